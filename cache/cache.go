@@ -0,0 +1,375 @@
+// Package cache stores fetched games on disk, one compressed file per
+// season date, so settled dates are never fetched twice.
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/internal/gzfile"
+	"github.com/corykiser/NCAA-Bayes-ELO/internal/migrate"
+)
+
+// SettleTime is how long after a date ends before its cached games are
+// considered final; late games and stat corrections can land past midnight
+const SettleTime = 12 * time.Hour
+
+// Entry represents one date's cached games
+type Entry struct {
+	Version   int        `json:"version"`
+	Season    int        `json:"season"`
+	Source    string     `json:"source"`
+	Date      string     `json:"date"`
+	FetchedAt time.Time  `json:"fetched_at"`
+	Games     []elo.Game `json:"games"`
+}
+
+// GameStore persists fetched games per season date
+type GameStore interface {
+	// Get returns a date's games if they were stored after the date settled
+	Get(season int, source string, date time.Time) ([]elo.Game, bool)
+	// Put replaces a date's stored games
+	Put(season int, source string, date time.Time, games []elo.Game) error
+	// Clear removes everything stored for a season
+	Clear(season int, source string) error
+}
+
+// RatingStore is implemented by stores that keep rating history
+type RatingStore interface {
+	SaveRatings(season int, source string, asOf time.Time, rankings []*elo.TeamRating) error
+}
+
+// Cache handles local storage of game data, one file per season date
+type Cache struct {
+	dir string
+}
+
+// Dir returns the directory the cache is stored in
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// DefaultDir returns the ncaa-bayes-elo directory in the user's cache directory
+func DefaultDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		// Fallback to current directory
+		cacheDir = "."
+	}
+	return filepath.Join(cacheDir, "ncaa-bayes-elo")
+}
+
+// New creates a cache in dir, or in the user's cache directory when dir is empty
+func New(dir string) (*Cache, error) {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return &Cache{dir: dir}, nil
+}
+
+// seasonDir returns the directory holding a season/source's date files
+func (c *Cache) seasonDir(season int, source string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%s_%d", source, season))
+}
+
+// cacheFile returns the path to the gzip-compressed cache file for a season/source date
+func (c *Cache) cacheFile(season int, source string, date time.Time) string {
+	return filepath.Join(c.seasonDir(season, source), date.Format("2006-01-02")+".json.gz")
+}
+
+// legacyCacheFile returns the path used by uncompressed cache files
+func (c *Cache) legacyCacheFile(season int, source string, date time.Time) string {
+	return strings.TrimSuffix(c.cacheFile(season, source, date), ".gz")
+}
+
+// Get retrieves a date's cached games if they were fetched after the date
+// settled, so results can no longer change
+func (c *Cache) Get(season int, source string, date time.Time) ([]elo.Game, bool) {
+	data, err := gzfile.ReadFileOr(c.cacheFile(season, source, date), c.legacyCacheFile(season, source, date))
+	if err != nil {
+		return nil, false
+	}
+
+	// Entries from a newer format are refetched rather than misread
+	data, err = migrate.Upgrade(data, CacheVersion, cacheMigrations)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+
+	// Dates fetched before they settled (e.g. today) must be refetched
+	settled := date.AddDate(0, 0, 1).Add(SettleTime)
+	if entry.FetchedAt.Before(settled) {
+		return nil, false
+	}
+
+	return entry.Games, true
+}
+
+// Put stores a date's games in the cache
+func (c *Cache) Put(season int, source string, date time.Time, games []elo.Game) error {
+	entry := Entry{
+		Version:   CacheVersion,
+		Season:    season,
+		Source:    source,
+		Date:      date.Format("2006-01-02"),
+		FetchedAt: time.Now(),
+		Games:     games,
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(c.seasonDir(season, source), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	path := c.cacheFile(season, source, date)
+	if err := gzfile.WriteFile(path, data); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	// The compressed copy supersedes any uncompressed one
+	os.Remove(c.legacyCacheFile(season, source, date))
+
+	return nil
+}
+
+// Clear removes cached data for a season
+func (c *Cache) Clear(season int, source string) error {
+	if err := os.RemoveAll(c.seasonDir(season, source)); err != nil {
+		return err
+	}
+
+	// Remove any whole-season file left by older versions
+	legacy := filepath.Join(c.dir, fmt.Sprintf("%s_%d.json", source, season))
+	if err := os.Remove(legacy); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ClearAll removes all cached data
+func (c *Cache) ClearAll() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		switch {
+		case entry.IsDir():
+			os.RemoveAll(filepath.Join(c.dir, entry.Name()))
+		case strings.HasSuffix(entry.Name(), ".json"), strings.HasSuffix(entry.Name(), ".json.gz"):
+			os.Remove(filepath.Join(c.dir, entry.Name()))
+		}
+	}
+	return nil
+}
+
+// HTTPDir returns the directory holding the HTTP response cache
+func (c *Cache) HTTPDir() string {
+	return filepath.Join(c.dir, "http")
+}
+
+// SeasonInfo summarizes the cached data for one season/source
+type SeasonInfo struct {
+	Source    string
+	Season    int
+	Dates     int
+	Bytes     int64
+	UpdatedAt time.Time
+}
+
+// Seasons lists the cached seasons, newest first
+func (c *Cache) Seasons() ([]SeasonInfo, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var seasons []SeasonInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		source, season, ok := parseSeasonDir(entry.Name())
+		if !ok {
+			continue
+		}
+
+		info := SeasonInfo{Source: source, Season: season}
+		files, err := os.ReadDir(filepath.Join(c.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			fi, err := f.Info()
+			if err != nil {
+				continue
+			}
+			info.Dates++
+			info.Bytes += fi.Size()
+			if fi.ModTime().After(info.UpdatedAt) {
+				info.UpdatedAt = fi.ModTime()
+			}
+		}
+		seasons = append(seasons, info)
+	}
+
+	sort.Slice(seasons, func(i, j int) bool {
+		if seasons[i].Season != seasons[j].Season {
+			return seasons[i].Season > seasons[j].Season
+		}
+		return seasons[i].Source < seasons[j].Source
+	})
+	return seasons, nil
+}
+
+// parseSeasonDir splits a season directory name like "espn_2025"
+func parseSeasonDir(name string) (string, int, bool) {
+	i := strings.LastIndex(name, "_")
+	if i <= 0 {
+		return "", 0, false
+	}
+	season, err := strconv.Atoi(name[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return name[:i], season, true
+}
+
+// Entries returns every cached date for a season/source in date order,
+// including dates that have not settled yet
+func (c *Cache) Entries(season int, source string) ([]Entry, error) {
+	files, err := os.ReadDir(c.seasonDir(season, source))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, f := range files {
+		data, err := gzfile.ReadFile(filepath.Join(c.seasonDir(season, source), f.Name()))
+		if err != nil {
+			continue
+		}
+		if data, err = migrate.Upgrade(data, CacheVersion, cacheMigrations); err != nil {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Date < entries[j].Date
+	})
+	return entries, nil
+}
+
+// RetentionPolicy limits how much data the cache keeps
+type RetentionPolicy struct {
+	KeepSeasons int   // Most recent seasons kept per source (0 = unlimited)
+	MaxBytes    int64 // Total cache size limit (0 = unlimited)
+}
+
+// PruneResult reports what a prune removed
+type PruneResult struct {
+	SeasonsRemoved int
+	FilesRemoved   int
+	BytesFreed     int64
+}
+
+// Prune enforces a retention policy. Seasons beyond KeepSeasons are removed
+// whole; then, while the cache exceeds MaxBytes, the least recently written
+// files are removed. The protected season/source (if any) is never removed.
+func (c *Cache) Prune(policy RetentionPolicy, protectSource string, protectSeason int) (PruneResult, error) {
+	var result PruneResult
+
+	seasons, err := c.Seasons()
+	if err != nil {
+		return result, err
+	}
+
+	if policy.KeepSeasons > 0 {
+		kept := make(map[string]int)
+		for _, s := range seasons {
+			kept[s.Source]++
+			if kept[s.Source] <= policy.KeepSeasons {
+				continue
+			}
+			if s.Source == protectSource && s.Season == protectSeason {
+				continue
+			}
+			if err := os.RemoveAll(c.seasonDir(s.Season, s.Source)); err != nil {
+				return result, err
+			}
+			result.SeasonsRemoved++
+			result.FilesRemoved += s.Dates
+			result.BytesFreed += s.Bytes
+		}
+	}
+
+	if policy.MaxBytes > 0 {
+		type cachedFile struct {
+			path    string
+			size    int64
+			modTime time.Time
+		}
+		var files []cachedFile
+		var total int64
+		protectDir := c.seasonDir(protectSeason, protectSource)
+
+		filepath.WalkDir(c.dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			fi, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			total += fi.Size()
+			if protectSource != "" && filepath.Dir(path) == protectDir {
+				return nil
+			}
+			files = append(files, cachedFile{path: path, size: fi.Size(), modTime: fi.ModTime()})
+			return nil
+		})
+
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].modTime.Before(files[j].modTime)
+		})
+		for _, f := range files {
+			if total <= policy.MaxBytes {
+				break
+			}
+			if err := os.Remove(f.path); err != nil {
+				continue
+			}
+			total -= f.size
+			result.FilesRemoved++
+			result.BytesFreed += f.size
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"github.com/corykiser/NCAA-Bayes-ELO/internal/migrate"
+)
+
+// CacheVersion is the format version of cache entries
+const CacheVersion = 1
+
+// cacheMigrations[v] upgrades a cache entry from version v to v+1
+var cacheMigrations = []migrate.Step{
+	migrateCacheV0,
+}
+
+// migrateCacheV0 upgrades cache entries saved before games recorded their
+// live state, deriving it from the completion flag
+func migrateCacheV0(doc map[string]any) error {
+	games, _ := doc["games"].([]any)
+	for _, g := range games {
+		game, ok := g.(map[string]any)
+		if !ok {
+			continue
+		}
+		if state, _ := game["State"].(string); state != "" {
+			continue
+		}
+		if completed, _ := game["Completed"].(bool); completed {
+			game["State"] = "post"
+		} else {
+			game["State"] = "pre"
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetMigratesEntries(t *testing.T) {
+	date := time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
+	const games = `"games": [
+		{"ID": "1", "Completed": true},
+		{"ID": "2", "Completed": false},
+		{"ID": "3", "Completed": false, "State": "in"}
+	]`
+	tests := []struct {
+		name       string
+		entry      string
+		wantOK     bool
+		wantStates []string
+	}{
+		{"unversioned", `{"fetched_at": "2025-02-01T00:00:00Z", ` + games + `}`, true, []string{"post", "pre", "in"}},
+		{"version 0", `{"version": 0, "fetched_at": "2025-02-01T00:00:00Z", ` + games + `}`, true, []string{"post", "pre", "in"}},
+		{"current is read as is", `{"version": 1, "fetched_at": "2025-02-01T00:00:00Z", ` + games + `}`, true, []string{"", "", "in"}},
+		{"newer version is refetched", `{"version": 2, "fetched_at": "2025-02-01T00:00:00Z", ` + games + `}`, false, nil},
+		{"fetched before settling", `{"fetched_at": "2025-01-15T06:00:00Z", ` + games + `}`, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Entries from before the format was versioned were stored uncompressed
+			path := c.legacyCacheFile(2025, "espn", date)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(tt.entry), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, ok := c.Get(2025, "espn", date)
+			if ok != tt.wantOK {
+				t.Fatalf("Get ok = %v, want %v", ok, tt.wantOK)
+			}
+			if len(got) != len(tt.wantStates) {
+				t.Fatalf("Get returned %d games, want %d", len(got), len(tt.wantStates))
+			}
+			for i, g := range got {
+				if g.State != tt.wantStates[i] {
+					t.Errorf("game %s state = %q, want %q", g.ID, g.State, tt.wantStates[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// teamAliases maps informal team names, normalized, to team IDs. findTeam
+// checks it before matching names.
+var teamAliases map[string]string
+
+// aliasesPath returns the aliases file to read: the one given, or the default
+// in the user config dir, reporting whether it was given explicitly
+func aliasesPath(path string) (string, bool) {
+	if path != "" {
+		return path, true
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	for _, name := range []string{"aliases.yaml", "aliases.yml", "aliases.toml"} {
+		path := filepath.Join(dir, "ncaa-bayes-elo", name)
+		if _, err := os.Stat(path); err == nil {
+			return path, false
+		}
+	}
+	return "", false
+}
+
+// loadAliases reads a YAML or TOML aliases file mapping names to team IDs.
+// Since sources number teams differently, aliases may also be grouped in a
+// section per source ("espn", "ncaa"); the source's section overrides the
+// top-level entries.
+func loadAliases(path, source string) (map[string]string, error) {
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make(map[string]string)
+	add := func(name string, id any) error {
+		if id == nil {
+			return nil
+		}
+		if _, ok := id.(map[string]any); ok {
+			return fmt.Errorf("alias %q: expected a team ID", name)
+		}
+		if key := normalizeName(name); key != "" {
+			aliases[key] = fmt.Sprint(id)
+		}
+		return nil
+	}
+	for name, value := range config {
+		if _, ok := value.(map[string]any); !ok {
+			if err := add(name, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if section, ok := config[source].(map[string]any); ok {
+		for name, value := range section {
+			if err := add(name, value); err != nil {
+				return nil, fmt.Errorf("%s: %w", source, err)
+			}
+		}
+	}
+	return aliases, nil
+}
+
+// useAliases loads the aliases file for findTeam, if there is one
+func useAliases(path, source string) error {
+	path, explicit := aliasesPath(path)
+	if path == "" {
+		return nil
+	}
+	aliases, err := loadAliases(path, source)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("aliases %s: %w", path, err)
+	}
+	teamAliases = aliases
+	return nil
+}
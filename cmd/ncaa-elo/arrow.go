@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// writeArrow writes rows as an Arrow IPC file, or as an IPC stream when
+// stream is set. Columns are named by each field's json tag.
+func writeArrow[T any](path string, rows []T, stream bool) error {
+	record, err := arrowRecord(rows)
+	if err != nil {
+		return err
+	}
+	defer record.Release()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create arrow file: %w", err)
+	}
+	defer f.Close()
+
+	if stream {
+		w := ipc.NewWriter(f, ipc.WithSchema(record.Schema()))
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("failed to write arrow stream: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return fmt.Errorf("failed to write arrow stream: %w", err)
+		}
+	} else {
+		w, err := ipc.NewFileWriter(f, ipc.WithSchema(record.Schema()))
+		if err != nil {
+			return fmt.Errorf("failed to create arrow file: %w", err)
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("failed to write arrow file: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return fmt.Errorf("failed to write arrow file: %w", err)
+		}
+	}
+
+	return f.Close()
+}
+
+// arrowRecord builds a single record batch from a slice of flat structs
+// with string, int, float64, and bool fields
+func arrowRecord[T any](rows []T) (arrow.Record, error) {
+	rowType := reflect.TypeOf((*T)(nil)).Elem()
+
+	var fields []arrow.Field
+	for i := 0; i < rowType.NumField(); i++ {
+		field := rowType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		var dataType arrow.DataType
+		switch field.Type.Kind() {
+		case reflect.String:
+			dataType = arrow.BinaryTypes.String
+		case reflect.Int:
+			dataType = arrow.PrimitiveTypes.Int64
+		case reflect.Float64:
+			dataType = arrow.PrimitiveTypes.Float64
+		case reflect.Bool:
+			dataType = arrow.FixedWidthTypes.Boolean
+		default:
+			return nil, fmt.Errorf("unsupported arrow column type %s for %s", field.Type, field.Name)
+		}
+		fields = append(fields, arrow.Field{Name: name, Type: dataType})
+	}
+
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, arrow.NewSchema(fields, nil))
+	defer builder.Release()
+
+	for _, row := range rows {
+		v := reflect.ValueOf(row)
+		for i := range fields {
+			switch b := builder.Field(i).(type) {
+			case *array.StringBuilder:
+				b.Append(v.Field(i).String())
+			case *array.Int64Builder:
+				b.Append(v.Field(i).Int())
+			case *array.Float64Builder:
+				b.Append(v.Field(i).Float())
+			case *array.BooleanBuilder:
+				b.Append(v.Field(i).Bool())
+			}
+		}
+	}
+
+	return builder.NewRecord(), nil
+}
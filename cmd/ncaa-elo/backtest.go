@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// BacktestBand is the calibration of predictions whose favorite had a win
+// probability in [Low, High)
+type BacktestBand struct {
+	Low            float64 `json:"low"`
+	High           float64 `json:"high"`
+	Games          int     `json:"games"`
+	Predicted      float64 `json:"predicted"`        // Mean favorite win probability
+	FavoriteWinPct float64 `json:"favorite_win_pct"` // Share of games the favorite won
+}
+
+// BacktestResult scores the pre-game predictions recorded in a game log
+type BacktestResult struct {
+	Since    string         `json:"since,omitempty"`
+	Until    string         `json:"until,omitempty"`
+	Games    int            `json:"games"`
+	Accuracy float64        `json:"accuracy"` // Favorite won; toss-ups count half
+	Brier    float64        `json:"brier"`
+	LogLoss  float64        `json:"log_loss"`
+	Bands    []BacktestBand `json:"calibration"`
+}
+
+// backtestCommand implements the backtest command. Each game log entry holds the
+// win probability from the ratings before that game day, so scoring them is a
+// walk-forward test with no lookahead.
+func backtestCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	since := fs.String("since", "", "Score games on or after this date (YYYY-MM-DD), e.g. to skip the noisy early season")
+	until := fs.String("until", "", "Score games on or before this date (YYYY-MM-DD)")
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	return func() {
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+
+		elo := engine.mustLoad(ctx)
+		result := backtest(elo.GameLog, *since, *until)
+		if result.Games == 0 {
+			fmt.Println("No games in the selected range")
+			return
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Print(formatBacktest(result))
+		}
+	}
+}
+
+// backtest scores the game log's predictions between two dates (either may
+// be empty for no limit)
+func backtest(log []model.GameResult, since, until string) BacktestResult {
+	result := BacktestResult{Since: since, Until: until}
+	for tenth := 5; tenth < 10; tenth++ {
+		result.Bands = append(result.Bands, BacktestBand{Low: float64(tenth) / 10, High: float64(tenth+1) / 10})
+	}
+
+	var correct, brier, logLoss float64
+	for _, g := range log {
+		if (since != "" && g.Date < since) || (until != "" && g.Date > until) {
+			continue
+		}
+		result.Games++
+
+		// WinProb is the actual winner's probability, so the outcome is always 1
+		p := g.WinProb
+		switch {
+		case p > 0.5:
+			correct++
+		case p == 0.5:
+			correct += 0.5
+		}
+		brier += (1 - p) * (1 - p)
+		logLoss -= math.Log(math.Max(p, 1e-15))
+
+		favorite := math.Max(p, 1-p)
+		band := &result.Bands[min(int((favorite-0.5)*10), len(result.Bands)-1)]
+		band.Games++
+		band.Predicted += favorite
+		if p > 0.5 {
+			band.FavoriteWinPct++
+		}
+	}
+	if result.Games == 0 {
+		return result
+	}
+
+	n := float64(result.Games)
+	result.Accuracy, result.Brier, result.LogLoss = correct/n, brier/n, logLoss/n
+	for i := range result.Bands {
+		if b := &result.Bands[i]; b.Games > 0 {
+			b.Predicted /= float64(b.Games)
+			b.FavoriteWinPct /= float64(b.Games)
+		}
+	}
+	return result
+}
+
+// formatBacktest renders backtest scores and the calibration table
+func formatBacktest(r BacktestResult) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("\nBacktest of %d pre-game predictions", r.Games))
+	if r.Since != "" || r.Until != "" {
+		sb.WriteString(fmt.Sprintf(" (%s to %s)", valueOr(r.Since, "start"), valueOr(r.Until, "end")))
+	}
+	sb.WriteString("\n" + strings.Repeat("=", 50) + "\n")
+	sb.WriteString(fmt.Sprintf("Accuracy:  %.1f%%\n", r.Accuracy*100))
+	sb.WriteString(fmt.Sprintf("Brier:     %.4f  (0.25 = coin flip)\n", r.Brier))
+	sb.WriteString(fmt.Sprintf("Log loss:  %.4f  (0.693 = coin flip)\n", r.LogLoss))
+
+	sb.WriteString("\nCalibration by favorite's win probability\n")
+	sb.WriteString(fmt.Sprintf("%-10s %8s %12s %12s\n", "Band", "Games", "Predicted", "Actual"))
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	for _, b := range r.Bands {
+		if b.Games == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%-10s %8d %11.1f%% %11.1f%%\n",
+			fmt.Sprintf("%.0f-%.0f%%", b.Low*100, b.High*100), b.Games, b.Predicted*100, b.FavoriteWinPct*100))
+	}
+	return sb.String()
+}
+
+// valueOr returns s, or def if s is empty
+func valueOr(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
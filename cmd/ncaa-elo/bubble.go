@@ -0,0 +1,434 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// BubbleTeam is a team near the cut line for the tournament's at-large bids
+type BubbleTeam struct {
+	AtLargeRank int     `json:"at_large_rank"` // Place in the projected at-large order
+	TeamID      string  `json:"team_id"`
+	TeamName    string  `json:"team_name"`
+	Conference  string  `json:"conference,omitempty"`
+	MeanELO     float64 `json:"mean_elo"`
+	Wins        int     `json:"wins"`
+	Losses      int     `json:"losses"`
+	Top50Wins   int     `json:"top50_wins"` // Against the current top 50
+	Top50Losses int     `json:"top50_losses"`
+	WAB         float64 `json:"wins_above_bubble"`
+	ProbAuto    float64 `json:"prob_auto_bid"`
+	ProbAtLarge float64 `json:"prob_at_large"`
+	ProbField   float64 `json:"prob_in_field"`
+}
+
+// BubbleWatch is the last teams projected into the tournament field on
+// at-large bids and the first left out
+type BubbleWatch struct {
+	Season    int          `json:"season"`
+	Field     int          `json:"field"`
+	AutoBids  int          `json:"auto_bids"`
+	AtLarge   int          `json:"at_large_bids"`
+	BubbleELO float64      `json:"bubble_elo"` // The rating WAB is measured against
+	Remaining int          `json:"remaining_games"`
+	Sims      int          `json:"simulations"`
+	Seed      uint64       `json:"seed"`
+	LastIn    []BubbleTeam `json:"last_in"`   // Safest first
+	FirstOut  []BubbleTeam `json:"first_out"` // Closest first
+}
+
+// Bubble watch defaults, shared with the daemon
+const (
+	defaultFieldSize  = 68
+	defaultBubbleRank = 45
+	defaultBubbleShow = 8
+)
+
+// bubbleCommand implements the bubble command: simulate the rest of the season
+// and the conference tournaments, then list the teams either side of the
+// at-large cut line with their chances of making the field
+func bubbleCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	sims := fs.Int("n", 10000, "Number of simulated seasons")
+	field := fs.Int("field", defaultFieldSize, "Teams in the tournament field")
+	bubbleRank := fs.Int("bubble-rank", defaultBubbleRank, "Rank of the bubble team that wins above bubble are measured against")
+	show := fs.Int("show", defaultBubbleShow, "Teams to list on each side of the cut line")
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	seed := registerSeedFlag(fs)
+	return func() {
+		if *sims < 1 || *field < 1 || *bubbleRank < 1 || *show < 1 {
+			fmt.Fprintln(os.Stderr, "Error: -n, -field, -bubble-rank, and -show must be at least 1")
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+
+		elo := engine.mustLoad(ctx)
+		schedule, err := remainingSchedule(ctx, elo, engine)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching schedule: %v\n", err)
+			os.Exit(1)
+		}
+
+		rng, usedSeed := newRNG(*seed)
+		watch, err := bubbleWatch(elo, schedule, *field, *bubbleRank, *sims, *show, rng)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		watch.Season, watch.Seed = *engine.season, usedSeed
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(watch, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Print(formatBubbleWatch(watch))
+		}
+	}
+}
+
+// winsAboveBubble scores each team's results against what a team rated
+// bubbleELO would expect from the same games at the same sites, using the
+// opponents' current ratings
+func winsAboveBubble(elo *model.BayesianELO, bubbleELO float64) map[string]float64 {
+	wab := make(map[string]float64)
+	for _, g := range elo.GameLog {
+		winner, okW := elo.Teams[g.WinnerID]
+		loser, okL := elo.Teams[g.LoserID]
+		if !okW || !okL {
+			continue
+		}
+		edge := 0.0 // The winner's home court edge
+		switch g.HomeAdvantage {
+		case "H":
+			edge = elo.HomeAdvantage
+		case "A":
+			edge = -elo.HomeAdvantage
+		}
+		wab[g.WinnerID] += 1 - elo.WinProbability(bubbleELO+edge-loser.Dist.Mean())
+		wab[g.LoserID] -= elo.WinProbability(bubbleELO - edge - winner.Dist.Mean())
+	}
+	return wab
+}
+
+// bracketOrder lists the seeds (0 the best) in bracket position order for a
+// single-elimination bracket of size teams, a power of two, so that the top
+// seeds meet as late as possible
+func bracketOrder(size int) []int {
+	order := []int{0}
+	for len(order) < size {
+		n := 2 * len(order)
+		next := make([]int, 0, n)
+		for _, s := range order {
+			next = append(next, s, n-1-s)
+		}
+		order = next
+	}
+	return order
+}
+
+// bubbleWatch plays the remaining schedule n times, then each conference's
+// tournament: a neutral-site bracket of every member seeded by wins, whose
+// winner takes the automatic bid. The at-large bids go to the other teams
+// with the most wins above bubble. Teams are projected in or out by their
+// chance of an at-large bid when they miss the automatic one, after setting
+// aside each conference's likeliest champion.
+func bubbleWatch(elo *model.BayesianELO, schedule []model.Game, field, bubbleRank, n, show int, rng *rand.Rand) (BubbleWatch, error) {
+	rankings := elo.GetRankings()
+	if len(rankings) == 0 {
+		return BubbleWatch{}, fmt.Errorf("no teams have been rated")
+	}
+	index := make(map[string]int, len(rankings))
+	means := make([]float64, len(rankings))
+	for i, team := range rankings {
+		index[team.TeamID] = i
+		means[i] = team.Dist.Mean()
+	}
+	bubbleELO := means[min(bubbleRank, len(rankings))-1]
+
+	members := make(map[string][]int)
+	for i, team := range rankings {
+		if team.Conference != "" {
+			members[team.Conference] = append(members[team.Conference], i)
+		}
+	}
+	conferences := make([]string, 0, len(members))
+	for conference := range members {
+		conferences = append(conferences, conference)
+	}
+	sort.Strings(conferences)
+	if len(conferences) == 0 {
+		fmt.Fprintln(os.Stderr, "Warning: no conferences are known, so every bid is treated as at-large")
+	}
+	if len(conferences) > field {
+		return BubbleWatch{}, fmt.Errorf("a field of %d can't hold %d automatic bids", field, len(conferences))
+	}
+	atLarge := field - len(conferences)
+
+	records := model.Records(elo.GameLog)
+	baseWins := make([]int, len(rankings))
+	baseWAB := make([]float64, len(rankings))
+	wab := winsAboveBubble(elo, bubbleELO)
+	for i, team := range rankings {
+		baseWins[i] = records[team.TeamID].Wins
+		baseWAB[i] = wab[team.TeamID]
+	}
+
+	// A bubble team's chance of beating each team at a neutral site, for
+	// scoring conference tournament games
+	neutralBubble := make([]float64, len(rankings))
+	for i := range rankings {
+		neutralBubble[i] = elo.WinProbability(bubbleELO - means[i])
+	}
+
+	type simGame struct {
+		home, away             int
+		prob                   float64 // Home win probability
+		homeBubble, awayBubble float64 // A bubble team's chance of winning in each side's place
+	}
+	var games []simGame
+	for _, g := range schedule {
+		home, okHome := index[g.HomeTeamID]
+		away, okAway := index[g.AwayTeamID]
+		if !okHome || !okAway {
+			continue
+		}
+		edge := elo.HomeAdvantage
+		if g.NeutralSite {
+			edge = 0
+		}
+		prob, _ := elo.PredictMatchupAt(g.HomeTeamID, g.AwayTeamID, edge)
+		games = append(games, simGame{
+			home:       home,
+			away:       away,
+			prob:       prob,
+			homeBubble: elo.WinProbability(bubbleELO + edge - means[away]),
+			awayBubble: elo.WinProbability(bubbleELO - edge - means[home]),
+		})
+	}
+	if skipped := len(schedule) - len(games); skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: skipped %d games involving teams without ratings\n", skipped)
+	}
+
+	brackets := make(map[string][]int, len(conferences))
+	for _, conference := range conferences {
+		size := 1
+		for size < len(members[conference]) {
+			size *= 2
+		}
+		brackets[conference] = bracketOrder(size)
+	}
+
+	wins := make([]int, len(rankings))
+	simWAB := make([]float64, len(rankings))
+	auto := make([]bool, len(rankings))
+	autoCounts := make([]int, len(rankings))
+	atLargeCounts := make([]int, len(rankings))
+	order := make([]int, len(rankings))
+	for s := 0; s < n; s++ {
+		copy(wins, baseWins)
+		copy(simWAB, baseWAB)
+		clear(auto)
+		for _, g := range games {
+			if rng.Float64() < g.prob {
+				wins[g.home]++
+				simWAB[g.home] += 1 - g.homeBubble
+				simWAB[g.away] -= g.awayBubble
+			} else {
+				wins[g.away]++
+				simWAB[g.away] += 1 - g.awayBubble
+				simWAB[g.home] -= g.homeBubble
+			}
+		}
+
+		for _, conference := range conferences {
+			seeded := append([]int(nil), members[conference]...)
+			sort.SliceStable(seeded, func(i, j int) bool { return wins[seeded[i]] > wins[seeded[j]] })
+			slots := make([]int, len(brackets[conference]))
+			for pos, seed := range brackets[conference] {
+				slots[pos] = -1 // A bye
+				if seed < len(seeded) {
+					slots[pos] = seeded[seed]
+				}
+			}
+			for len(slots) > 1 {
+				next := slots[:0]
+				for i := 0; i < len(slots); i += 2 {
+					a, b := slots[i], slots[i+1]
+					switch {
+					case b < 0:
+						next = append(next, a)
+					case a < 0:
+						next = append(next, b)
+					case rng.Float64() < elo.WinProbability(means[a]-means[b]):
+						simWAB[a] += 1 - neutralBubble[b]
+						simWAB[b] -= neutralBubble[a]
+						next = append(next, a)
+					default:
+						simWAB[b] += 1 - neutralBubble[a]
+						simWAB[a] -= neutralBubble[b]
+						next = append(next, b)
+					}
+				}
+				slots = next
+			}
+			auto[slots[0]] = true
+			autoCounts[slots[0]]++
+		}
+
+		for t := range order {
+			order[t] = t
+		}
+		sort.Slice(order, func(i, j int) bool { return simWAB[order[i]] > simWAB[order[j]] })
+		picked := 0
+		for _, t := range order {
+			if picked == atLarge {
+				break
+			}
+			if !auto[t] {
+				atLargeCounts[t]++
+				picked++
+			}
+		}
+	}
+
+	// Set aside each conference's likeliest champion, then cut the rest by
+	// their chance of an at-large bid
+	projectedAuto := make(map[int]bool, len(conferences))
+	for _, conference := range conferences {
+		best := members[conference][0]
+		for _, t := range members[conference] {
+			if autoCounts[t] > autoCounts[best] {
+				best = t
+			}
+		}
+		projectedAuto[best] = true
+	}
+	var candidates []int
+	for t := range rankings {
+		if !projectedAuto[t] {
+			candidates = append(candidates, t)
+		}
+	}
+	standing := func(t int) float64 {
+		if autoCounts[t] == n {
+			return 1
+		}
+		return float64(atLargeCounts[t]) / float64(n-autoCounts[t])
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if standing(a) != standing(b) {
+			return standing(a) > standing(b)
+		}
+		return baseWAB[a] > baseWAB[b]
+	})
+
+	top50 := make(map[string]bool)
+	for _, team := range rankings[:min(50, len(rankings))] {
+		top50[team.TeamID] = true
+	}
+	top50Records := make(map[string]model.Record)
+	for _, g := range elo.GameLog {
+		if top50[g.LoserID] {
+			r := top50Records[g.WinnerID]
+			r.Wins++
+			top50Records[g.WinnerID] = r
+		}
+		if top50[g.WinnerID] {
+			r := top50Records[g.LoserID]
+			r.Losses++
+			top50Records[g.LoserID] = r
+		}
+	}
+
+	bubbleTeam := func(place int) BubbleTeam {
+		t := candidates[place]
+		team := rankings[t]
+		rec, top := records[team.TeamID], top50Records[team.TeamID]
+		return BubbleTeam{
+			AtLargeRank: place + 1,
+			TeamID:      team.TeamID,
+			TeamName:    team.TeamName,
+			Conference:  team.Conference,
+			MeanELO:     means[t],
+			Wins:        rec.Wins,
+			Losses:      rec.Losses,
+			Top50Wins:   top.Wins,
+			Top50Losses: top.Losses,
+			WAB:         baseWAB[t],
+			ProbAuto:    float64(autoCounts[t]) / float64(n),
+			ProbAtLarge: float64(atLargeCounts[t]) / float64(n),
+			ProbField:   float64(autoCounts[t]+atLargeCounts[t]) / float64(n),
+		}
+	}
+	watch := BubbleWatch{
+		Field:     field,
+		AutoBids:  len(conferences),
+		AtLarge:   atLarge,
+		BubbleELO: bubbleELO,
+		Remaining: len(games),
+		Sims:      n,
+		LastIn:    []BubbleTeam{},
+		FirstOut:  []BubbleTeam{},
+	}
+	cut := min(atLarge, len(candidates))
+	for place := max(0, cut-show); place < cut; place++ {
+		watch.LastIn = append(watch.LastIn, bubbleTeam(place))
+	}
+	for place := cut; place < min(cut+show, len(candidates)); place++ {
+		watch.FirstOut = append(watch.FirstOut, bubbleTeam(place))
+	}
+	return watch, nil
+}
+
+// formatBubbleWatch renders the bubble watch as a text table
+func formatBubbleWatch(w BubbleWatch) string {
+	var sb strings.Builder
+	width := 118
+
+	sb.WriteString(fmt.Sprintf("\nBubble Watch (%d-%d Season)\n", w.Season-1, w.Season))
+	sb.WriteString(fmt.Sprintf("%d-team field: %d automatic bids, %d at-large. %d remaining games and the conference tournaments simulated %d times (seed %d)\n",
+		w.Field, w.AutoBids, w.AtLarge, w.Remaining, w.Sims, w.Seed))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	for _, section := range []struct {
+		title string
+		teams []BubbleTeam
+	}{{"Last In", w.LastIn}, {"First Out", w.FirstOut}} {
+		sb.WriteString(section.title + "\n")
+		sb.WriteString(fmt.Sprintf("%-4s %-26s %-12s %7s %7s %7s %8s %7s %9s %7s\n",
+			"Rk", "Team", "Conf", "Record", "Top 50", "WAB", "ELO", "Auto", "At-Large", "Field"))
+		sb.WriteString(strings.Repeat("-", width) + "\n")
+		if len(section.teams) == 0 {
+			sb.WriteString("  (none)\n")
+		}
+		for _, t := range section.teams {
+			sb.WriteString(fmt.Sprintf("%-4d %-26s %-12s %7s %7s %+7.2f %8.1f %6.1f%% %8.1f%% %6.1f%%\n",
+				t.AtLargeRank,
+				output.Truncate(t.TeamName, 26),
+				output.Truncate(t.Conference, 12),
+				fmt.Sprintf("%d-%d", t.Wins, t.Losses),
+				fmt.Sprintf("%d-%d", t.Top50Wins, t.Top50Losses),
+				t.WAB,
+				t.MeanELO,
+				t.ProbAuto*100,
+				t.ProbAtLarge*100,
+				t.ProbField*100))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("WAB is wins above bubble: wins beyond what a team rated %.1f would expect from the same games.\n", w.BubbleELO))
+	sb.WriteString("Rk is the place in the projected at-large order, after each conference's likeliest champion.\n")
+	return sb.String()
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/cache"
+)
+
+// openGameStore opens the configured game store: PostgreSQL when a DSN is
+// given, otherwise the local file cache. A file cache that can't be created
+// is reported and treated as no store.
+func openGameStore(ctx context.Context, f *cacheFlags) (cache.GameStore, error) {
+	if *f.postgres != "" {
+		store, err := NewPostgresStore(ctx, *f.postgres)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+
+	c, err := cache.New(f.cacheDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not initialize cache: %v\n", err)
+		return nil, nil
+	}
+	return c, nil
+}
+
+// cacheFlags holds the command line switches controlling cache use
+type cacheFlags struct {
+	noCache     *bool
+	refresh     *bool
+	clear       *bool
+	dir         *string
+	keepSeasons *int
+	maxMB       *int
+	postgres    *string
+}
+
+// registerCacheFlags adds cache control flags to a flag set
+func registerCacheFlags(fs *flag.FlagSet) *cacheFlags {
+	return &cacheFlags{
+		noCache:     fs.Bool("no-cache", false, "Bypass the cache entirely: don't read or write cached data"),
+		refresh:     fs.Bool("refresh", false, "Ignore cached data and fetch fresh, then update the cache"),
+		clear:       fs.Bool("clear-cache", false, "Clear cached data before running"),
+		dir:         fs.String("cache-dir", "", "Cache directory (default: user cache dir/ncaa-bayes-elo)"),
+		keepSeasons: fs.Int("cache-keep-seasons", 0, "Keep only the N most recent cached seasons per source (0 = unlimited)"),
+		maxMB:       fs.Int("cache-max-mb", 0, "Prune least recently written cache files beyond this many MB (0 = unlimited)"),
+		postgres:    fs.String("postgres", os.Getenv("NCAA_ELO_POSTGRES_DSN"), "PostgreSQL DSN for shared game and rating storage instead of the file cache (env: NCAA_ELO_POSTGRES_DSN)"),
+	}
+}
+
+// cacheDir returns the configured cache directory
+func (f *cacheFlags) cacheDir() string {
+	if *f.dir != "" {
+		return *f.dir
+	}
+	return cache.DefaultDir()
+}
+
+// policy returns the configured retention policy
+func (f *cacheFlags) policy() cache.RetentionPolicy {
+	return cache.RetentionPolicy{
+		KeepSeasons: *f.keepSeasons,
+		MaxBytes:    int64(*f.maxMB) * 1024 * 1024,
+	}
+}
+
+// readable reports whether cached data may be used
+func (f *cacheFlags) readable() bool {
+	return !*f.noCache && !*f.refresh
+}
+
+// writable reports whether freshly fetched data should be cached
+func (f *cacheFlags) writable() bool {
+	return !*f.noCache
+}
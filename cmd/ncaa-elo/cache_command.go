@@ -0,0 +1,176 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/cache"
+)
+
+// cacheTargets are the cache subcommands: list, inspect, and prune
+var cacheTargets = []command{
+	{name: "list", flags: cacheListCommand},
+	{name: "inspect", flags: cacheInspectCommand},
+	{name: "prune", flags: cachePruneCommand},
+}
+
+// registerCacheDirFlag adds the -cache-dir flag every cache subcommand takes
+func registerCacheDirFlag(fs *flag.FlagSet) *string {
+	return fs.String("cache-dir", "", "Cache directory (default: user cache dir/ncaa-bayes-elo)")
+}
+
+// cacheListCommand implements cache list
+func cacheListCommand(fs *flag.FlagSet) func() {
+	cacheDir := registerCacheDirFlag(fs)
+	return func() {
+		listCache(openCache(*cacheDir))
+	}
+}
+
+// cacheInspectCommand implements cache inspect
+func cacheInspectCommand(fs *flag.FlagSet) func() {
+	cacheDir := registerCacheDirFlag(fs)
+	dataSource := fs.String("source", "espn", "Data source: "+sourceChoices())
+	season := fs.Int("season", 2025, "Season year (e.g., 2025 for 2024-2025 season)")
+	return func() {
+		inspectCache(openCache(*cacheDir), *dataSource, *season)
+	}
+}
+
+// cachePruneCommand implements cache prune
+func cachePruneCommand(fs *flag.FlagSet) func() {
+	cacheDir := registerCacheDirFlag(fs)
+	keepSeasons := fs.Int("keep-seasons", 0, "Keep only the N most recent seasons per source (0 = unlimited)")
+	maxMB := fs.Int("max-mb", 0, "Remove least recently written files beyond this many MB (0 = unlimited)")
+	return func() {
+		if *keepSeasons <= 0 && *maxMB <= 0 {
+			fmt.Fprintln(os.Stderr, "Nothing to prune: set -keep-seasons and/or -max-mb")
+			os.Exit(1)
+		}
+
+		c := openCache(*cacheDir)
+		result, err := c.Prune(cache.RetentionPolicy{
+			KeepSeasons: *keepSeasons,
+			MaxBytes:    int64(*maxMB) * 1024 * 1024,
+		}, "", 0)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error pruning cache: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed %d seasons and %d files, freeing %s\n",
+			result.SeasonsRemoved, result.FilesRemoved, formatBytes(result.BytesFreed))
+	}
+}
+
+// openCache opens the cache or exits with an error
+func openCache(dir string) *cache.Cache {
+	c, err := cache.New(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening cache: %v\n", err)
+		os.Exit(1)
+	}
+	return c
+}
+
+// listCache prints a summary of each cached season and the HTTP cache
+func listCache(c *cache.Cache) {
+	seasons, err := c.Seasons()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading cache: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Cache directory: %s\n\n", c.Dir())
+	fmt.Printf("%-8s %-9s %6s %10s  %s\n", "Source", "Season", "Dates", "Size", "Updated")
+	fmt.Println(strings.Repeat("-", 55))
+
+	var total int64
+	for _, s := range seasons {
+		label := fmt.Sprintf("%d-%02d", s.Season-1, s.Season%100)
+		fmt.Printf("%-8s %-9s %6d %10s  %s\n",
+			s.Source, label, s.Dates, formatBytes(s.Bytes), s.UpdatedAt.Format("2006-01-02 15:04"))
+		total += s.Bytes
+	}
+
+	httpFiles, httpBytes := dirUsage(c.HTTPDir())
+	if httpFiles > 0 {
+		fmt.Printf("%-8s %-9s %6d %10s\n", "http", "-", httpFiles, formatBytes(httpBytes))
+		total += httpBytes
+	}
+
+	fmt.Println(strings.Repeat("-", 55))
+	fmt.Printf("Total: %s\n", formatBytes(total))
+}
+
+// inspectCache prints each cached date for a season
+func inspectCache(c *cache.Cache, dataSource string, season int) {
+	entries, err := c.Entries(season, dataSource)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Printf("No cached data for %s %d\n", dataSource, season)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error reading cache: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s %d-%d season\n\n", dataSource, season-1, season)
+	fmt.Printf("%-10s %6s %9s  %-16s %s\n", "Date", "Games", "Completed", "Fetched", "Status")
+	fmt.Println(strings.Repeat("-", 60))
+
+	totalGames := 0
+	for _, entry := range entries {
+		completed := 0
+		for _, g := range entry.Games {
+			if g.Completed {
+				completed++
+			}
+		}
+		totalGames += len(entry.Games)
+
+		status := "settled"
+		if date, err := time.Parse("2006-01-02", entry.Date); err == nil &&
+			entry.FetchedAt.Before(date.AddDate(0, 0, 1).Add(cache.SettleTime)) {
+			status = "refetch"
+		}
+
+		fmt.Printf("%-10s %6d %9d  %-16s %s\n",
+			entry.Date, len(entry.Games), completed, entry.FetchedAt.Format("2006-01-02 15:04"), status)
+	}
+
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("%d dates, %d games\n", len(entries), totalGames)
+}
+
+// dirUsage returns the number of files and total bytes under dir
+func dirUsage(dir string) (int, int64) {
+	var files int
+	var bytes int64
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if fi, err := d.Info(); err == nil {
+			files++
+			bytes += fi.Size()
+		}
+		return nil
+	})
+	return files, bytes
+}
+
+// formatBytes renders a byte count with a binary unit
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
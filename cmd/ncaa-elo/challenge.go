@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// ChallengeGame is one game of a conference challenge
+type ChallengeGame struct {
+	TeamID       string  `json:"team_id"`
+	TeamName     string  `json:"team_name"`
+	OpponentID   string  `json:"opponent_id"`
+	OpponentName string  `json:"opponent_name"`
+	WinProb      float64 `json:"win_prob"`
+}
+
+// ChallengeResult is a conference challenge's projection, from the first
+// conference's side
+type ChallengeResult struct {
+	Conference           string          `json:"conference"`
+	Opponent             string          `json:"opponent"`
+	Games                []ChallengeGame `json:"games"`
+	ExpectedWins         float64         `json:"expected_wins"`
+	OpponentExpectedWins float64         `json:"opponent_expected_wins"`
+	WinProb              float64         `json:"win_prob"`  // Winning more than half the games
+	TieProb              float64         `json:"tie_prob"`  // Splitting the games evenly
+	LoseProb             float64         `json:"lose_prob"` // Winning fewer than half
+}
+
+// challengeCommand implements the challenge command: project a conference vs
+// conference event, every member against every other or as paired in a file
+func challengeCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	pairingsFile := fs.String("pairings", "", "CSV of the event's games, one 'team,team' pair per line (default: every member plays every member)")
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ncaa-bayes-elo challenge [flags] <conference> <conference>")
+		fs.PrintDefaults()
+	}
+	return func() {
+		var names []string
+		switch fs.NArg() {
+		case 1:
+			if left, right, ok := strings.Cut(strings.ToLower(fs.Arg(0)), " vs "); ok {
+				names = []string{left, right}
+			}
+		case 2:
+			names = fs.Args()
+		}
+		if names == nil {
+			fs.Usage()
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		rankings := elo.GetRankings()
+		var conferences [2]string
+		for i, name := range names {
+			conference, err := findConference(rankings, name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			conferences[i] = conference
+		}
+		if conferences[0] == conferences[1] {
+			fmt.Fprintf(os.Stderr, "Error: a challenge needs two different conferences, got %s twice\n", conferences[0])
+			os.Exit(1)
+		}
+
+		var pairs [][2]*model.TeamRating
+		if *pairingsFile != "" {
+			f, err := os.Open(*pairingsFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			pairs, err = readPairings(elo, f, conferences)
+			f.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", *pairingsFile, err)
+				os.Exit(1)
+			}
+		} else {
+			pairs = allPairings(rankings, conferences)
+		}
+
+		result, err := projectChallenge(elo, conferences, pairs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Print(formatChallengeTable(result, *pairingsFile == ""))
+		}
+	}
+}
+
+// allPairings matches every member of the first conference, in ranking
+// order, against every member of the second
+func allPairings(rankings []*model.TeamRating, conferences [2]string) [][2]*model.TeamRating {
+	var first, second []*model.TeamRating
+	for _, team := range rankings {
+		switch team.Conference {
+		case conferences[0]:
+			first = append(first, team)
+		case conferences[1]:
+			second = append(second, team)
+		}
+	}
+
+	var pairs [][2]*model.TeamRating
+	for _, a := range first {
+		for _, b := range second {
+			pairs = append(pairs, [2]*model.TeamRating{a, b})
+		}
+	}
+	return pairs
+}
+
+// readPairings reads 'team,team' lines naming a member of each conference, in
+// either order. Blank lines and lines starting with # are skipped.
+func readPairings(elo *model.BayesianELO, r io.Reader, conferences [2]string) ([][2]*model.TeamRating, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	var pairs [][2]*model.TeamRating
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		var pair [2]*model.TeamRating
+		for i, name := range record {
+			if pair[i], err = findTeam(elo, name); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		if pair[0].Conference == conferences[1] && pair[1].Conference == conferences[0] {
+			pair[0], pair[1] = pair[1], pair[0]
+		}
+		if pair[0].Conference != conferences[0] || pair[1].Conference != conferences[1] {
+			return nil, fmt.Errorf("line %d: %s vs %s isn't a %s vs %s game", line, pair[0].TeamName, pair[1].TeamName, conferences[0], conferences[1])
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+// projectChallenge predicts each game at a neutral site and combines them
+// into the distribution of the first conference's wins
+func projectChallenge(elo *model.BayesianELO, conferences [2]string, pairs [][2]*model.TeamRating) (ChallengeResult, error) {
+	result := ChallengeResult{Conference: conferences[0], Opponent: conferences[1]}
+	if len(pairs) == 0 {
+		return result, fmt.Errorf("no %s vs %s games to project", conferences[0], conferences[1])
+	}
+
+	// wins[k] is the probability the first conference wins k of the games so far
+	wins := []float64{1}
+	for _, pair := range pairs {
+		prob, err := elo.PredictMatchup(pair[0].TeamID, pair[1].TeamID)
+		if err != nil {
+			return result, err
+		}
+		result.Games = append(result.Games, ChallengeGame{
+			TeamID:       pair[0].TeamID,
+			TeamName:     pair[0].TeamName,
+			OpponentID:   pair[1].TeamID,
+			OpponentName: pair[1].TeamName,
+			WinProb:      prob,
+		})
+		result.ExpectedWins += prob
+
+		next := make([]float64, len(wins)+1)
+		for k, p := range wins {
+			next[k] += p * (1 - prob)
+			next[k+1] += p * prob
+		}
+		wins = next
+	}
+	result.OpponentExpectedWins = float64(len(pairs)) - result.ExpectedWins
+
+	for k, p := range wins {
+		switch {
+		case 2*k > len(pairs):
+			result.WinProb += p
+		case 2*k == len(pairs):
+			result.TieProb += p
+		default:
+			result.LoseProb += p
+		}
+	}
+	return result, nil
+}
+
+// formatChallengeTable renders a challenge projection: each team's expected
+// wins when every member plays every member, otherwise each game
+func formatChallengeTable(result ChallengeResult, roundRobin bool) string {
+	var sb strings.Builder
+	width := 80
+
+	sb.WriteString(fmt.Sprintf("%s vs %s Challenge (%d games, neutral site)\n", result.Conference, result.Opponent, len(result.Games)))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+
+	if roundRobin {
+		type tally struct {
+			name, conference string
+			games            int
+			wins             float64
+		}
+		var order []string
+		tallies := make(map[string]*tally)
+		add := func(id, name, conference string, prob float64) {
+			t, ok := tallies[id]
+			if !ok {
+				t = &tally{name: name, conference: conference}
+				tallies[id] = t
+				order = append(order, id)
+			}
+			t.games++
+			t.wins += prob
+		}
+		for _, g := range result.Games {
+			add(g.TeamID, g.TeamName, result.Conference, g.WinProb)
+		}
+		for _, g := range result.Games {
+			add(g.OpponentID, g.OpponentName, result.Opponent, 1-g.WinProb)
+		}
+
+		sb.WriteString(fmt.Sprintf("%-30s %-16s %14s\n", "Team", "Conference", "Expected Wins"))
+		sb.WriteString(strings.Repeat("-", width) + "\n")
+		for _, id := range order {
+			t := tallies[id]
+			sb.WriteString(fmt.Sprintf("%-30s %-16s %7.1f of %d\n", output.Truncate(t.name, 30), output.Truncate(t.conference, 16), t.wins, t.games))
+		}
+	} else {
+		sb.WriteString(fmt.Sprintf("%-30s %7s   %-30s %7s\n", result.Conference, "Win %", result.Opponent, "Win %"))
+		sb.WriteString(strings.Repeat("-", width) + "\n")
+		for _, g := range result.Games {
+			sb.WriteString(fmt.Sprintf("%-30s %6.1f%%   %-30s %6.1f%%\n",
+				output.Truncate(g.TeamName, 30), g.WinProb*100, output.Truncate(g.OpponentName, 30), (1-g.WinProb)*100))
+		}
+	}
+
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("Expected wins: %s %.1f, %s %.1f\n", result.Conference, result.ExpectedWins, result.Opponent, result.OpponentExpectedWins))
+	sb.WriteString(fmt.Sprintf("Challenge odds: %s %.1f%%, %s %.1f%%", result.Conference, result.WinProb*100, result.Opponent, result.LoseProb*100))
+	if len(result.Games)%2 == 0 {
+		sb.WriteString(fmt.Sprintf(", tie %.1f%%", result.TieProb*100))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+)
+
+// checkpointer periodically saves engine state while games are processed, so
+// an interrupted run resumes from the last checkpoint instead of starting over.
+// A nil checkpointer does nothing.
+type checkpointer struct {
+	path     string
+	interval time.Duration
+	source   string
+	season   int
+	lastSave time.Time
+}
+
+// newCheckpointer returns a checkpointer writing to path, or nil if path is empty
+func newCheckpointer(path string, interval time.Duration, source string, season int) *checkpointer {
+	if path == "" {
+		return nil
+	}
+	return &checkpointer{path: path, interval: interval, source: source, season: season}
+}
+
+// resume returns the engine saved in an existing checkpoint for the same
+// source and season, or a new engine if there is none
+func (c *checkpointer) resume() *model.BayesianELO {
+	if c == nil {
+		return model.NewBayesianELO()
+	}
+	if _, err := os.Stat(c.path); errors.Is(err, os.ErrNotExist) {
+		return model.NewBayesianELO()
+	}
+
+	elo, state, err := model.LoadState(c.path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring unreadable checkpoint %s: %v\n", c.path, err)
+		return model.NewBayesianELO()
+	}
+	if state.Source != c.source || state.Season != c.season {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring checkpoint %s for %s %d\n", c.path, state.Source, state.Season)
+		return model.NewBayesianELO()
+	}
+
+	fmt.Fprintf(os.Stderr, "Resuming from checkpoint %s: %d games already processed\n", c.path, len(elo.GameLog))
+	c.lastSave = time.Now()
+	return elo
+}
+
+// afterDay returns a ProcessGamesFunc callback that saves a checkpoint once
+// the interval has passed since the last one
+func (c *checkpointer) afterDay(elo *model.BayesianELO) func(date string) error {
+	if c == nil {
+		return nil
+	}
+	if c.lastSave.IsZero() {
+		c.lastSave = time.Now()
+	}
+	return func(date string) error {
+		if time.Since(c.lastSave) < c.interval {
+			return nil
+		}
+		return c.save(elo)
+	}
+}
+
+// save writes the engine's current state to the checkpoint file
+func (c *checkpointer) save(elo *model.BayesianELO) error {
+	if c == nil {
+		return nil
+	}
+	if err := elo.SaveState(c.path, c.source, c.season); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	c.lastSave = time.Now()
+	return nil
+}
+
+// finish removes the checkpoint after a run completes
+func (c *checkpointer) finish() {
+	if c == nil {
+		return
+	}
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Warning: could not remove checkpoint: %v\n", err)
+	}
+}
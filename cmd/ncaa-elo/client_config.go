@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	_ "time/tzdata" // Time zones for -timezone on systems without a zoneinfo database
+
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// headerList collects repeated -header flags
+type headerList []string
+
+func (h *headerList) String() string {
+	return strings.Join(*h, "; ")
+}
+
+func (h *headerList) Set(value string) error {
+	*h = append(*h, value)
+	return nil
+}
+
+// clientFlags holds command line overrides for API client settings
+type clientFlags struct {
+	concurrency *int
+	rate        *float64
+	burst       *int
+	noHTTPCache *bool
+	proxy       *string
+	userAgent   *string
+	timezone    *string
+	headers     headerList
+}
+
+// registerClientFlags adds API client tuning flags to a flag set
+func registerClientFlags(fs *flag.FlagSet) *clientFlags {
+	f := &clientFlags{
+		concurrency: fs.Int("concurrency", 0, "Parallel API requests (default: source-specific)"),
+		rate:        fs.Float64("rate", 0, "Max API requests per second (default: source-specific)"),
+		burst:       fs.Int("burst", 0, "Requests allowed in a burst before rate limiting (default: source-specific)"),
+		noHTTPCache: fs.Bool("no-http-cache", false, "Disable ETag/Last-Modified revalidation of previously fetched responses"),
+		proxy:       fs.String("proxy", os.Getenv("NCAA_ELO_PROXY"), "HTTP(S) proxy URL for API requests (env: NCAA_ELO_PROXY)"),
+		userAgent:   fs.String("user-agent", os.Getenv("NCAA_ELO_USER_AGENT"), "User-Agent for API requests (env: NCAA_ELO_USER_AGENT)"),
+		timezone:    fs.String("timezone", "America/New_York", "Time zone whose calendar day games are dated by, so late tip-offs stay on their day"),
+	}
+	fs.Var(&f.headers, "header", "Extra request header 'Name: Value' (repeatable; env: NCAA_ELO_HEADERS, ';'-separated)")
+	return f
+}
+
+// location returns the -timezone zone games are dated in
+func (f *clientFlags) location() (*time.Location, error) {
+	loc, err := time.LoadLocation(*f.timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -timezone %q: %w", *f.timezone, err)
+	}
+	return loc, nil
+}
+
+// today returns the current game day in the -timezone zone
+func (f *clientFlags) today() (time.Time, error) {
+	loc, err := f.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sources.GameDay(time.Now(), loc), nil
+}
+
+// config returns the client settings for a data source with flag overrides
+// applied. Conditional request state is kept under cacheDir.
+func (f *clientFlags) config(dataSource, cacheDir string) (sources.Config, error) {
+	cfg, err := sources.DefaultConfig(dataSource)
+	if err != nil {
+		return cfg, err
+	}
+
+	if *f.concurrency > 0 {
+		cfg.Concurrency = *f.concurrency
+	}
+	if *f.rate > 0 {
+		cfg.RequestsPerSecond = *f.rate
+	}
+	if *f.burst > 0 {
+		cfg.Burst = *f.burst
+	}
+
+	if *f.proxy != "" {
+		proxy, err := url.Parse(*f.proxy)
+		if err != nil || proxy.Host == "" {
+			return cfg, fmt.Errorf("invalid proxy URL %q", *f.proxy)
+		}
+		cfg.Proxy = proxy
+	}
+	cfg.UserAgent = *f.userAgent
+	cfg.Instrument = func(rt http.RoundTripper) http.RoundTripper { return &metricsTransport{base: rt} }
+	if cfg.Location, err = f.location(); err != nil {
+		return cfg, err
+	}
+
+	// Headers from the environment come first so flags can add to them
+	pairs := strings.Split(os.Getenv("NCAA_ELO_HEADERS"), ";")
+	headers, err := sources.ParseHeaders(append(pairs, f.headers...))
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Headers = headers
+
+	if !*f.noHTTPCache {
+		httpCache, err := sources.NewHTTPCache(filepath.Join(cacheDir, "http"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not initialize HTTP cache: %v\n", err)
+		} else {
+			cfg.HTTPCache = httpCache
+		}
+	}
+	return cfg, nil
+}
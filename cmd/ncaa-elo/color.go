@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// useColor resolves a -color mode: "always", "never", or "auto" to color only
+// a terminal, and then only when NO_COLOR is unset and TERM isn't "dumb"
+func useColor(mode string, f *os.File) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto", "":
+		if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+			return false, nil
+		}
+		return f != nil && term.IsTerminal(int(f.Fd())), nil
+	}
+	return false, fmt.Errorf("invalid -color %q (expected auto, always, or never)", mode)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// command is a CLI subcommand. Its flags function registers the command's
+// flags and returns the function that runs it once they're parsed, so
+// completion can list the flags without running anything.
+type command struct {
+	name    string
+	summary string
+	flags   func(fs *flag.FlagSet) func()
+}
+
+// commandTargets lists, for the commands that take a target before their
+// flags, each target and its flags
+var commandTargets = map[string][]command{
+	"cache":  cacheTargets,
+	"notify": notifyTargets,
+}
+
+// commands lists the subcommands in the order help shows them
+var commands = []command{
+	{"rank", "Rate the season and print or export the rankings (the default)", rankCommand},
+	{"fetch", "Download a season's games into the cache without rating them", fetchCommand},
+	{"doctor", "Check a season's games for duplicates, bad scores, odd dates, and other anomalies", doctorCommand},
+	{"predict", "Predict the outcome of a matchup", predictCommand},
+	{"compare", "Compare two teams side by side", compareCommand},
+	{"team", "Show a team's rating distribution", teamCommand},
+	{"teams", "List teams and their IDs, optionally searching by name", teamsCommand},
+	{"conferences", "Rank conferences by their members' ratings", conferencesCommand},
+	{"challenge", "Project a conference vs conference challenge", challengeCommand},
+	{"tui", "Browse the rankings, teams, and matchups in an interactive terminal UI", tuiCommand},
+	{"splits", "Compare teams' home, road, and neutral-site performance", splitsCommand},
+	{"unproven", "List the teams whose ratings are least certain, and why", unprovenCommand},
+	{"picks", "Pick every game on a date with the favorite's odds and a confidence tier", picksCommand},
+	{"parlay", "Price a parlay: the chance every pick wins and its fair odds", parlayCommand},
+	{"series", "Work out the chances of winning a best-of-N series", seriesCommand},
+	{"normalized", "Expected records against one standard schedule, comparable across teams", normalizedCommand},
+	{"surprise", "Rank teams by how far their results strayed from the model's expectations", surpriseCommand},
+	{"swings", "List the single games that moved ratings the most", swingsCommand},
+	{"bubble", "List the last teams in and first out of the tournament field with their odds", bubbleCommand},
+	{"schedule", "List a team's remaining games with win probabilities", scheduleCommand},
+	{"simulate", "Simulate the remaining schedule and project final records", simulateCommand},
+	{"summary", "Season statistics: games, home wins, margins, overtimes, upsets, and rating spread", summaryCommand},
+	{"backtest", "Score the model's pre-game predictions against actual results", backtestCommand},
+	{"update", "Apply newly completed games to a saved state", updateCommand},
+	{"whatif", "Rerate the season with made-up results and show how the rankings change", whatIfCommand},
+	{"movers", "Compare the rankings between two dates: risers, fallers, and top-N changes", moversCommand},
+	{"greatest", "Rate a range of seasons independently and list the best single-season teams", greatestCommand},
+	{"history", "Export each team's rating after every game day", historyCommand},
+	{"plot", "Plot rating distributions and trajectories", plotCommand},
+	{"live", "Follow today's games with in-game win probabilities", liveCommand},
+	{"serve", "Serve ratings over REST, GraphQL, WebSocket, and gRPC", serveCommand},
+	{"daemon", "Update, publish, and notify on a schedule", daemonCommand},
+	{"notify", "Post the rankings to Slack or email a report", nil},
+	{"discord", "Run a Discord bot answering rating commands", discordCommand},
+	{"sheets", "Push the rankings to a Google Sheet", sheetsCommand},
+	{"cache", "Inspect and manage the game cache", nil},
+	{"completion", "Print a bash, zsh, or fish completion script", completionCommand},
+}
+
+// findCommand returns the named subcommand, or nil if there is none
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// run parses a command's flags, after its target if it takes one, and runs it
+func (c *command) run(args []string) {
+	name, setup := c.name, c.flags
+	if targets := commandTargets[c.name]; targets != nil {
+		names := make([]string, len(targets))
+		for i, t := range targets {
+			names[i] = t.name
+		}
+		if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+			fmt.Fprintf(os.Stderr, "Usage: ncaa-bayes-elo %s <%s> [flags]\n", c.name, strings.Join(names, "|"))
+			os.Exit(1)
+		}
+		target := findTarget(c, args[0])
+		if target == nil {
+			fmt.Fprintf(os.Stderr, "Unknown %s target: %s (expected %s)\n", c.name, args[0], orList(names))
+			os.Exit(1)
+		}
+		name, setup, args = c.name+" "+target.name, target.flags, args[1:]
+	}
+
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	run := setup(fs)
+	parseFlags(fs, args)
+	run()
+}
+
+// findTarget returns a command's named target, or nil if there is none
+func findTarget(c *command, name string) *command {
+	targets := commandTargets[c.name]
+	for i := range targets {
+		if targets[i].name == name {
+			return &targets[i]
+		}
+	}
+	return nil
+}
+
+// printUsage lists the subcommands
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: ncaa-bayes-elo [command] [flags]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nWithout a command, flags are passed to rank. Run 'ncaa-bayes-elo help <command>' for a command's flags.")
+}
+
+// runHelp prints the command list, or a command's flags
+func runHelp(args []string) {
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return
+	}
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", args[0])
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+	cmd.run([]string{"-h"})
+}
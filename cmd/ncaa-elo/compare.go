@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// CommonOpponent is an opponent both compared teams played, with each
+// team's results against it as "W"/"L" strings in date order
+type CommonOpponent struct {
+	TeamID    string   `json:"team_id"`
+	TeamName  string   `json:"team_name"`
+	Results   []string `json:"results"`
+	OpResults []string `json:"opponent_results"`
+}
+
+// Comparison sets two teams side by side
+type Comparison struct {
+	Team            output.Team        `json:"team"`
+	Opponent        output.Team        `json:"opponent"`
+	HomeWinProb     float64            `json:"home_win_prob"` // The first team's chances at home
+	NeutralWinProb  float64            `json:"neutral_win_prob"`
+	AwayWinProb     float64            `json:"away_win_prob"`
+	HeadToHead      []model.GameResult `json:"head_to_head"`
+	CommonOpponents []CommonOpponent   `json:"common_opponents"`
+}
+
+// SeasonRating is a team's end-of-season rating in one season
+type SeasonRating struct {
+	Season int `json:"season"`
+	output.Team
+}
+
+// ProgramComparison sets one program's seasons side by side
+type ProgramComparison struct {
+	TeamID   string         `json:"team_id"`
+	TeamName string         `json:"team_name"`
+	Seasons  []SeasonRating `json:"seasons"`
+}
+
+// compareCommand implements the compare command: two teams' ratings, records,
+// head-to-head games, common opponents, and matchup odds in one view, or
+// with -seasons, one team's ratings across seasons
+func compareCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	teamName := fs.String("team", "", "With -seasons, the team to compare across seasons (or give it as an argument)")
+	var seasons seasonList
+	fs.Var(&seasons, "seasons", "Compare one team across these seasons, each rated on its own from the same prior (e.g. 2015,2019,2023-2025)")
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ncaa-bayes-elo compare [flags] <team> <team>")
+		fmt.Fprintln(fs.Output(), "       ncaa-bayes-elo compare [flags] -seasons <seasons> -team <team>")
+		fs.PrintDefaults()
+	}
+	return func() {
+		if len(seasons) > 0 && *teamName == "" {
+			*teamName = strings.Join(fs.Args(), " ")
+		}
+		if *teamName == "" && fs.NArg() == 0 {
+			fs.Usage()
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+
+		if len(seasons) > 0 {
+			comparison, err := compareSeasons(ctx, engine, *teamName, seasons)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			switch output.Format(*outputFormat) {
+			case output.FormatJSON:
+				data, _ := json.MarshalIndent(comparison, "", "  ")
+				fmt.Println(string(data))
+			default:
+				fmt.Print(formatSeasonComparison(comparison))
+			}
+			return
+		}
+		if *teamName != "" {
+			fmt.Fprintln(os.Stderr, "Error: -team is for comparing across -seasons; give two teams to compare them")
+			os.Exit(1)
+		}
+
+		elo := engine.mustLoad(ctx)
+
+		a, b, err := matchupArgs(elo, fs.Args())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		comparison, err := compareTeams(elo, a, b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(comparison, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Print(formatComparison(comparison))
+		}
+	}
+}
+
+// compareTeams gathers a comparison of two teams from the ratings and game log
+func compareTeams(elo *model.BayesianELO, a, b *model.TeamRating) (Comparison, error) {
+	ranks := elo.Ranks()
+	splits := output.Splits(elo)
+	side := func(team *model.TeamRating) output.Team {
+		t := output.NewTeam(ranks[team.TeamID], team)
+		t.SetRecords(splits[team.TeamID])
+		return t
+	}
+	c := Comparison{Team: side(a), Opponent: side(b), HeadToHead: []model.GameResult{}, CommonOpponents: []CommonOpponent{}}
+
+	for _, p := range []struct {
+		homeELO float64
+		prob    *float64
+	}{
+		{elo.HomeAdvantage, &c.HomeWinProb},
+		{0, &c.NeutralWinProb},
+		{-elo.HomeAdvantage, &c.AwayWinProb},
+	} {
+		prob, err := elo.PredictMatchupAt(a.TeamID, b.TeamID, p.homeELO)
+		if err != nil {
+			return c, err
+		}
+		*p.prob = prob
+	}
+
+	// Each team's results by opponent, in date order
+	games := append([]model.GameResult(nil), elo.GameLog...)
+	sort.SliceStable(games, func(i, j int) bool { return games[i].Date < games[j].Date })
+	results := map[string]map[string][]string{a.TeamID: {}, b.TeamID: {}}
+	names := make(map[string]string)
+	for _, g := range games {
+		if (g.WinnerID == a.TeamID && g.LoserID == b.TeamID) || (g.WinnerID == b.TeamID && g.LoserID == a.TeamID) {
+			c.HeadToHead = append(c.HeadToHead, g)
+			continue
+		}
+		if byOpponent, ok := results[g.WinnerID]; ok {
+			byOpponent[g.LoserID] = append(byOpponent[g.LoserID], "W")
+			names[g.LoserID] = g.LoserName
+		}
+		if byOpponent, ok := results[g.LoserID]; ok {
+			byOpponent[g.WinnerID] = append(byOpponent[g.WinnerID], "L")
+			names[g.WinnerID] = g.WinnerName
+		}
+	}
+
+	for id, aResults := range results[a.TeamID] {
+		if bResults, ok := results[b.TeamID][id]; ok {
+			c.CommonOpponents = append(c.CommonOpponents, CommonOpponent{
+				TeamID:    id,
+				TeamName:  names[id],
+				Results:   aResults,
+				OpResults: bResults,
+			})
+		}
+	}
+	sort.Slice(c.CommonOpponents, func(i, j int) bool { return c.CommonOpponents[i].TeamName < c.CommonOpponents[j].TeamName })
+	return c, nil
+}
+
+// formatComparison renders a comparison as side-by-side text
+func formatComparison(c Comparison) string {
+	var sb strings.Builder
+	width := 72
+	a, b := c.Team, c.Opponent
+
+	row := func(label, left, right string) {
+		sb.WriteString(fmt.Sprintf("%-24s %23s %23s\n", label, left, right))
+	}
+	num := func(v float64) string { return fmt.Sprintf("%.1f", v) }
+
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	row("", output.Truncate(a.TeamName, 23), output.Truncate(b.TeamName, 23))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	row("Rank", fmt.Sprint(a.Rank), fmt.Sprint(b.Rank))
+	if a.Conference != "" || b.Conference != "" {
+		row("Conference", output.Truncate(a.Conference, 23), output.Truncate(b.Conference, 23))
+	}
+	row("Record", fmt.Sprintf("%d-%d", a.Wins, a.Losses), fmt.Sprintf("%d-%d", b.Wins, b.Losses))
+	row("Home", fmt.Sprintf("%d-%d", a.HomeWins, a.HomeLosses), fmt.Sprintf("%d-%d", b.HomeWins, b.HomeLosses))
+	row("Road", fmt.Sprintf("%d-%d", a.RoadWins, a.RoadLosses), fmt.Sprintf("%d-%d", b.RoadWins, b.RoadLosses))
+	row("vs Top 50", fmt.Sprintf("%d-%d", a.Top50Wins, a.Top50Losses), fmt.Sprintf("%d-%d", b.Top50Wins, b.Top50Losses))
+	row("Mean ELO", num(a.MeanELO), num(b.MeanELO))
+	row("Std Dev", num(a.StdDev), num(b.StdDev))
+	row("5th %", num(a.Pct5), num(b.Pct5))
+	row("Median", num(a.Median), num(b.Median))
+	row("95th %", num(a.Pct95), num(b.Pct95))
+
+	sb.WriteString("\nWin Probability\n")
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, p := range []struct {
+		where string
+		prob  float64
+	}{
+		{"At " + a.TeamName, c.HomeWinProb},
+		{"Neutral site", c.NeutralWinProb},
+		{"At " + b.TeamName, c.AwayWinProb},
+	} {
+		row(output.Truncate(p.where, 24), fmt.Sprintf("%.1f%%", p.prob*100), fmt.Sprintf("%.1f%%", (1-p.prob)*100))
+	}
+
+	sb.WriteString("\nHead to Head\n")
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	if len(c.HeadToHead) == 0 {
+		sb.WriteString("No games this season\n")
+	}
+	for _, g := range c.HeadToHead {
+		sb.WriteString(fmt.Sprintf("%s  %s beat %s (%.1f%% pre-game)\n", g.Date, g.WinnerName, g.LoserName, g.WinProb*100))
+	}
+
+	sb.WriteString("\nCommon Opponents\n")
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	if len(c.CommonOpponents) == 0 {
+		sb.WriteString("None\n")
+	}
+	for _, o := range c.CommonOpponents {
+		row(output.Truncate(o.TeamName, 24), strings.Join(o.Results, " "), strings.Join(o.OpResults, " "))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	return sb.String()
+}
+
+// compareSeasons rates each season on its own and gathers one team's
+// end-of-season rating in each. The team is matched by name in the first
+// season it's found and followed by ID after that; seasons it didn't play
+// are skipped.
+func compareSeasons(ctx context.Context, engine *engineFlags, name string, seasons []int) (ProgramComparison, error) {
+	var c ProgramComparison
+	for _, season := range seasons {
+		fmt.Fprintf(os.Stderr, "Season %d-%d\n", season-1, season)
+		elo, err := engine.loadSeason(ctx, season)
+		if err != nil {
+			return c, fmt.Errorf("season %d: %w", season, err)
+		}
+		team, ok := elo.Teams[c.TeamID]
+		if !ok {
+			if team, err = findTeam(elo, name); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: season %d: %v\n", season, err)
+				continue
+			}
+		}
+		c.TeamID, c.TeamName = team.TeamID, team.TeamName
+
+		t := output.NewTeam(elo.Ranks()[team.TeamID], team)
+		t.SetRecords(output.Splits(elo)[team.TeamID])
+		c.Seasons = append(c.Seasons, SeasonRating{Season: season, Team: t})
+	}
+	if len(c.Seasons) == 0 {
+		return c, fmt.Errorf("no team matches %q in any of those seasons", name)
+	}
+	return c, nil
+}
+
+// formatSeasonComparison renders one team's seasons as a text table
+func formatSeasonComparison(c ProgramComparison) string {
+	var sb strings.Builder
+	width := 78
+
+	sb.WriteString(c.TeamName + " by Season\n")
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-7s %5s %7s %8s %8s %8s %8s %8s %8s\n", "Season", "Rank", "W-L", "Mean", "StdDev", "5th%", "Median", "95th%", "Change"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for i, s := range c.Seasons {
+		change := "-"
+		if i > 0 {
+			change = output.FormatTrend(s.MeanELO - c.Seasons[i-1].MeanELO)
+		}
+		sb.WriteString(fmt.Sprintf("%-7s %5d %7s %8.1f %8.1f %8.1f %8.1f %8.1f %s\n",
+			fmt.Sprintf("%d-%02d", s.Season-1, s.Season%100), s.Rank, fmt.Sprintf("%d-%d", s.Wins, s.Losses),
+			s.MeanELO, s.StdDev, s.Pct5, s.Median, s.Pct95, output.PadLeft(change, 8)))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString("\nEvery season is rated on its own from the same prior, so ratings share one scale.\n")
+	return sb.String()
+}
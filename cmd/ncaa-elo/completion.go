@@ -0,0 +1,333 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/cache"
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// teamFlags are the flags that take a team ID
+var teamFlags = map[string]bool{"team": true, "predict": true, "vs": true}
+
+// sourceFlags are the flags that take a data source name
+var sourceFlags = map[string]bool{"source": true, "compare": true}
+
+// teamArgCommands take teams as positional arguments, by ID or name
+var teamArgCommands = map[string]bool{"predict": true, "team": true}
+
+// completionCommand implements the completion command: print the completion
+// script for a shell
+func completionCommand(fs *flag.FlagSet) func() {
+	return func() {
+		if fs.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "Usage: ncaa-bayes-elo completion <bash|zsh|fish>")
+			os.Exit(1)
+		}
+		script, ok := completionScripts[fs.Arg(0)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unsupported shell: %s (expected bash, zsh, or fish)\n", fs.Arg(0))
+			os.Exit(1)
+		}
+		fmt.Print(script)
+	}
+}
+
+// runComplete implements the hidden __complete command the completion scripts
+// call: given the shell and the words after the program name, the last being
+// the one under the cursor, print one candidate per line
+func runComplete(args []string) {
+	if len(args) < 1 {
+		return
+	}
+	shell, words := args[0], args[1:]
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	for _, c := range completeWords(words) {
+		switch shell {
+		case "zsh":
+			c.value = strings.ReplaceAll(c.value, ":", `\:`)
+			if c.desc != "" {
+				fmt.Printf("%s:%s\n", c.value, c.desc)
+			} else {
+				fmt.Println(c.value)
+			}
+		case "fish":
+			fmt.Printf("%s\t%s\n", c.value, c.desc)
+		default:
+			fmt.Println(bashEscape(c.value))
+		}
+	}
+}
+
+// candidate is a completion with an optional description
+type candidate struct {
+	value, desc string
+}
+
+// completeWords returns the candidates for the last word
+func completeWords(words []string) []candidate {
+	cur := words[len(words)-1]
+	if len(words) == 1 {
+		var out []candidate
+		for _, cmd := range commands {
+			if strings.HasPrefix(cmd.name, cur) {
+				out = append(out, candidate{cmd.name, cmd.summary})
+			}
+		}
+		if strings.HasPrefix(cur, "-") {
+			out = append(out, flagCandidates(findCommand("rank"), nil, cur)...)
+		}
+		return out
+	}
+
+	cmd, rest := findCommand(words[0]), words[1:]
+	if cmd == nil {
+		cmd, rest = findCommand("rank"), words
+	}
+
+	// completion takes a shell, and notify and cache a target before their
+	// flags
+	var targets []string
+	if cmd.name == "completion" {
+		if len(rest) > 1 {
+			return nil
+		}
+		targets = []string{"bash", "zsh", "fish"}
+	}
+	for _, t := range commandTargets[cmd.name] {
+		targets = append(targets, t.name)
+	}
+	var sub []string
+	if targets != nil {
+		if len(rest) == 1 {
+			var out []candidate
+			for _, t := range targets {
+				if strings.HasPrefix(t, cur) {
+					out = append(out, candidate{value: t})
+				}
+			}
+			return out
+		}
+		sub, rest = rest[:1], rest[1:]
+	}
+
+	if len(rest) >= 2 {
+		if prev := strings.TrimLeft(rest[len(rest)-2], "-"); strings.HasPrefix(rest[len(rest)-2], "-") && !strings.Contains(prev, "=") {
+			fs := commandFlags(cmd, sub)
+			if f := lookupFlag(fs, prev); f != nil && !isBoolFlag(f) {
+				if teamFlags[prev] {
+					return teamCandidates(words, cur, true)
+				}
+				if sourceFlags[prev] {
+					var out []candidate
+					for _, name := range sources.Names() {
+						if strings.HasPrefix(name, cur) {
+							out = append(out, candidate{value: name})
+						}
+					}
+					return out
+				}
+				return nil // Let the shell complete file names
+			}
+		}
+	}
+	if strings.HasPrefix(cur, "-") {
+		return flagCandidates(cmd, sub, cur)
+	}
+	if teamArgCommands[cmd.name] {
+		return teamCandidates(words, cur, false)
+	}
+	return nil
+}
+
+// commandFlags returns a command's flag set, for the target in args if it
+// takes one, registering the flags without running the command. It returns
+// nil for an unknown target.
+func commandFlags(cmd *command, args []string) *flag.FlagSet {
+	name, setup := cmd.name, cmd.flags
+	if commandTargets[cmd.name] != nil {
+		if len(args) == 0 {
+			return nil
+		}
+		target := findTarget(cmd, args[0])
+		if target == nil {
+			return nil
+		}
+		name, setup = cmd.name+" "+target.name, target.flags
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	setup(fs)
+	registerConfigFlag(fs)
+	return fs
+}
+
+// lookupFlag finds a flag in a possibly nil flag set
+func lookupFlag(fs *flag.FlagSet, name string) *flag.Flag {
+	if fs == nil {
+		return nil
+	}
+	return fs.Lookup(name)
+}
+
+// isBoolFlag reports whether a flag takes no value
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// flagCandidates lists a command's flags starting with prefix
+func flagCandidates(cmd *command, sub []string, prefix string) []candidate {
+	fs := commandFlags(cmd, sub)
+	if fs == nil {
+		return nil
+	}
+	var out []candidate
+	fs.VisitAll(func(f *flag.Flag) {
+		if name := "-" + f.Name; strings.HasPrefix(name, prefix) {
+			desc, _, _ := strings.Cut(f.Usage, " (")
+			out = append(out, candidate{name, desc})
+		}
+	})
+	return out
+}
+
+// teamCandidates lists the teams matching prefix by ID or name, from the
+// -load-state on the command line or the newest matching cached season.
+// Flags take IDs, which a name prefix also completes to; positional arguments
+// complete to names. After a comma (as in -predict 57,150) only the part after
+// it is completed.
+func teamCandidates(words []string, prefix string, ids bool) []candidate {
+	lead := ""
+	if i := strings.LastIndex(prefix, ","); i >= 0 {
+		lead, prefix = prefix[:i+1], prefix[i+1:]
+	}
+	prefix = strings.ToLower(strings.TrimLeft(prefix, `"'`))
+
+	teams := knownTeams(words)
+	idList := make([]string, 0, len(teams))
+	for id := range teams {
+		idList = append(idList, id)
+	}
+	sort.Slice(idList, func(i, j int) bool { return teams[idList[i]] < teams[idList[j]] })
+
+	var out []candidate
+	for _, id := range idList {
+		name := teams[id]
+		switch {
+		case strings.HasPrefix(id, prefix) || (ids && strings.HasPrefix(strings.ToLower(name), prefix)):
+			out = append(out, candidate{lead + id, name})
+		case strings.HasPrefix(strings.ToLower(name), prefix):
+			out = append(out, candidate{lead + name, id})
+		}
+	}
+	return out
+}
+
+// knownTeams maps team IDs to names from a saved state named on the command
+// line, or else from the cached games of the -source and -season given (by
+// default the newest cached season)
+func knownTeams(words []string) map[string]string {
+	source, season, state, cacheDir := "espn", 0, "", cache.DefaultDir()
+	for i := 0; i+1 < len(words); i++ {
+		switch strings.TrimLeft(words[i], "-") {
+		case "load-state", "state":
+			state = words[i+1]
+		case "cache-dir":
+			cacheDir = words[i+1]
+		case "source":
+			source = words[i+1]
+		case "season":
+			fmt.Sscan(words[i+1], &season)
+		}
+	}
+
+	teams := make(map[string]string)
+	if state != "" {
+		if elo, _, err := model.LoadState(state); err == nil {
+			for id, team := range elo.Teams {
+				teams[id] = team.TeamName
+			}
+		}
+		return teams
+	}
+
+	c, err := cache.New(cacheDir)
+	if err != nil {
+		return teams
+	}
+	if season == 0 {
+		seasons, _ := c.Seasons()
+		for _, s := range seasons {
+			if s.Source == source {
+				season = s.Season
+				break
+			}
+		}
+	}
+	entries, _ := c.Entries(season, source)
+	for _, entry := range entries {
+		for _, g := range entry.Games {
+			teams[g.HomeTeamID] = g.HomeTeam
+			teams[g.AwayTeamID] = g.AwayTeam
+		}
+	}
+	delete(teams, "")
+	return teams
+}
+
+// bashEscape backslash-escapes the characters bash would otherwise split or
+// interpret in a completed word
+func bashEscape(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(" '\"\\()&;<>|$`!*?[]{}#~", r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// completionScripts are the shell completion scripts, which ask the binary
+// being completed for candidates
+var completionScripts = map[string]string{
+	"bash": `# bash completion for ncaa-bayes-elo
+# Load with: source <(ncaa-bayes-elo completion bash)
+_ncaa_bayes_elo() {
+    local IFS=$'\n'
+    COMPREPLY=($("${COMP_WORDS[0]}" __complete bash "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _ncaa_bayes_elo ncaa-bayes-elo ./ncaa-bayes-elo
+`,
+	"zsh": `#compdef ncaa-bayes-elo
+# zsh completion for ncaa-bayes-elo
+# Load with: source <(ncaa-bayes-elo completion zsh)
+_ncaa_bayes_elo() {
+    local -a candidates
+    candidates=("${(@f)$("${words[1]}" __complete zsh "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    if [[ -z "${candidates[1]}" ]]; then
+        _files
+        return
+    fi
+    _describe 'ncaa-bayes-elo' candidates
+}
+compdef _ncaa_bayes_elo ncaa-bayes-elo ./ncaa-bayes-elo
+`,
+	"fish": `# fish completion for ncaa-bayes-elo
+# Load with: ncaa-bayes-elo completion fish | source
+function __ncaa_bayes_elo_complete
+    set -l words (commandline -opc)
+    set -e words[1]
+    ncaa-bayes-elo __complete fish $words (commandline -ct) 2>/dev/null
+end
+complete -c ncaa-bayes-elo -a '(__ncaa_bayes_elo_complete)'
+`,
+}
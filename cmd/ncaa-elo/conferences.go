@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// assignConferences fills in the conference of teams without one, when the
+// data source knows conferences. Conferences are informational, so failures
+// only warn.
+func assignConferences(ctx context.Context, elo *model.BayesianELO, dataSource string, clientConfig sources.Config) {
+	missing := false
+	for _, team := range elo.Teams {
+		if team.Conference == "" {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return
+	}
+
+	source, err := newGameSource(dataSource, clientConfig)
+	if err != nil {
+		return
+	}
+	conferenceSource, ok := source.(sources.ConferenceSource)
+	if !ok {
+		return
+	}
+	conferences, err := conferenceSource.GetConferences(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not fetch conferences: %v\n", err)
+		return
+	}
+	for id, team := range elo.Teams {
+		if conference, ok := conferences[id]; ok && team.Conference == "" {
+			team.Conference = conference
+		}
+	}
+}
+
+// nonD1ID and nonD1Name identify the team standing in for every non-Division I
+// opponent with -merge-non-d1
+const (
+	nonD1ID   = "non-d1"
+	nonD1Name = "Non-D1"
+)
+
+// gameFilter drops or rewrites games before they're processed, reporting
+// what it changed on stderr
+type gameFilter func(games []model.Game) []model.Game
+
+// fetchD1Teams returns the IDs of the Division I teams, the members of the
+// source's conferences
+func fetchD1Teams(ctx context.Context, dataSource string, clientConfig sources.Config) (map[string]bool, error) {
+	source, err := newGameSource(dataSource, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	conferenceSource, ok := source.(sources.ConferenceSource)
+	if !ok {
+		return nil, fmt.Errorf("the %s source has no Division I team list", dataSource)
+	}
+	conferences, err := conferenceSource.GetConferences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching Division I teams: %w", err)
+	}
+	d1 := make(map[string]bool, len(conferences))
+	for id := range conferences {
+		d1[id] = true
+	}
+	return d1, nil
+}
+
+// nonD1Filter drops games against teams outside d1 or, merging, plays them
+// against a single Non-D1 team. Games between two non-D1 teams are dropped
+// either way.
+func nonD1Filter(d1 map[string]bool, merge bool) gameFilter {
+	return func(games []model.Game) []model.Game {
+		var kept []model.Game
+		dropped, merged := 0, 0
+		for _, g := range games {
+			homeD1, awayD1 := d1[g.HomeTeamID], d1[g.AwayTeamID]
+			switch {
+			case homeD1 && awayD1:
+				kept = append(kept, g)
+			case merge && (homeD1 || awayD1):
+				if !homeD1 {
+					g.HomeTeamID, g.HomeTeam = nonD1ID, nonD1Name
+				} else {
+					g.AwayTeamID, g.AwayTeam = nonD1ID, nonD1Name
+				}
+				if g.WinnerID != "" && !d1[g.WinnerID] {
+					g.WinnerID = nonD1ID
+				}
+				kept = append(kept, g)
+				merged++
+			default:
+				dropped++
+			}
+		}
+
+		if merged > 0 {
+			fmt.Fprintf(os.Stderr, "Rated %d games against non-Division I opponents as %s\n", merged, nonD1Name)
+		}
+		if dropped > 0 {
+			fmt.Fprintf(os.Stderr, "Excluded %d games against non-Division I opponents\n", dropped)
+		}
+		return kept
+	}
+}
+
+// errNoConferences reports ratings without conference data
+var errNoConferences = errors.New("no conference data (the ncaa source doesn't provide conferences)")
+
+// conferenceList collects repeated -conference flags, each of which may also
+// be a comma-separated list
+type conferenceList []string
+
+func (c *conferenceList) String() string {
+	return strings.Join(*c, ",")
+}
+
+func (c *conferenceList) Set(value string) error {
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			*c = append(*c, name)
+		}
+	}
+	return nil
+}
+
+// filterConferences keeps the teams in any of the named conferences, in
+// order. Names match case-insensitively and ignoring punctuation.
+func filterConferences(teams []*model.TeamRating, names []string) ([]*model.TeamRating, error) {
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		conference, err := findConference(teams, name)
+		if err != nil {
+			return nil, err
+		}
+		wanted[conference] = true
+	}
+
+	var filtered []*model.TeamRating
+	for _, team := range teams {
+		if wanted[team.Conference] {
+			filtered = append(filtered, team)
+		}
+	}
+	return filtered, nil
+}
+
+// topPerConference keeps the best n teams of each conference, grouped by
+// conference, strongest first by the average rating of the teams kept.
+// Teams keep their ranking order within a conference; teams without one are
+// left out.
+func topPerConference(teams []*model.TeamRating, n int) ([]*model.TeamRating, error) {
+	groups := make(map[string][]*model.TeamRating)
+	var names []string
+	for _, team := range teams {
+		if team.Conference == "" {
+			continue
+		}
+		if _, ok := groups[team.Conference]; !ok {
+			names = append(names, team.Conference)
+		}
+		if len(groups[team.Conference]) < n {
+			groups[team.Conference] = append(groups[team.Conference], team)
+		}
+	}
+	if len(names) == 0 {
+		return nil, errNoConferences
+	}
+
+	strength := make(map[string]float64, len(names))
+	for _, name := range names {
+		for _, team := range groups[name] {
+			strength[name] += team.Dist.Mean() / float64(len(groups[name]))
+		}
+	}
+	sort.SliceStable(names, func(i, j int) bool { return strength[names[i]] > strength[names[j]] })
+
+	var kept []*model.TeamRating
+	for _, name := range names {
+		kept = append(kept, groups[name]...)
+	}
+	return kept, nil
+}
+
+// findConference resolves a conference name among the teams' conferences,
+// case-insensitively and ignoring punctuation
+func findConference(teams []*model.TeamRating, name string) (string, error) {
+	known := make(map[string]string)
+	for _, team := range teams {
+		if team.Conference != "" {
+			known[normalizeName(team.Conference)] = team.Conference
+		}
+	}
+	if len(known) == 0 {
+		return "", errNoConferences
+	}
+	if conference, ok := known[normalizeName(name)]; ok {
+		return conference, nil
+	}
+	return "", fmt.Errorf("unknown conference %q (known: %s)", name, strings.Join(sortedValues(known), ", "))
+}
+
+// sortedValues returns a map's values in sorted order
+func sortedValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// ConferenceRating aggregates a conference's member ratings
+type ConferenceRating struct {
+	Rank       int     `json:"rank"`
+	Conference string  `json:"conference"`
+	Teams      int     `json:"teams"`
+	MeanELO    float64 `json:"mean_elo"`
+	StdDev     float64 `json:"std_dev"` // Uncertainty in the mean, treating members as independent
+	TopAvg     float64 `json:"top_avg"` // Mean of the best top-n members
+	Depth      float64 `json:"depth"`   // Median member's rating
+	Best       string  `json:"best_team"`
+}
+
+// conferencesCommand implements the conferences command: rank conferences by
+// their members' ratings
+func conferencesCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	topN := fs.Int("top-n", 5, "Members averaged in the top-N column")
+	sortBy := fs.String("sort", "mean", "Rank conferences by 'mean', 'top' (top-N average), or 'depth' (median member)")
+	outputFormat := fs.String("format", "table", "Output format: 'table', 'json', or 'csv'")
+	return func() {
+		if *topN < 1 {
+			fmt.Fprintln(os.Stderr, "Error: -top-n must be at least 1")
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		ratings, err := conferenceRatings(elo, *topN, *sortBy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			fmt.Println(output.JSON(ratings))
+		case output.FormatCSV:
+			fmt.Print(formatConferencesCSV(ratings))
+		default:
+			fmt.Print(formatConferencesTable(ratings, *engine.season, *topN))
+		}
+	}
+}
+
+// conferenceRatings aggregates every conference's members, ranked by the
+// mean, top-N average, or depth
+func conferenceRatings(elo *model.BayesianELO, topN int, sortBy string) ([]ConferenceRating, error) {
+	members := make(map[string][]*model.TeamRating)
+	for _, team := range elo.GetRankings() {
+		if team.Conference != "" {
+			members[team.Conference] = append(members[team.Conference], team)
+		}
+	}
+	if len(members) == 0 {
+		return nil, errNoConferences
+	}
+
+	var ratings []ConferenceRating
+	for conference, teams := range members {
+		ratings = append(ratings, conferenceRating(conference, teams, topN))
+	}
+
+	var key func(c ConferenceRating) float64
+	switch sortBy {
+	case "mean":
+		key = func(c ConferenceRating) float64 { return c.MeanELO }
+	case "top":
+		key = func(c ConferenceRating) float64 { return c.TopAvg }
+	case "depth":
+		key = func(c ConferenceRating) float64 { return c.Depth }
+	default:
+		return nil, fmt.Errorf("invalid -sort %q (expected mean, top, or depth)", sortBy)
+	}
+	sort.Slice(ratings, func(i, j int) bool {
+		if key(ratings[i]) != key(ratings[j]) {
+			return key(ratings[i]) > key(ratings[j])
+		}
+		return ratings[i].Conference < ratings[j].Conference
+	})
+	for i := range ratings {
+		ratings[i].Rank = i + 1
+	}
+	return ratings, nil
+}
+
+// conferenceRating aggregates one conference's members, given in ranking order
+func conferenceRating(conference string, teams []*model.TeamRating, topN int) ConferenceRating {
+	var sum, variance float64
+	means := make([]float64, len(teams))
+	for i, team := range teams {
+		means[i] = team.Dist.Mean()
+		sum += means[i]
+		variance += team.Dist.Std() * team.Dist.Std()
+	}
+	n := float64(len(teams))
+
+	top := means[:min(topN, len(means))]
+	var topSum float64
+	for _, m := range top {
+		topSum += m
+	}
+
+	depth := means[len(means)/2]
+	if len(means)%2 == 0 {
+		depth = (means[len(means)/2-1] + means[len(means)/2]) / 2
+	}
+
+	return ConferenceRating{
+		Conference: conference,
+		Teams:      len(teams),
+		MeanELO:    sum / n,
+		StdDev:     math.Sqrt(variance) / n,
+		TopAvg:     topSum / float64(len(top)),
+		Depth:      depth,
+		Best:       teams[0].TeamName,
+	}
+}
+
+// formatConferencesTable renders conference ratings as a text table
+func formatConferencesTable(ratings []ConferenceRating, season, topN int) string {
+	var sb strings.Builder
+	width := 96
+
+	sb.WriteString(fmt.Sprintf("NCAA Men's Basketball Conference Ratings (%d-%d Season)\n", season-1, season))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-4s %-16s %5s %8s %8s %8s %8s  %s\n",
+		"Rank", "Conference", "Teams", "Mean", "StdDev", fmt.Sprintf("Top %d", topN), "Depth", "Best Team"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, c := range ratings {
+		sb.WriteString(fmt.Sprintf("%-4d %-16s %5d %8.1f %8.1f %8.1f %8.1f  %s\n",
+			c.Rank,
+			output.Truncate(c.Conference, 16),
+			c.Teams,
+			c.MeanELO,
+			c.StdDev,
+			c.TopAvg,
+			c.Depth,
+			output.Truncate(c.Best, 30)))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString("\nMean averages every member; Depth is the median member's rating.\n")
+	return sb.String()
+}
+
+// formatConferencesCSV renders conference ratings as CSV
+func formatConferencesCSV(ratings []ConferenceRating) string {
+	var sb strings.Builder
+	sb.WriteString("rank,conference,teams,mean_elo,std_dev,top_avg,depth,best_team\n")
+	for _, c := range ratings {
+		sb.WriteString(fmt.Sprintf("%d,\"%s\",%d,%.1f,%.1f,%.1f,%.1f,\"%s\"\n",
+			c.Rank, c.Conference, c.Teams, c.MeanELO, c.StdDev, c.TopAvg, c.Depth, c.Best))
+	}
+	return sb.String()
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// envFlagPattern finds the environment variable a flag's usage says it reads
+var envFlagPattern = regexp.MustCompile(`env: ([A-Z0-9_]+)`)
+
+// parseFlags parses a command's flags, then fills in those not given on the
+// command line from the environment and config file. Precedence runs command
+// line, then environment variables, then the config file, then built-in
+// defaults; a repeatable flag takes its values from the first of these that
+// sets it rather than adding them together.
+func parseFlags(fs *flag.FlagSet, args []string) {
+	registerConfigFlag(fs)
+	fs.Parse(args)
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+
+	path, explicit := configPath(args)
+	if path != "" {
+		config, err := loadConfig(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist) && !explicit:
+			// No default config file
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Error: config %s: %v\n", path, err)
+			os.Exit(1)
+		default:
+			if err := applyConfig(fs, config, given); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: config %s: %v\n", path, err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	if err := applyEnv(fs, given); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// registerConfigFlag adds the -config flag every command takes
+func registerConfigFlag(fs *flag.FlagSet) {
+	fs.String("config", "", "Config file (default: $NCAA_ELO_CONFIG or ncaa-bayes-elo/config.yaml in the user config dir)")
+}
+
+// flagEnvVar returns the environment variable that sets a flag: the one its
+// usage names, or NCAA_ELO_ and the flag name in upper case with underscores
+func flagEnvVar(f *flag.Flag) string {
+	if m := envFlagPattern.FindStringSubmatch(f.Usage); m != nil {
+		return m[1]
+	}
+	return "NCAA_ELO_" + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+}
+
+// applyEnv sets flags not given on the command line from NCAA_ELO_*
+// environment variables. Flags that name their own variable already read it
+// as their default. Repeatable flags take a comma-separated list.
+func applyEnv(fs *flag.FlagSet, given map[string]bool) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || given[f.Name] || f.Name == "config" || envFlagPattern.MatchString(f.Usage) {
+			return
+		}
+		name := flagEnvVar(f)
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			return
+		}
+
+		values := []string{value}
+		if _, list := f.Value.(*urlList); list {
+			values = strings.Split(value, ",")
+		}
+		for _, v := range values {
+			if setErr := fs.Set(f.Name, strings.TrimSpace(v)); setErr != nil {
+				err = fmt.Errorf("%s=%q: %w", name, value, setErr)
+				return
+			}
+		}
+	})
+	return err
+}
+
+// configPath returns the config file named by -config, NCAA_ELO_CONFIG, or the
+// default location, reporting whether it was chosen explicitly. The command
+// line is scanned before parsing since the file supplies the flag defaults.
+func configPath(args []string) (string, bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "config" {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			value = args[i+1]
+		}
+		return value, true
+	}
+
+	if path := os.Getenv("NCAA_ELO_CONFIG"); path != "" {
+		return path, true
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	for _, name := range []string{"config.yaml", "config.yml", "config.toml"} {
+		path := filepath.Join(dir, "ncaa-bayes-elo", name)
+		if _, err := os.Stat(path); err == nil {
+			return path, false
+		}
+	}
+	return filepath.Join(dir, "ncaa-bayes-elo", "config.yaml"), false
+}
+
+// loadConfig reads a YAML or TOML (by .toml extension) config file
+func loadConfig(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := make(map[string]any)
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(data, &config)
+	} else {
+		err = yaml.Unmarshal(data, &config)
+	}
+	return config, err
+}
+
+// applyConfig sets flags not given on the command line from the config's
+// top-level values, then from the section named after the command (and
+// subcommand, nested inside it). A key in a section replaces the same key
+// above it, so a repeatable flag takes only the innermost list. Top-level keys
+// may be flags of other commands and are skipped, but unknown keys in a
+// command's own section are errors.
+func applyConfig(fs *flag.FlagSet, config map[string]any, given map[string]bool) error {
+	values := make(map[string]any)
+	if err := readSection(fs, config, values, false); err != nil {
+		return err
+	}
+
+	section := config
+	for _, name := range strings.Fields(fs.Name()) {
+		next, ok := section[name].(map[string]any)
+		if !ok {
+			break
+		}
+		if err := readSection(fs, next, values, true); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		section = next
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		// The command line and environment take precedence over the file
+		if given[key] || os.Getenv(flagEnvVar(fs.Lookup(key))) != "" {
+			continue
+		}
+
+		list, ok := values[key].([]any)
+		if !ok {
+			list = []any{values[key]}
+		}
+		for _, v := range list {
+			if err := fs.Set(key, fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// readSection records the values of a section's keys that name flags,
+// replacing any recorded from an outer section. Nested sections belong to
+// other commands and are skipped.
+func readSection(fs *flag.FlagSet, section map[string]any, values map[string]any, strict bool) error {
+	keys := make([]string, 0, len(section))
+	for key := range section {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := section[key]
+		if _, ok := value.(map[string]any); ok || value == nil {
+			continue
+		}
+		if fs.Lookup(key) == nil {
+			if strict {
+				return fmt.Errorf("unknown option %q", key)
+			}
+			continue
+		}
+		values[key] = value
+	}
+	return nil
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+
+	"github.com/robfig/cron/v3"
+	"golang.org/x/oauth2/google"
+)
+
+// daemon runs unattended updates of a saved state on a cron schedule,
+// regenerating outputs and sending notifications after each one
+type daemon struct {
+	statePath   string
+	source      string
+	season      int
+	timeout     time.Duration
+	cache       *cacheFlags
+	client      *clientFlags
+	filter      *filterFlags
+	outputs     urlList
+	gameLog     string
+	bubble      string
+	topN        int
+	spreadsheet string
+	slackURL    string
+	email       *smtpConfig // Nil disables the email report
+	emailTo     []string
+	emailDays   int
+	webhooks    *webhookFlags
+}
+
+// daemonCommand implements the daemon subcommand
+func daemonCommand(fs *flag.FlagSet) func() {
+	d := &daemon{
+		cache:  registerCacheFlags(fs),
+		client: registerClientFlags(fs),
+		filter: registerFilterFlags(fs),
+	}
+	fs.StringVar(&d.statePath, "state", "", "Saved state file to keep updated, created by rating the season if missing (required)")
+	fs.StringVar(&d.source, "source", "espn", "Data source for a new state: "+sourceChoices())
+	fs.IntVar(&d.season, "season", 2025, "Season year for a new state")
+	schedule := fs.String("schedule", "0 6 * * *", "When to update, as a cron spec (5 fields, @daily, or @every 6h; prefix CRON_TZ=Zone for a time zone)")
+	runNow := fs.Bool("run-now", false, "Also update once immediately at startup")
+	fs.DurationVar(&d.timeout, "timeout", 30*time.Minute, "Abort an update after this long (0 = no limit)")
+	fs.Var(&d.outputs, "output", "Rankings file or object storage URL to regenerate, formatted by extension (repeatable)")
+	fs.StringVar(&d.gameLog, "gamelog", "", "Game log file or object storage URL to regenerate")
+	fs.StringVar(&d.bubble, "bubble", "", "Bubble watch file or object storage URL to regenerate: JSON by .json extension, else a text table")
+	fs.IntVar(&d.topN, "top", 25, "Teams in outputs and notifications")
+	fs.StringVar(&d.spreadsheet, "spreadsheet", os.Getenv("NCAA_ELO_SPREADSHEET"), "Google Sheet ID or URL to update (env: NCAA_ELO_SPREADSHEET)")
+	notify := fs.String("notify", "", "Comma-separated notifications after new games: slack, email (configured by the notify command's environment variables)")
+	fs.IntVar(&d.emailDays, "email-days", 7, "Days of rating changes and upsets in the email report")
+	d.webhooks = registerWebhookFlags(fs)
+	return func() {
+		if d.statePath == "" {
+			fmt.Fprintln(os.Stderr, "Usage: ncaa-bayes-elo daemon -state <file> [-schedule '0 6 * * *']")
+			os.Exit(1)
+		}
+		if err := d.filter.check(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sched, err := cron.ParseStandard(*schedule)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid -schedule %q: %v\n", *schedule, err)
+			os.Exit(1)
+		}
+		for _, dest := range append([]string{d.gameLog, d.bubble}, d.outputs...) {
+			if err := checkOutputPath(dest); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if m := spreadsheetURLPattern.FindStringSubmatch(d.spreadsheet); m != nil {
+			d.spreadsheet = m[1]
+		}
+		if err := d.configureNotify(*notify); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(0)
+		defer cancel()
+
+		if *runNow {
+			d.runOnce(ctx)
+		}
+		for {
+			next := sched.Next(time.Now())
+			fmt.Printf("Next update at %s\n", next.Format("2006-01-02 15:04:05 MST"))
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				fmt.Println("\nStopping daemon")
+				return
+			case <-timer.C:
+				d.runOnce(ctx)
+			}
+		}
+	}
+}
+
+// configureNotify enables the requested notifications, checking that their
+// settings are present in the environment
+func (d *daemon) configureNotify(list string) error {
+	for _, target := range strings.Split(list, ",") {
+		switch target = strings.TrimSpace(target); target {
+		case "":
+		case "slack":
+			if d.slackURL = os.Getenv("SLACK_WEBHOOK_URL"); d.slackURL == "" {
+				return errors.New("-notify slack requires SLACK_WEBHOOK_URL")
+			}
+		case "email":
+			d.email = &smtpConfig{
+				Host:     os.Getenv("SMTP_HOST"),
+				Port:     envInt("SMTP_PORT", 587),
+				Username: os.Getenv("SMTP_USERNAME"),
+				Password: os.Getenv("SMTP_PASSWORD"),
+				From:     os.Getenv("SMTP_FROM"),
+			}
+			d.emailTo = splitAddresses(os.Getenv("NCAA_ELO_EMAIL_TO"))
+			if d.email.Host == "" || d.email.From == "" || len(d.emailTo) == 0 {
+				return errors.New("-notify email requires SMTP_HOST, SMTP_FROM, and NCAA_ELO_EMAIL_TO")
+			}
+		default:
+			return fmt.Errorf("unknown -notify target %q (expected slack or email)", target)
+		}
+	}
+	return nil
+}
+
+// runOnce performs one scheduled update, logging rather than exiting on
+// errors so the daemon keeps running
+func (d *daemon) runOnce(ctx context.Context) {
+	fmt.Printf("[%s] Starting update\n", time.Now().Format("2006-01-02 15:04:05"))
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+	if err := d.update(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: update failed: %v\n", err)
+		return
+	}
+	fmt.Printf("[%s] Update finished\n", time.Now().Format("2006-01-02 15:04:05"))
+}
+
+// update applies new games to the state, creating it if needed, then
+// publishes the results
+func (d *daemon) update(ctx context.Context) error {
+	elo, source, season, err := d.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	today, err := d.client.today()
+	if err != nil {
+		return err
+	}
+	before, ranks := len(elo.GameLog), elo.Ranks()
+	if dates := updateDates(elo, season, today); len(dates) > 0 {
+		failed, err := applyUpdate(ctx, elo, source, season, dates, d.cache, d.client, d.filter)
+		if err != nil {
+			return err
+		}
+		if len(failed) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %d dates could not be fetched: %s\n", len(failed), formatDates(failed))
+		}
+		if err := elo.SaveState(d.statePath, source, season); err != nil {
+			return fmt.Errorf("saving state: %w", err)
+		}
+	} else {
+		fmt.Printf("Season %d has no dates left to update\n", season)
+	}
+	if len(elo.Teams) == 0 {
+		fmt.Println("No completed games yet; skipping outputs and notifications")
+		return nil
+	}
+
+	// Failures below are independent, so each is reported and the rest still run
+	d.writeOutputs(ctx, elo, season)
+	d.writeBubble(ctx, elo, source, season)
+	if len(elo.GameLog) == before {
+		return nil
+	}
+	d.webhooks.send(ctx, d.webhooks.alerts(elo, before, ranks))
+	d.sendNotifications(ctx, elo, season)
+	return nil
+}
+
+// loadState reads the saved state, rating the configured season and saving
+// it first if the file doesn't exist yet
+func (d *daemon) loadState(ctx context.Context) (*model.BayesianELO, string, int, error) {
+	elo, state, err := model.LoadState(d.statePath)
+	if err == nil {
+		if state.Source == "" || state.Season == 0 {
+			return nil, "", 0, errors.New("state file does not record its source and season")
+		}
+		return elo, state.Source, state.Season, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, "", 0, fmt.Errorf("loading state: %w", err)
+	}
+
+	fmt.Printf("No state at %s; rating the %s %d-%d season\n", d.statePath, d.source, d.season-1, d.season)
+	clientConfig, err := d.client.config(d.source, d.cache.cacheDir())
+	if err != nil {
+		return nil, "", 0, err
+	}
+	store, err := openGameStore(ctx, d.cache)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if closer, ok := store.(io.Closer); ok {
+		defer closer.Close()
+	}
+	filters, err := d.filter.fetched(ctx, d.source, clientConfig)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if elo, err = rateSeason(ctx, store, d.source, d.season, sources.DateRange{}, d.cache, clientConfig, false, nil, filters...); err != nil {
+		return nil, "", 0, err
+	}
+	if err := elo.SaveState(d.statePath, d.source, d.season); err != nil {
+		return nil, "", 0, fmt.Errorf("saving state: %w", err)
+	}
+	return elo, d.source, d.season, nil
+}
+
+// writeOutputs regenerates the rankings files, game log, and spreadsheet
+func (d *daemon) writeOutputs(ctx context.Context, elo *model.BayesianELO, season int) {
+	rankings := elo.GetRankings()
+	teams := output.Ranked(elo, rankings[:min(d.topN, len(rankings))])
+
+	for _, dest := range d.outputs {
+		err := writeOutput(ctx, dest, func(path string) error { return writeRankingsFile(path, elo, teams, season) })
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", dest, err)
+			continue
+		}
+		fmt.Printf("Output written to %s\n", dest)
+	}
+
+	if d.gameLog != "" {
+		err := writeOutput(ctx, d.gameLog, func(path string) error { return writeGameLog(path, elo.GameLog) })
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing game log: %v\n", err)
+		} else {
+			fmt.Printf("Game log written to %s\n", d.gameLog)
+		}
+	}
+
+	if d.spreadsheet != "" {
+		client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/spreadsheets")
+		if err == nil {
+			err = pushSheets(ctx, client, d.spreadsheet, rankingsSheets(elo, teams))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating spreadsheet: %v\n", err)
+		} else {
+			fmt.Printf("Spreadsheet %s updated\n", d.spreadsheet)
+		}
+	}
+}
+
+// writeBubble regenerates the bubble watch, simulating the rest of the season
+// with the bubble command's defaults
+func (d *daemon) writeBubble(ctx context.Context, elo *model.BayesianELO, source string, season int) {
+	if d.bubble == "" {
+		return
+	}
+	schedule, err := remainingGames(ctx, elo, source, season, d.cache, d.client)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching schedule for the bubble watch: %v\n", err)
+		return
+	}
+	rng, seed := newRNG(0)
+	watch, err := bubbleWatch(elo, schedule, defaultFieldSize, defaultBubbleRank, 10000, defaultBubbleShow, rng)
+	if err == nil {
+		watch.Season, watch.Seed = season, seed
+		err = writeOutput(ctx, d.bubble, func(file string) error {
+			text := formatBubbleWatch(watch)
+			if strings.EqualFold(path.Ext(file), ".json") {
+				data, _ := json.MarshalIndent(watch, "", "  ")
+				text = string(data) + "\n"
+			}
+			return os.WriteFile(file, []byte(text), 0644)
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing bubble watch: %v\n", err)
+	} else {
+		fmt.Printf("Bubble watch written to %s\n", d.bubble)
+	}
+}
+
+// sendNotifications posts the Slack summary and emails the report
+func (d *daemon) sendNotifications(ctx context.Context, elo *model.BayesianELO, season int) {
+	if d.slackURL != "" {
+		if days := feedDays(elo, 1, d.topN); len(days) > 0 {
+			body, err := json.Marshal(slackMessage(days[0], season))
+			if err == nil {
+				err = postJSON(ctx, &http.Client{Timeout: 10 * time.Second}, d.slackURL, body)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error posting to Slack: %v\n", err)
+			} else {
+				fmt.Println("Posted rankings to Slack")
+			}
+		}
+	}
+
+	if d.email != nil {
+		report := buildPeriodReport(elo, season, d.topN, d.emailDays)
+		msg, err := report.email(d.email.From, d.emailTo, "")
+		if err == nil {
+			err = sendEmail(*d.email, d.emailTo, msg)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error sending email: %v\n", err)
+		} else {
+			fmt.Printf("Emailed the report to %d recipients\n", len(d.emailTo))
+		}
+	}
+}
+
+// writeRankingsFile writes rankings to path in the format its extension names:
+// .html, .json, .jsonl, .csv, .atom or .xml, .rss, .parquet, .arrow, or .xlsx
+// (with team detail and game log sheets), otherwise a text table
+func writeRankingsFile(file string, elo *model.BayesianELO, teams []output.Team, season int) error {
+	var text string
+	var err error
+	switch strings.ToLower(path.Ext(file)) {
+	case ".xlsx":
+		return writeRankingsWorkbook(file, elo, teams)
+	case ".parquet":
+		return writeBinary(output.FormatParquet, file, teams)
+	case ".arrow":
+		return writeBinary(output.FormatArrow, file, teams)
+	case ".jsonl":
+		return writeJSONLines(file, teams)
+	case ".json":
+		text = output.JSON(teams)
+	case ".csv":
+		text = output.CSV(teams)
+	case ".html", ".htm":
+		text, err = formatHTML(elo, teams, season)
+	case ".atom", ".xml":
+		text, err = formatAtom(feedDays(elo, defaultFeedDays, len(teams)), season, defaultFeedURL)
+	case ".rss":
+		text, err = formatRSS(feedDays(elo, defaultFeedDays, len(teams)), season, defaultFeedURL)
+	default:
+		text = output.Table(teams, season, output.Trends(elo, 7), 7, output.TableStyle{})
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(text), 0644)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discordCommand implements the discord command: run a Discord bot answering
+// rating commands from the loaded model, refreshing it with newly completed
+// games on a schedule
+func discordCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	token := fs.String("token", os.Getenv("DISCORD_BOT_TOKEN"), "Discord bot token (env: DISCORD_BOT_TOKEN)")
+	prefix := fs.String("prefix", "!", "Command prefix")
+	refreshInterval := fs.Duration("refresh-interval", time.Hour, "How often to fetch and apply newly completed games (0 = never)")
+	return func() {
+		if *token == "" {
+			fmt.Fprintln(os.Stderr, "Error: set DISCORD_BOT_TOKEN or -token")
+			os.Exit(1)
+		}
+
+		loadCtx, cancelLoad := commandContext(*engine.timeout)
+		elo := engine.mustLoad(loadCtx)
+		cancelLoad()
+
+		ctx, cancel := commandContext(0)
+		defer cancel()
+
+		s := newRatingServer(elo, *engine.dataSource, *engine.season)
+		if *refreshInterval > 0 {
+			go s.refreshLoop(ctx, engine, *refreshInterval)
+		}
+
+		session, err := discordgo.New("Bot " + *token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		// Reading command text requires the privileged message content intent
+		session.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsDirectMessages | discordgo.IntentMessageContent
+
+		session.AddHandler(func(ds *discordgo.Session, m *discordgo.MessageCreate) {
+			if m.Author == nil || m.Author.Bot {
+				return
+			}
+			reply, ok := s.botCommand(*prefix, m.Content)
+			if !ok {
+				return
+			}
+			if _, err := ds.ChannelMessageSendReply(m.ChannelID, reply, m.Reference()); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not reply in channel %s: %v\n", m.ChannelID, err)
+			}
+		})
+
+		if err := session.Open(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error connecting to Discord: %v\n", err)
+			os.Exit(1)
+		}
+		defer session.Close()
+
+		fmt.Printf("Discord bot connected as %s with %d teams\n", session.State.User.Username, len(elo.Teams))
+		<-ctx.Done()
+		fmt.Println("\nStopping Discord bot")
+	}
+}
+
+// botCommand answers a chat command, reporting false for messages that
+// aren't commands
+func (s *ratingServer) botCommand(prefix, content string) (string, bool) {
+	if !strings.HasPrefix(content, prefix) {
+		return "", false
+	}
+	command, arg, _ := strings.Cut(strings.TrimPrefix(content, prefix), " ")
+	arg = strings.TrimSpace(arg)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var reply string
+	switch strings.ToLower(command) {
+	case "rank":
+		reply = s.botRankings(arg)
+	case "team":
+		reply = s.botTeam(arg)
+	case "predict":
+		reply = s.botPredict(arg)
+	case "help":
+		reply = fmt.Sprintf("`%[1]srank [N]` top N teams (default 10)\n`%[1]steam <name>` a team's rating\n`%[1]spredict <team> vs <team>` matchup odds", prefix)
+	default:
+		return "", false
+	}
+	return reply, true
+}
+
+// botRankings answers "rank [N]" with a table of the top N teams
+func (s *ratingServer) botRankings(arg string) string {
+	n := 10
+	if arg != "" {
+		var err error
+		if n, err = strconv.Atoi(arg); err != nil || n < 1 {
+			return fmt.Sprintf("Invalid count %q", arg)
+		}
+	}
+	// Longer tables would exceed Discord's 2000 character message limit
+	rankings := s.elo.GetRankings()
+	rankings = rankings[:min(n, 25, len(rankings))]
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**Top %d** (updated %s)\n```\n", len(rankings), s.updated.Format("2006-01-02 15:04")))
+	for i, team := range rankings {
+		sb.WriteString(fmt.Sprintf("%2d. %-28s %7.1f ± %.0f\n", i+1, team.TeamName, team.Dist.Mean(), team.Dist.Std()))
+	}
+	sb.WriteString("```")
+	return sb.String()
+}
+
+// botTeam answers "team <name>" with the team's rank and rating distribution
+func (s *ratingServer) botTeam(arg string) string {
+	if arg == "" {
+		return "Usage: team <name>"
+	}
+	team, err := findTeam(s.elo, arg)
+	if err != nil {
+		return capitalize(err.Error())
+	}
+
+	t := output.NewTeam(s.ranks()[team.TeamID], team)
+	reply := fmt.Sprintf("**#%d %s**: %.1f ± %.1f (90%% interval %.0f-%.0f)",
+		t.Rank, t.TeamName, t.MeanELO, t.StdDev, t.Pct5, t.Pct95)
+
+	// Rating change over the team's last few game days
+	if history := s.elo.History[team.TeamID]; len(history) > 1 {
+		from := history[max(0, len(history)-6)]
+		reply += fmt.Sprintf("\n%s since %s", output.FormatTrend(t.MeanELO-from.Mean), from.Date)
+	}
+	return reply
+}
+
+// botPredict answers "predict <team> vs <team>"
+func (s *ratingServer) botPredict(arg string) string {
+	a, b, err := splitMatchup(s.elo, arg)
+	if err != nil {
+		return capitalize(err.Error())
+	}
+	prob, err := s.elo.PredictMatchup(a.TeamID, b.TeamID)
+	if err != nil {
+		return capitalize(err.Error())
+	}
+	return fmt.Sprintf("**%s** %.1f%% vs **%s** %.1f%% (neutral site)", a.TeamName, prob*100, b.TeamName, (1-prob)*100)
+}
+
+// splitMatchup resolves "A vs B" or "A, B" into two teams. Without a
+// separator, it tries each split point and accepts the one where both halves
+// name a team, so "michigan state duke" works.
+func splitMatchup(elo *model.BayesianELO, arg string) (*model.TeamRating, *model.TeamRating, error) {
+	for _, sep := range []string{" vs. ", " vs ", ","} {
+		if left, right, ok := strings.Cut(strings.ToLower(arg), sep); ok {
+			a, err := findTeam(elo, left)
+			if err != nil {
+				return nil, nil, err
+			}
+			b, err := findTeam(elo, right)
+			if err != nil {
+				return nil, nil, err
+			}
+			return a, b, nil
+		}
+	}
+
+	words := strings.Fields(arg)
+	if len(words) < 2 {
+		return nil, nil, fmt.Errorf("usage: predict <team> vs <team>")
+	}
+	var found [][2]*model.TeamRating
+	for i := 1; i < len(words); i++ {
+		a, errA := findTeam(elo, strings.Join(words[:i], " "))
+		b, errB := findTeam(elo, strings.Join(words[i:], " "))
+		if errA == nil && errB == nil && a != b {
+			found = append(found, [2]*model.TeamRating{a, b})
+		}
+	}
+	if len(found) != 1 {
+		return nil, nil, fmt.Errorf("couldn't tell the two teams apart in %q; separate them with \"vs\"", arg)
+	}
+	return found[0][0], found[0][1], nil
+}
+
+// capitalize upper-cases the first letter of a message
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
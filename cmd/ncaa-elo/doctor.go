@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// DoctorIssue is one anomaly found in a season's games
+type DoctorIssue struct {
+	Kind   string `json:"kind"`
+	GameID string `json:"game_id,omitempty"`
+	Date   string `json:"date,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// DoctorReport is every anomaly found in a season's games, by kind
+type DoctorReport struct {
+	Source    string         `json:"source"`
+	Season    int            `json:"season"`
+	Games     int            `json:"games"`
+	Completed int            `json:"completed"`
+	Counts    map[string]int `json:"counts"` // Issues of each kind
+	Issues    []DoctorIssue  `json:"issues"`
+}
+
+// Kinds of issue the doctor reports, in the order they're listed
+var doctorKinds = []string{
+	"duplicate", "missing_team_id", "zero_zero", "no_winner", "impossible_date", "single_game_team", "source_mismatch",
+}
+
+// doctorCommand implements the doctor command: scan a season's fetched games for
+// anomalies that would distort the ratings
+func doctorCommand(fs *flag.FlagSet) func() {
+	dataSource := fs.String("source", "espn", "Data source: "+sourceChoices())
+	season := fs.Int("season", 2025, "Season year (e.g., 2025 for 2024-2025 season)")
+	compare := fs.String("compare", "", "Another source ("+sourceChoices()+") to compare completed game counts with, date by date")
+	maxShown := fs.Int("max", 10, "Issues to list of each kind in the table (the JSON report has all)")
+	timeout := fs.Duration("timeout", 0, "Abort fetching after this long (e.g. 10m; 0 = no limit)")
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	cacheOpts := registerCacheFlags(fs)
+	clientOpts := registerClientFlags(fs)
+	return func() {
+		if *compare == *dataSource {
+			fmt.Fprintln(os.Stderr, "Error: -compare needs a different source than -source")
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*timeout)
+		defer cancel()
+
+		store, err := openGameStore(ctx, cacheOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if closer, ok := store.(io.Closer); ok {
+			defer closer.Close()
+		}
+		seasonGames := func(source string) []model.Game {
+			clientConfig, err := clientOpts.config(source, cacheOpts.cacheDir())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			games, failed, err := loadGames(ctx, store, source, *season, sources.DateRange{}, cacheOpts, clientConfig)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error fetching %s games: %v\n", source, err)
+				os.Exit(1)
+			}
+			if len(failed) > 0 {
+				fmt.Fprintf(os.Stderr, "Warning: %d %s dates could not be fetched: %s\n", len(failed), source, formatDates(failed))
+			}
+			return games
+		}
+
+		games := seasonGames(*dataSource)
+		report := diagnoseGames(games, *season, time.Now())
+		report.Source = *dataSource
+		if *compare != "" {
+			for _, issue := range compareSources(games, seasonGames(*compare), *dataSource, *compare) {
+				report.add(issue)
+			}
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Print(formatDoctorReport(report, *maxShown))
+		}
+		if len(report.Issues) > 0 {
+			os.Exit(1)
+		}
+	}
+}
+
+// add records an issue
+func (r *DoctorReport) add(issue DoctorIssue) {
+	r.Issues = append(r.Issues, issue)
+	r.Counts[issue.Kind]++
+}
+
+// diagnoseGames checks a season's games for duplicates, missing team IDs,
+// scoreless or winnerless finals, dates outside the season or in the future,
+// and teams with only one completed game
+func diagnoseGames(games []model.Game, season int, now time.Time) DoctorReport {
+	report := DoctorReport{Season: season, Games: len(games), Counts: make(map[string]int), Issues: []DoctorIssue{}}
+	first := time.Date(season-1, time.July, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(season, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	byID := make(map[string]model.Game)
+	byMatchup := make(map[string]model.Game)
+	played := make(map[string]int)
+	names := make(map[string]string)
+	for _, g := range games {
+		date := g.Date.Format("2006-01-02")
+		matchup := fmt.Sprintf("%s at %s", g.AwayTeam, g.HomeTeam)
+		issue := func(kind, detail string) {
+			report.add(DoctorIssue{Kind: kind, GameID: g.ID, Date: date, Detail: detail})
+		}
+
+		if prev, ok := byID[g.ID]; ok && g.ID != "" {
+			issue("duplicate", fmt.Sprintf("%s listed again (first on %s)", matchup, prev.Date.Format("2006-01-02")))
+		} else if prev, ok := byMatchup[matchupKey(date, g.HomeTeamID, g.AwayTeamID)]; ok && g.HomeTeamID != "" && g.AwayTeamID != "" {
+			issue("duplicate", fmt.Sprintf("%s appears twice on this date (also game %s)", matchup, prev.ID))
+		}
+		byID[g.ID] = g
+		byMatchup[matchupKey(date, g.HomeTeamID, g.AwayTeamID)] = g
+
+		if g.HomeTeamID == "" || g.AwayTeamID == "" {
+			issue("missing_team_id", matchup+" is missing a team ID")
+		}
+		switch {
+		case g.Date.IsZero():
+			issue("impossible_date", matchup+" has no date")
+		case g.Date.Before(first) || g.Date.After(last):
+			issue("impossible_date", fmt.Sprintf("%s falls outside the %d-%d season", matchup, season-1, season))
+		case g.Completed && g.Date.After(now):
+			issue("impossible_date", matchup+" is final but dated in the future")
+		}
+		if !g.Completed {
+			continue
+		}
+		report.Completed++
+		if g.HomeScore == 0 && g.AwayScore == 0 {
+			issue("zero_zero", matchup+" is final at 0-0")
+		}
+		if g.WinnerID == "" || (g.WinnerID != g.HomeTeamID && g.WinnerID != g.AwayTeamID) {
+			issue("no_winner", fmt.Sprintf("%s is final at %d-%d with no winner among its teams", matchup, g.AwayScore, g.HomeScore))
+		}
+		for id, name := range map[string]string{g.HomeTeamID: g.HomeTeam, g.AwayTeamID: g.AwayTeam} {
+			played[id]++
+			names[id] = name
+		}
+	}
+
+	var single []string
+	for id, n := range played {
+		if n == 1 && id != "" {
+			single = append(single, id)
+		}
+	}
+	sort.Slice(single, func(i, j int) bool { return names[single[i]] < names[single[j]] })
+	for _, id := range single {
+		report.add(DoctorIssue{Kind: "single_game_team", Detail: fmt.Sprintf("%s (%s) has only one completed game", names[id], id)})
+	}
+	return report
+}
+
+// compareSources reports the dates where two sources disagree on how many
+// games were completed. Team IDs differ between sources, so games are only
+// counted, not matched.
+func compareSources(games, other []model.Game, source, otherSource string) []DoctorIssue {
+	counts := func(games []model.Game) map[string]int {
+		byDate := make(map[string]int)
+		for _, g := range games {
+			if g.Completed {
+				byDate[g.Date.Format("2006-01-02")]++
+			}
+		}
+		return byDate
+	}
+	mine, theirs := counts(games), counts(other)
+	dates := make(map[string]bool)
+	for date := range mine {
+		dates[date] = true
+	}
+	for date := range theirs {
+		dates[date] = true
+	}
+	var sorted []string
+	for date := range dates {
+		if mine[date] != theirs[date] {
+			sorted = append(sorted, date)
+		}
+	}
+	sort.Strings(sorted)
+
+	var issues []DoctorIssue
+	for _, date := range sorted {
+		issues = append(issues, DoctorIssue{
+			Kind:   "source_mismatch",
+			Date:   date,
+			Detail: fmt.Sprintf("%s has %d completed games, %s has %d", source, mine[date], otherSource, theirs[date]),
+		})
+	}
+	return issues
+}
+
+// formatDoctorReport renders the report as text, listing up to maxShown
+// issues of each kind
+func formatDoctorReport(r DoctorReport, maxShown int) string {
+	var sb strings.Builder
+	width := 80
+
+	sb.WriteString(fmt.Sprintf("Data Check: %s %d-%d Season\n", r.Source, r.Season-1, r.Season))
+	sb.WriteString(fmt.Sprintf("%d games, %d completed, %d issues\n", r.Games, r.Completed, len(r.Issues)))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	if len(r.Issues) == 0 {
+		sb.WriteString("No problems found\n")
+	}
+	for _, kind := range doctorKinds {
+		if r.Counts[kind] == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n%s (%d)\n", kind, r.Counts[kind]))
+		sb.WriteString(strings.Repeat("-", width) + "\n")
+		shown := 0
+		for _, issue := range r.Issues {
+			if issue.Kind != kind {
+				continue
+			}
+			if shown == maxShown {
+				sb.WriteString(fmt.Sprintf("  ... and %d more\n", r.Counts[kind]-shown))
+				break
+			}
+			line := fmt.Sprintf("  %-10s %-10s %s", issue.Date, issue.GameID, issue.Detail)
+			sb.WriteString(strings.TrimRight(line, " ") + "\n")
+			shown++
+		}
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	return sb.String()
+}
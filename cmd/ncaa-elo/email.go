@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"math"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// Number of movers and upsets listed in a report
+const reportListLen = 5
+
+// reportRow is one team's line in a periodic report
+type reportRow struct {
+	Rank     int
+	TeamName string
+	Rating   float64
+	StdDev   float64
+	Change   float64 // Over the report period
+}
+
+// periodReport summarizes the rankings and the games of the last few days
+type periodReport struct {
+	Title   string
+	Since   string // First date of the period
+	Through string // Last game date
+	Top     []reportRow
+	Movers  []reportRow
+	Upsets  []GameLogOutput
+}
+
+// buildPeriodReport summarizes the top teams, biggest movers, and biggest
+// upsets over the days before the last game date
+func buildPeriodReport(elo *model.BayesianELO, season, topN, days int) periodReport {
+	report := periodReport{
+		Title:   feedTitle(season),
+		Through: elo.LastGameDate(),
+	}
+	if last, err := time.Parse("2006-01-02", report.Through); err == nil {
+		report.Since = last.AddDate(0, 0, -days+1).Format("2006-01-02")
+	}
+
+	trends := output.Trends(elo, days)
+	var rows []reportRow
+	for i, team := range elo.GetRankings() {
+		rows = append(rows, reportRow{
+			Rank:     i + 1,
+			TeamName: team.TeamName,
+			Rating:   team.Dist.Mean(),
+			StdDev:   team.Dist.Std(),
+			Change:   trends[team.TeamID],
+		})
+	}
+	report.Top = rows[:min(topN, len(rows))]
+
+	movers := append([]reportRow(nil), rows...)
+	sort.SliceStable(movers, func(a, b int) bool { return math.Abs(movers[a].Change) > math.Abs(movers[b].Change) })
+	for _, m := range movers[:min(reportListLen, len(movers))] {
+		if m.Change != 0 {
+			report.Movers = append(report.Movers, m)
+		}
+	}
+
+	for _, g := range gameLogOutputs(elo.GameLog) {
+		if g.Date >= report.Since && g.WinProb < 0.5 {
+			report.Upsets = append(report.Upsets, g)
+		}
+	}
+	sort.SliceStable(report.Upsets, func(a, b int) bool { return report.Upsets[a].WinProb < report.Upsets[b].WinProb })
+	report.Upsets = report.Upsets[:min(reportListLen, len(report.Upsets))]
+
+	return report
+}
+
+// markdown renders the report as Markdown, which also reads well as plain text
+func (r periodReport) markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s\n\nGames from %s through %s\n\n", r.Title, r.Since, r.Through))
+	sb.WriteString("| Rank | Team | Rating | StdDev | Change |\n|---:|---|---:|---:|---:|\n")
+	for _, t := range r.Top {
+		sb.WriteString(fmt.Sprintf("| %d | %s | %.1f | %.1f | %s |\n", t.Rank, t.TeamName, t.Rating, t.StdDev, output.FormatTrend(t.Change)))
+	}
+
+	if len(r.Movers) > 0 {
+		sb.WriteString("\n## Biggest movers\n\n")
+		for _, m := range r.Movers {
+			sb.WriteString(fmt.Sprintf("- #%d %s: %s to %.1f\n", m.Rank, m.TeamName, output.FormatTrend(m.Change), m.Rating))
+		}
+	}
+
+	if len(r.Upsets) > 0 {
+		sb.WriteString("\n## Biggest upsets\n\n")
+		for _, g := range r.Upsets {
+			sb.WriteString(fmt.Sprintf("- %s: %s (%.1f%%) beat %s\n", g.Date, g.WinnerName, g.WinProb*100, g.LoserName))
+		}
+	}
+	return sb.String()
+}
+
+// html renders the report with inline styles, since mail clients ignore
+// style sheets and scripts
+func (r periodReport) html() (string, error) {
+	var sb strings.Builder
+	if err := emailTemplate.Execute(&sb, r); err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// email builds the report as a message, defaulting the subject to the title
+// and dates
+func (r periodReport) email(from string, to []string, subject string) ([]byte, error) {
+	html, err := r.html()
+	if err != nil {
+		return nil, err
+	}
+	if subject == "" {
+		subject = fmt.Sprintf("%s: %s to %s", r.Title, r.Since, r.Through)
+	}
+	return buildEmail(from, to, subject, r.markdown(), html)
+}
+
+var emailTemplate = template.Must(template.New("email").Funcs(template.FuncMap{
+	"trend": output.FormatTrend,
+	"pct":   func(p float64) string { return fmt.Sprintf("%.1f%%", p*100) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Helvetica, Arial, sans-serif; color: #222;">
+<h2 style="margin-bottom: 0;">{{.Title}}</h2>
+<p style="color: #666; margin-top: 4px;">Games from {{.Since}} through {{.Through}}</p>
+<table style="border-collapse: collapse; font-size: 14px;">
+<tr style="background: #1f4e78; color: #fff;">
+<th style="padding: 4px 8px; text-align: right;">Rank</th><th style="padding: 4px 8px; text-align: left;">Team</th>
+<th style="padding: 4px 8px; text-align: right;">Rating</th><th style="padding: 4px 8px; text-align: right;">StdDev</th>
+<th style="padding: 4px 8px; text-align: right;">Change</th>
+</tr>
+{{range .Top}}<tr style="border-bottom: 1px solid #e4e4e4;">
+<td style="padding: 4px 8px; text-align: right;">{{.Rank}}</td><td style="padding: 4px 8px;">{{.TeamName}}</td>
+<td style="padding: 4px 8px; text-align: right;">{{printf "%.1f" .Rating}}</td><td style="padding: 4px 8px; text-align: right;">{{printf "%.1f" .StdDev}}</td>
+<td style="padding: 4px 8px; text-align: right;">{{trend .Change}}</td>
+</tr>
+{{end}}</table>
+{{if .Movers}}<h3>Biggest movers</h3>
+<ul>{{range .Movers}}<li>#{{.Rank}} {{.TeamName}}: {{trend .Change}} to {{printf "%.1f" .Rating}}</li>{{end}}</ul>
+{{end}}{{if .Upsets}}<h3>Biggest upsets</h3>
+<ul>{{range .Upsets}}<li>{{.Date}}: {{.WinnerName}} ({{pct .WinProb}}) beat {{.LoserName}}</li>{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+// smtpConfig is where and as whom to send mail
+type smtpConfig struct {
+	Host     string
+	Port     int
+	Username string // Empty sends without authenticating
+	Password string
+	From     string
+}
+
+// buildEmail assembles a multipart/alternative message with Markdown plain
+// text and HTML versions of the same body
+func buildEmail(from string, to []string, subject, text, html string) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// splitAddresses parses a comma-separated list of email addresses
+func splitAddresses(list string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(list, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// sendEmail delivers a message over SMTP, upgrading to TLS with STARTTLS when
+// the server offers it
+func sendEmail(cfg smtpConfig, to []string, msg []byte) error {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	addr := net.JoinHostPort(cfg.Host, fmt.Sprint(cfg.Port))
+	if err := smtp.SendMail(addr, auth, cfg.From, to, msg); err != nil {
+		return fmt.Errorf("sending to %s: %w", addr, err)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/cache"
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// engineFlags holds the flags shared by every command that needs a rated
+// season: which season to rate, or a saved state to load instead
+type engineFlags struct {
+	dataSource *string
+	season     *int // The last season of a -season range
+	seasons    *seasonsFlag
+	decay      *float64
+	loadState  *string
+	strict     *bool
+	timeout    *time.Duration
+	checkpoint *string
+	cpInterval *time.Duration
+	aliases    *string
+	asOf       *string
+	start      *string
+	end        *string
+	filter     *filterFlags
+	whatIf     *whatIfList
+	whatIfFile *string
+	cache      *cacheFlags
+	client     *clientFlags
+}
+
+// registerEngineFlags adds season, state, cache, and API client flags to a flag set
+func registerEngineFlags(fs *flag.FlagSet) *engineFlags {
+	seasons := &seasonsFlag{first: 2025, last: 2025}
+	fs.Var(seasons, "season", "Season `year` (e.g., 2025 for 2024-2025 season), or a range rated in sequence as one continuous rating (e.g., 2020-2025)")
+	whatIf := new(whatIfList)
+	fs.Var(whatIf, "what-if", "Rate a made-up result as if played, e.g. \"Duke beats Houston on a neutral court on 3/30\"; repeatable")
+	return &engineFlags{
+		dataSource: fs.String("source", "espn", "Data source: "+sourceChoices()),
+		season:     &seasons.last,
+		seasons:    seasons,
+		decay:      fs.Float64("season-decay", 0.3, "With a -season range, how far to pull each team back toward the prior between seasons (0 = not at all, 1 = start over)"),
+		loadState:  fs.String("load-state", "", "Load a saved rating state instead of fetching and processing games"),
+		strict:     fs.Bool("strict", false, "Fail if any dates could not be fetched after retrying"),
+		timeout:    fs.Duration("timeout", 0, "Abort fetching and processing after this long (e.g. 10m; 0 = no limit)"),
+		checkpoint: fs.String("checkpoint", "", "Periodically save processing progress to this file and resume from it after an interruption"),
+		cpInterval: fs.Duration("checkpoint-interval", time.Minute, "Minimum time between checkpoints"),
+		aliases:    fs.String("aliases", "", "Team aliases file mapping names to team IDs (default: ncaa-bayes-elo/aliases.yaml in the user config dir)"),
+		asOf:       fs.String("as-of", "", "Only process games on or before this date (YYYY-MM-DD), to see the ratings as they stood then"),
+		start:      fs.String("start", "", "Rate only games from this date (YYYY-MM-DD; default: November 1), starting every team from the prior"),
+		end:        fs.String("end", "", "Rate only games through this date (YYYY-MM-DD; default: April 15)"),
+		filter:     registerFilterFlags(fs),
+		whatIf:     whatIf,
+		whatIfFile: fs.String("what-if-file", "", "File of made-up results to rate, one per line in the -what-if form"),
+		cache:      registerCacheFlags(fs),
+		client:     registerClientFlags(fs),
+	}
+}
+
+// load returns a rated engine, either restored from -load-state or built by
+// fetching and processing the season. A loaded state's source and season
+// replace the flag values, and the source's team aliases are loaded for
+// findTeam. The engine has no teams if no games were completed.
+func (f *engineFlags) load(ctx context.Context) (*model.BayesianELO, error) {
+	window, err := f.window()
+	if err != nil {
+		return nil, err
+	}
+	windowed := window != sources.DateRange{}
+	if err := f.filter.check(); err != nil {
+		return nil, err
+	}
+	filteringD1 := f.filter.filteringD1()
+	multiSeason := f.seasons.first < f.seasons.last
+	if multiSeason {
+		switch {
+		case *f.loadState != "":
+			return nil, errors.New("a -season range can't be combined with -load-state")
+		case windowed || *f.checkpoint != "":
+			return nil, errors.New("a -season range can't be combined with -start, -end, -as-of, or -checkpoint")
+		case *f.decay < 0 || *f.decay > 1:
+			return nil, errors.New("-season-decay must be between 0 and 1")
+		}
+	}
+
+	// Overrides and exclusions run first, while games still have the
+	// source's team IDs. The forfeit policy isn't among these filters: it
+	// only applies to fetched games.
+	filters, err := f.filter.corrections()
+	if err != nil {
+		return nil, err
+	}
+	hyps, err := readHypotheticals(*f.whatIf, *f.whatIfFile)
+	if err != nil {
+		return nil, fmt.Errorf("what-if: %w", err)
+	}
+
+	if *f.loadState != "" {
+		// A saved state replaces fetching and processing entirely
+		elo, state, err := model.LoadState(*f.loadState)
+		if err != nil {
+			return nil, fmt.Errorf("loading state: %w", err)
+		}
+		if state.Season != 0 {
+			*f.season = state.Season
+		}
+		if state.Source != "" {
+			*f.dataSource = state.Source
+		}
+		fmt.Fprintf(os.Stderr, "Loaded state from %s (saved %s): %d games for %d teams\n\n",
+			*f.loadState, state.SavedAt.Format("2006-01-02 15:04"), len(elo.GameLog), len(elo.Teams))
+		if err := useAliases(*f.aliases, *f.dataSource); err != nil {
+			return nil, err
+		}
+		if !windowed && !filteringD1 && len(filters) == 0 && len(hyps) == 0 {
+			return elo, nil
+		}
+		if filteringD1 {
+			d1 := make(map[string]bool)
+			for id, team := range elo.Teams {
+				if team.Conference != "" {
+					d1[id] = true
+				}
+			}
+			if len(d1) == 0 {
+				return nil, fmt.Errorf("-d1-only and -merge-non-d1 need conferences to tell Division I teams apart: %w", errNoConferences)
+			}
+			filters = append(filters, f.filter.d1Filter(d1))
+		}
+		if len(hyps) > 0 {
+			filters = append(filters, whatIfFilter(hyps, *f.season))
+		}
+		return replay(ctx, elo, window, filters...)
+	}
+
+	if err := useAliases(*f.aliases, *f.dataSource); err != nil {
+		return nil, err
+	}
+	clientConfig, err := f.client.config(*f.dataSource, f.cache.cacheDir())
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := openGameStore(ctx, f.cache)
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := store.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if filteringD1 {
+		d1, err := fetchD1Teams(ctx, *f.dataSource, clientConfig)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f.filter.d1Filter(d1))
+	}
+	if len(hyps) > 0 {
+		filters = append(filters, whatIfFilter(hyps, *f.season))
+	}
+	fetchFilters := append(f.filter.forfeitFilters(), filters...)
+
+	var elo *model.BayesianELO
+	if multiSeason {
+		elo, err = rateSeasons(ctx, store, *f.dataSource, f.seasons.first, f.seasons.last, *f.decay, f.cache, clientConfig, *f.strict, fetchFilters...)
+	} else {
+		cp := newCheckpointer(*f.checkpoint, *f.cpInterval, *f.dataSource, *f.season)
+		elo, err = rateSeason(ctx, store, *f.dataSource, *f.season, window, f.cache, clientConfig, *f.strict, cp, fetchFilters...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	assignConferences(ctx, elo, *f.dataSource, clientConfig)
+
+	// Share the latest ratings when the store keeps rating history and
+	// they're the season's as it was played
+	if ratingStore, ok := store.(cache.RatingStore); ok && len(elo.Teams) > 0 && !windowed && !multiSeason && len(filters) == 0 && *f.filter.forfeits == "skip" {
+		if err := ratingStore.SaveRatings(*f.season, *f.dataSource, time.Now(), elo.GetRankings()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not save ratings: %v\n", err)
+		}
+	}
+
+	return elo, nil
+}
+
+// window parses -start, -end, and -as-of (another name for -end) into the
+// date range to rate
+func (f *engineFlags) window() (sources.DateRange, error) {
+	var window sources.DateRange
+	if *f.asOf != "" && *f.end != "" {
+		return window, errors.New("-as-of and -end both set the last date; use one")
+	}
+	for _, d := range []struct {
+		name, value string
+		date        *time.Time
+	}{
+		{"start", *f.start, &window.Start},
+		{"end", *f.end, &window.End},
+		{"as-of", *f.asOf, &window.End},
+	} {
+		if d.value == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", d.value)
+		if err != nil {
+			return window, fmt.Errorf("invalid -%s date %q (expected YYYY-MM-DD)", d.name, d.value)
+		}
+		*d.date = date
+	}
+
+	if !window.Start.IsZero() && !window.End.IsZero() && window.End.Before(window.Start) {
+		return window, errors.New("-start must not be after -end")
+	}
+	if window != (sources.DateRange{}) && *f.checkpoint != "" {
+		return window, errors.New("-start, -end, and -as-of can't be combined with -checkpoint")
+	}
+	return window, nil
+}
+
+// replay reprocesses a loaded state's games within a date range and passing
+// the filters, keeping team conferences
+func replay(ctx context.Context, loaded *model.BayesianELO, window sources.DateRange, filters ...gameFilter) (*model.BayesianELO, error) {
+	games, err := gamesFromLog(loaded.GameLog, window)
+	if err != nil {
+		return nil, err
+	}
+	if window != (sources.DateRange{}) {
+		fmt.Fprintf(os.Stderr, "Replaying %d of %d games %s\n", len(games), len(loaded.GameLog), window)
+	}
+	games = filterGames(games, filters)
+
+	elo := model.NewBayesianELO(loaded.Options()...)
+	if err := processGames(ctx, elo, games, nil); err != nil {
+		return nil, fmt.Errorf("processing games: %w", err)
+	}
+	for id, team := range elo.Teams {
+		if prev, ok := loaded.Teams[id]; ok {
+			team.Conference = prev.Conference
+		}
+	}
+	return elo, nil
+}
+
+// mustLoad is load for commands: errors and seasons without completed games
+// end the program
+func (f *engineFlags) mustLoad(ctx context.Context) *model.BayesianELO {
+	elo, err := f.load(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(elo.Teams) == 0 {
+		fmt.Fprintln(os.Stderr, "No completed games found. Try a different date range or data source.")
+		os.Exit(0)
+	}
+	return elo
+}
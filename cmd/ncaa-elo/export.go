@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// writeBinary writes rows to path in one of the binary output formats
+func writeBinary[T any](format output.Format, path string, rows []T) error {
+	switch format {
+	case output.FormatParquet:
+		return writeParquet(path, rows)
+	case output.FormatArrow:
+		return writeArrow(path, rows, false)
+	case output.FormatArrowStream:
+		return writeArrow(path, rows, true)
+	case output.FormatXLSX:
+		return writeXLSX(path, sheetFromRows("Sheet1", rows))
+	}
+	return fmt.Errorf("%s is not a binary format", format)
+}
+
+// writeJSONLines streams rows as one JSON object per line to path, or to
+// stdout if path is empty
+func writeJSONLines[T any](path string, rows []T) error {
+	out := os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		return out.Close()
+	}
+	return nil
+}
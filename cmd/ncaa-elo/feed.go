@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// feedMovers is how many of a day's biggest rating changes each item lists
+const feedMovers = 5
+
+// Defaults for the game days a feed covers and the link its items point to
+const (
+	defaultFeedDays = 14
+	defaultFeedURL  = "https://github.com/corykiser/NCAA-Bayes-ELO"
+)
+
+// feedRating is a team's rating at the end of a feed day
+type feedRating struct {
+	TeamName string
+	Rating   float64
+	Change   float64 // Since the previous game day
+}
+
+// feedDay summarizes the rankings after one game day
+type feedDay struct {
+	Date   string
+	Top    []feedRating
+	Movers []feedRating
+}
+
+// feedDays rebuilds the top teams and biggest movers after each of the last
+// count game days from the rating history
+func feedDays(elo *model.BayesianELO, count, topN int) []feedDay {
+	dateSet := make(map[string]bool)
+	for _, points := range elo.History {
+		for _, p := range points {
+			dateSet[p.Date] = true
+		}
+	}
+	var dates []string
+	for d := range dateSet {
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+
+	start := max(0, len(dates)-count)
+	var days []feedDay
+	for i := start; i < len(dates); i++ {
+		date := dates[i]
+		var ratings []feedRating
+		for id, team := range elo.Teams {
+			points := elo.History[id]
+			if len(points) == 0 || points[0].Date > date {
+				continue // Not yet played
+			}
+			rating := elo.RatingAsOf(id, date)
+			change := 0.0
+			if i > 0 {
+				change = rating - elo.RatingAsOf(id, dates[i-1])
+			}
+			ratings = append(ratings, feedRating{TeamName: team.TeamName, Rating: rating, Change: change})
+		}
+
+		sort.Slice(ratings, func(a, b int) bool { return ratings[a].Rating > ratings[b].Rating })
+		day := feedDay{Date: date, Top: ratings[:min(topN, len(ratings))]}
+
+		movers := append([]feedRating(nil), ratings...)
+		sort.SliceStable(movers, func(a, b int) bool { return math.Abs(movers[a].Change) > math.Abs(movers[b].Change) })
+		for _, m := range movers[:min(feedMovers, len(movers))] {
+			if m.Change != 0 {
+				day.Movers = append(day.Movers, m)
+			}
+		}
+
+		days = append(days, day)
+	}
+
+	// Newest first, as feed readers expect
+	for i, j := 0, len(days)-1; i < j; i, j = i+1, j-1 {
+		days[i], days[j] = days[j], days[i]
+	}
+	return days
+}
+
+// feedItemHTML renders a day's summary as the HTML body of a feed item
+func feedItemHTML(day feedDay) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("<p>Top %d after games on %s</p>\n<ol>\n", len(day.Top), day.Date))
+	for _, t := range day.Top {
+		sb.WriteString(fmt.Sprintf("<li>%s &ndash; %.1f (%s)</li>\n", html.EscapeString(t.TeamName), t.Rating, output.FormatTrend(t.Change)))
+	}
+	sb.WriteString("</ol>\n")
+
+	if len(day.Movers) > 0 {
+		sb.WriteString("<p>Biggest movers</p>\n<ul>\n")
+		for _, m := range day.Movers {
+			sb.WriteString(fmt.Sprintf("<li>%s: %s to %.1f</li>\n", html.EscapeString(m.TeamName), output.FormatTrend(m.Change), m.Rating))
+		}
+		sb.WriteString("</ul>\n")
+	}
+
+	return sb.String()
+}
+
+// feedTitle titles a feed for a season
+func feedTitle(season int) string {
+	return fmt.Sprintf("NCAA Men's Basketball Bayesian ELO Rankings (%d-%d Season)", season-1, season)
+}
+
+// feedItemTime dates an item at the end of its game day
+func feedItemTime(date string) time.Time {
+	t, _ := time.Parse("2006-01-02", date)
+	return t.Add(24*time.Hour - time.Second)
+}
+
+// Atom 1.0 document structure
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Link    atomLink    `xml:"link"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Link    atomLink    `xml:"link"`
+	Updated string      `xml:"updated"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// formatAtom renders an Atom feed with one entry per game day
+func formatAtom(days []feedDay, season int, link string) (string, error) {
+	feed := atomFeed{
+		Title:   feedTitle(season),
+		ID:      fmt.Sprintf("tag:ncaa-bayes-elo,%d:rankings", season),
+		Link:    atomLink{Href: link},
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: "NCAA Bayesian ELO"},
+	}
+	if len(days) > 0 {
+		feed.Updated = feedItemTime(days[0].Date).Format(time.RFC3339)
+	}
+
+	for _, day := range days {
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   fmt.Sprintf("Rankings after %s", day.Date),
+			ID:      fmt.Sprintf("tag:ncaa-bayes-elo,%d:rankings/%s", season, day.Date),
+			Link:    atomLink{Href: link},
+			Updated: feedItemTime(day.Date).Format(time.RFC3339),
+			Content: atomContent{Type: "html", Body: feedItemHTML(day)},
+		})
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data) + "\n", nil
+}
+
+// RSS 2.0 document structure
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// formatRSS renders an RSS 2.0 feed with one item per game day
+func formatRSS(days []feedDay, season int, link string) (string, error) {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         feedTitle(season),
+			Link:          link,
+			Description:   "Daily Bayesian ELO rankings and biggest movers",
+			LastBuildDate: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	for _, day := range days {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       fmt.Sprintf("Rankings after %s", day.Date),
+			Link:        link,
+			GUID:        rssGUID{Value: fmt.Sprintf("ncaa-bayes-elo-%d-%s", season, day.Date)},
+			PubDate:     feedItemTime(day.Date).Format(time.RFC1123Z),
+			Description: feedItemHTML(day),
+		})
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data) + "\n", nil
+}
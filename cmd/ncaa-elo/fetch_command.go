@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// fetchCommand implements the fetch command: download a season's games into the
+// cache (or PostgreSQL) so later commands can rate it without hitting the API
+func fetchCommand(fs *flag.FlagSet) func() {
+	dataSource := fs.String("source", "espn", "Data source: "+sourceChoices())
+	season := fs.Int("season", 2025, "Season year (e.g., 2025 for 2024-2025 season)")
+	strict := fs.Bool("strict", false, "Fail if any dates could not be fetched after retrying")
+	timeout := fs.Duration("timeout", 0, "Abort fetching after this long (e.g. 10m; 0 = no limit)")
+	cacheOpts := registerCacheFlags(fs)
+	clientOpts := registerClientFlags(fs)
+	return func() {
+		ctx, cancel := commandContext(*timeout)
+		defer cancel()
+
+		clientConfig, err := clientOpts.config(*dataSource, cacheOpts.cacheDir())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		store, err := openGameStore(ctx, cacheOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if store == nil || !cacheOpts.writable() {
+			fmt.Fprintln(os.Stderr, "Error: fetch stores games in the cache, which is unavailable or disabled")
+			os.Exit(1)
+		}
+		if closer, ok := store.(io.Closer); ok {
+			defer closer.Close()
+		}
+
+		games, failed, err := loadGames(ctx, store, *dataSource, *season, sources.DateRange{}, cacheOpts, clientConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching games: %v\n", err)
+			os.Exit(1)
+		}
+
+		completed := 0
+		for _, g := range games {
+			if g.Completed {
+				completed++
+			}
+		}
+		fmt.Printf("Fetched %d games (%d completed) for the %s %d-%d season\n", len(games), completed, *dataSource, *season-1, *season)
+
+		if len(failed) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %d dates could not be fetched: %s\n", len(failed), formatDates(failed))
+			if *strict {
+				os.Exit(1)
+			}
+		}
+	}
+}
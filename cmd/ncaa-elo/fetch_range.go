@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// fetchDates is FetchDates reporting progress on stderr, with a warning
+// listing the dates that failed
+func fetchDates(ctx context.Context, dates []time.Time, workers int, fetch func(context.Context, time.Time) ([]model.Game, error)) (map[time.Time][]model.Game, []time.Time, error) {
+	gamesByDate, failed, err := sources.FetchDates(ctx, dates, workers, fetch, func(label string, total int) sources.Tracker {
+		return newProgress(label, "dates", total)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d dates could not be fetched: %s\n", len(failed), formatDates(failed))
+	}
+	return gamesByDate, failed, nil
+}
+
+// formatDates renders dates as a comma-separated YYYY-MM-DD list
+func formatDates(dates []time.Time) string {
+	strs := make([]string, len(dates))
+	for i, d := range dates {
+		strs[i] = d.Format("2006-01-02")
+	}
+	return strings.Join(strs, ", ")
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// filterFlags holds the flags choosing which fetched games are rated: the
+// forfeit policy, corrections and exclusions, and Division I only
+type filterFlags struct {
+	d1Only     *bool
+	mergeNonD1 *bool
+	overrides  *string
+	forfeits   *string
+	exclude    *exclusionList
+}
+
+// registerFilterFlags adds game filtering flags to a flag set
+func registerFilterFlags(fs *flag.FlagSet) *filterFlags {
+	exclude := new(exclusionList)
+	fs.Var(exclude, "exclude", "Leave out a game by ID (as in the game log), e.g. a forfeit or exhibition; repeatable or comma-separated")
+	return &filterFlags{
+		d1Only:     fs.Bool("d1-only", false, "Drop games against non-Division I opponents"),
+		mergeNonD1: fs.Bool("merge-non-d1", false, "Rate every non-Division I opponent as one \"Non-D1\" team instead of separately"),
+		overrides:  fs.String("overrides", "", "YAML or TOML file correcting games' neutral-site flags, home teams, or scores before rating"),
+		forfeits:   fs.String("forfeits", "skip", "How to rate forfeits: 'skip' them, or 'count' them as wins for the team awarded the game"),
+		exclude:    exclude,
+	}
+}
+
+// check rejects invalid or conflicting filter flags
+func (f *filterFlags) check() error {
+	if *f.d1Only && *f.mergeNonD1 {
+		return errors.New("-d1-only and -merge-non-d1 are alternatives; use one")
+	}
+	if *f.forfeits != "skip" && *f.forfeits != "count" {
+		return fmt.Errorf("invalid -forfeits %q (expected skip or count)", *f.forfeits)
+	}
+	return nil
+}
+
+// filteringD1 reports whether games against non-Division I teams are
+// dropped or merged
+func (f *filterFlags) filteringD1() bool {
+	return *f.d1Only || *f.mergeNonD1
+}
+
+// corrections returns the filters for -overrides and -exclude, which run
+// while games still have the source's team IDs
+func (f *filterFlags) corrections() ([]gameFilter, error) {
+	var overrides []GameOverride
+	if *f.overrides != "" {
+		var err error
+		if overrides, err = loadOverrides(*f.overrides); err != nil {
+			return nil, fmt.Errorf("overrides %s: %w", *f.overrides, err)
+		}
+	}
+	for _, id := range *f.exclude {
+		overrides = append(overrides, GameOverride{ID: id, Exclude: true})
+	}
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+	return []gameFilter{overridesFilter(overrides)}, nil
+}
+
+// forfeitFilters returns the filters for the forfeit policy. It applies only
+// as games are fetched: the game log doesn't record forfeits, so a saved
+// state keeps the policy it was rated with.
+func (f *filterFlags) forfeitFilters() []gameFilter {
+	if *f.forfeits == "skip" {
+		return []gameFilter{skipForfeits}
+	}
+	return nil
+}
+
+// d1Filter returns the -d1-only or -merge-non-d1 filter for the given
+// Division I teams
+func (f *filterFlags) d1Filter(d1 map[string]bool) gameFilter {
+	return nonD1Filter(d1, *f.mergeNonD1)
+}
+
+// fetched returns the filters for games fetched from a source, in the order
+// they run: the forfeit policy, corrections and exclusions, then the
+// Division I filter, whose teams are fetched only if it's needed
+func (f *filterFlags) fetched(ctx context.Context, dataSource string, clientConfig sources.Config) ([]gameFilter, error) {
+	if err := f.check(); err != nil {
+		return nil, err
+	}
+	corrections, err := f.corrections()
+	if err != nil {
+		return nil, err
+	}
+	filters := append(f.forfeitFilters(), corrections...)
+	if f.filteringD1() {
+		d1, err := fetchD1Teams(ctx, dataSource, clientConfig)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f.d1Filter(d1))
+	}
+	return filters, nil
+}
+
+// filterGames runs games through the filters in order
+func filterGames(games []model.Game, filters []gameFilter) []model.Game {
+	for _, filter := range filters {
+		games = filter(games)
+	}
+	return games
+}
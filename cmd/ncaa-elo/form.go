@@ -0,0 +1,61 @@
+package main
+
+import (
+	"sort"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+)
+
+// formRatings rates each team from only its last n games: a fresh prior
+// updated by each result against the opponent's full-season distribution.
+// Opponents stay fixed, so one team's form never feeds into another's.
+func formRatings(elo *model.BayesianELO, n int) map[string]float64 {
+	games := append([]model.GameResult(nil), elo.GameLog...)
+	sort.SliceStable(games, func(i, j int) bool { return games[i].Date < games[j].Date })
+
+	recent := make(map[string][]model.GameResult)
+	for _, g := range games {
+		for _, id := range []string{g.WinnerID, g.LoserID} {
+			recent[id] = append(recent[id], g)
+		}
+	}
+
+	// winProbs[i-j+size-1] is a rating at grid point i beating one at j
+	prior := elo.NewPrior()
+	size := len(prior.Values)
+	winProbs := elo.Likelihood(0)
+
+	form := make(map[string]float64, len(recent))
+	for id, teamGames := range recent {
+		if _, ok := elo.Teams[id]; !ok {
+			continue
+		}
+		dist := prior.Clone()
+		for _, g := range teamGames[max(0, len(teamGames)-n):] {
+			won, opponentID := g.WinnerID == id, g.LoserID
+			if !won {
+				opponentID = g.WinnerID
+			}
+			opponent, ok := elo.Teams[opponentID]
+			if !ok {
+				continue
+			}
+			// Likelihood of the result at each rating, averaged over the opponent's
+			for i := range dist.Values {
+				var likelihood float64
+				for j, q := range opponent.Dist.Probs {
+					if q > 1e-12 {
+						likelihood += q * winProbs[i-j+size-1]
+					}
+				}
+				if !won {
+					likelihood = 1 - likelihood
+				}
+				dist.Probs[i] *= likelihood
+			}
+			dist.Normalize()
+		}
+		form[id] = dist.Mean()
+	}
+	return form
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+	_ "github.com/corykiser/NCAA-Bayes-ELO/sources/espn"
+	_ "github.com/corykiser/NCAA-Bayes-ELO/sources/ncaa"
+)
+
+// newGameSource creates the API client for a named data source
+func newGameSource(dataSource string, clientConfig sources.Config) (sources.Source, error) {
+	return sources.New(dataSource, clientConfig)
+}
+
+// sourceChoices lists the registered sources for flag help, e.g.
+// "'espn' or 'ncaa'"
+func sourceChoices() string {
+	names := sources.Names()
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("'%s'", name)
+	}
+	return orList(quoted)
+}
+
+// orList joins choices as "a", "a or b", or "a, b, or c"
+func orList(choices []string) string {
+	switch len(choices) {
+	case 0:
+		return ""
+	case 1:
+		return choices[0]
+	case 2:
+		return choices[0] + " or " + choices[1]
+	}
+	return strings.Join(choices[:len(choices)-1], ", ") + ", or " + choices[len(choices)-1]
+}
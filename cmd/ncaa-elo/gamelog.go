@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// GameLogOutput is one processed game with ratings before and after it
+type GameLogOutput struct {
+	Date          string  `json:"date" parquet:"date"`
+	GameID        string  `json:"game_id" parquet:"game_id"`
+	WinnerID      string  `json:"winner_id" parquet:"winner_id"`
+	WinnerName    string  `json:"winner_name" parquet:"winner_name"`
+	LoserID       string  `json:"loser_id" parquet:"loser_id"`
+	LoserName     string  `json:"loser_name" parquet:"loser_name"`
+	HomeAdvantage string  `json:"home_advantage" parquet:"home_advantage"`
+	WinProb       float64 `json:"win_prob" parquet:"win_prob"`
+	WinnerPreELO  float64 `json:"winner_pre_elo" parquet:"winner_pre_elo"`
+	WinnerPreStd  float64 `json:"winner_pre_std" parquet:"winner_pre_std"`
+	WinnerPostELO float64 `json:"winner_post_elo" parquet:"winner_post_elo"`
+	WinnerPostStd float64 `json:"winner_post_std" parquet:"winner_post_std"`
+	WinnerDelta   float64 `json:"winner_delta" parquet:"winner_delta"`
+	LoserPreELO   float64 `json:"loser_pre_elo" parquet:"loser_pre_elo"`
+	LoserPreStd   float64 `json:"loser_pre_std" parquet:"loser_pre_std"`
+	LoserPostELO  float64 `json:"loser_post_elo" parquet:"loser_post_elo"`
+	LoserPostStd  float64 `json:"loser_post_std" parquet:"loser_post_std"`
+	LoserDelta    float64 `json:"loser_delta" parquet:"loser_delta"`
+}
+
+// gameLogOutputs converts the engine's game log for export
+func gameLogOutputs(log []model.GameResult) []GameLogOutput {
+	outputs := make([]GameLogOutput, 0, len(log))
+	for _, g := range log {
+		// States saved before post-game ratings were logged have none to compare
+		var winnerDelta, loserDelta float64
+		if g.WinnerPostStd > 0 {
+			winnerDelta = g.WinnerPostELO - g.WinnerELO
+			loserDelta = g.LoserPostELO - g.LoserELO
+		}
+		outputs = append(outputs, GameLogOutput{
+			Date:          g.Date,
+			GameID:        g.GameID,
+			WinnerID:      g.WinnerID,
+			WinnerName:    g.WinnerName,
+			LoserID:       g.LoserID,
+			LoserName:     g.LoserName,
+			HomeAdvantage: g.HomeAdvantage,
+			WinProb:       g.WinProb,
+			WinnerPreELO:  g.WinnerELO,
+			WinnerPreStd:  g.WinnerStd,
+			WinnerPostELO: g.WinnerPostELO,
+			WinnerPostStd: g.WinnerPostStd,
+			WinnerDelta:   winnerDelta,
+			LoserPreELO:   g.LoserELO,
+			LoserPreStd:   g.LoserStd,
+			LoserPostELO:  g.LoserPostELO,
+			LoserPostStd:  g.LoserPostStd,
+			LoserDelta:    loserDelta,
+		})
+	}
+	return outputs
+}
+
+// gamesFromLog rebuilds the games in a game log played within a date range,
+// so they can be processed again. Scores are 1-0 for games logged without
+// them, which is all the engine uses of them.
+func gamesFromLog(log []model.GameResult, window sources.DateRange) ([]model.Game, error) {
+	var games []model.Game
+	for _, g := range log {
+		if !window.Contains(g.Date) {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", g.Date)
+		if err != nil {
+			return nil, fmt.Errorf("game %s has an invalid date: %w", g.GameID, err)
+		}
+		winnerScore, loserScore := g.WinnerScore, g.LoserScore
+		if winnerScore <= loserScore {
+			winnerScore, loserScore = 1, 0
+		}
+		game := model.Game{
+			ID:          g.GameID,
+			Date:        date,
+			HomeTeamID:  g.WinnerID,
+			HomeTeam:    g.WinnerName,
+			AwayTeamID:  g.LoserID,
+			AwayTeam:    g.LoserName,
+			HomeScore:   winnerScore,
+			AwayScore:   loserScore,
+			NeutralSite: g.HomeAdvantage == "N",
+			Completed:   true,
+			WinnerID:    g.WinnerID,
+			State:       "post",
+			Period:      g.Periods,
+		}
+		if g.HomeAdvantage == "A" {
+			game.HomeTeamID, game.AwayTeamID = g.LoserID, g.WinnerID
+			game.HomeTeam, game.AwayTeam = g.LoserName, g.WinnerName
+			game.HomeScore, game.AwayScore = loserScore, winnerScore
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+// writeGameLog writes every processed game to path, as JSON, JSON Lines,
+// Parquet, an Arrow IPC file, or an Excel workbook for .json, .jsonl,
+// .parquet, .arrow, or .xlsx files and CSV otherwise
+func writeGameLog(path string, log []model.GameResult) error {
+	games := gameLogOutputs(log)
+
+	var text string
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".jsonl":
+		return writeJSONLines(path, games)
+	case ".parquet":
+		return writeParquet(path, games)
+	case ".arrow":
+		return writeArrow(path, games, false)
+	case ".xlsx":
+		return writeXLSX(path, sheetFromRows("Game Log", games))
+	case ".json":
+		data, err := json.MarshalIndent(games, "", "  ")
+		if err != nil {
+			return err
+		}
+		text = string(data) + "\n"
+	default:
+		text = formatGameLogCSV(games)
+	}
+
+	return os.WriteFile(path, []byte(text), 0644)
+}
+
+// formatGameLogCSV renders one row per processed game
+func formatGameLogCSV(games []GameLogOutput) string {
+	var sb strings.Builder
+
+	sb.WriteString("date,game_id,winner_id,winner_name,loser_id,loser_name,home_advantage,win_prob," +
+		"winner_pre_elo,winner_pre_std,winner_post_elo,winner_post_std,winner_delta," +
+		"loser_pre_elo,loser_pre_std,loser_post_elo,loser_post_std,loser_delta\n")
+
+	for _, g := range games {
+		sb.WriteString(fmt.Sprintf("%s,%s,%s,\"%s\",%s,\"%s\",%s,%.4f,%.1f,%.1f,%.1f,%.1f,%.1f,%.1f,%.1f,%.1f,%.1f,%.1f\n",
+			g.Date,
+			g.GameID,
+			g.WinnerID,
+			g.WinnerName,
+			g.LoserID,
+			g.LoserName,
+			g.HomeAdvantage,
+			g.WinProb,
+			g.WinnerPreELO,
+			g.WinnerPreStd,
+			g.WinnerPostELO,
+			g.WinnerPostStd,
+			g.WinnerDelta,
+			g.LoserPreELO,
+			g.LoserPreStd,
+			g.LoserPostELO,
+			g.LoserPostStd,
+			g.LoserDelta))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+
+	"github.com/graphql-go/graphql"
+)
+
+// graphqlRanksKey is the context key for the request's team ranks, computed
+// once per query rather than once per team resolved
+type graphqlRanksKey struct{}
+
+// graphqlRequest is a GraphQL query posted as JSON
+type graphqlRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// graphqlHandler serves the GraphQL API over GET (?query=) and POST (JSON body)
+func (s *ratingServer) graphqlHandler() http.Handler {
+	schema, err := s.graphqlSchema()
+	if err != nil {
+		// The schema is static, so this only fails if it's malformed
+		panic("invalid GraphQL schema: " + err.Error())
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		switch r.Method {
+		case http.MethodGet:
+			q := r.URL.Query()
+			req.Query = q.Get("query")
+			req.OperationName = q.Get("operationName")
+			if v := q.Get("variables"); v != "" {
+				if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+					writeError(w, http.StatusBadRequest, "invalid variables: %v", err)
+					return
+				}
+			}
+		case http.MethodPost:
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeError(w, http.StatusMethodNotAllowed, "use GET or POST")
+			return
+		}
+		if req.Query == "" {
+			writeError(w, http.StatusBadRequest, "query is required")
+			return
+		}
+
+		// Resolvers read the engine directly, so the whole query runs under the read lock
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			OperationName:  req.OperationName,
+			VariableValues: req.Variables,
+			Context:        context.WithValue(r.Context(), graphqlRanksKey{}, s.ranks()),
+		})
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// graphqlTeam resolves a team ID to its output at its current rank, or nil
+// for an unrated team
+func (s *ratingServer) graphqlTeam(ctx context.Context, id string) any {
+	team, ok := s.elo.Teams[id]
+	if !ok {
+		return nil
+	}
+	ranks, _ := ctx.Value(graphqlRanksKey{}).(map[string]int)
+	return output.NewTeam(ranks[id], team)
+}
+
+// graphqlSchema builds the GraphQL schema over the server's ratings
+func (s *ratingServer) graphqlSchema() (graphql.Schema, error) {
+	// floatField resolves one of a team's rating statistics
+	floatField := func(get func(output.Team) float64) *graphql.Field {
+		return &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Float),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return get(p.Source.(output.Team)), nil
+			},
+		}
+	}
+
+	distributionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Distribution",
+		Description: "A posterior over the ELO grid",
+		Fields: graphql.Fields{
+			"values": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.Float))),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(*model.Distribution).Values, nil
+				},
+			},
+			"probs": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.Float))),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(*model.Distribution).Probs, nil
+				},
+			},
+		},
+	})
+
+	ratingPointType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "RatingPoint",
+		Description: "A team's rating after a day it played",
+		Fields: graphql.Fields{
+			"date": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(model.RatingPoint).Date, nil
+				},
+			},
+			"mean": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Float),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(model.RatingPoint).Mean, nil
+				},
+			},
+			"stdDev": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Float),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(model.RatingPoint).Std, nil
+				},
+			},
+		},
+	})
+
+	// Teams and scheduled games refer to each other, so their fields are thunks
+	var teamType, scheduledGameType *graphql.Object
+
+	teamType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Team",
+		Description: "A team's rating distribution summary",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(output.Team).TeamID, nil
+					},
+				},
+				"name": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(output.Team).TeamName, nil
+					},
+				},
+				"conference": &graphql.Field{
+					Type:        graphql.String,
+					Description: "Null when the data source doesn't provide conferences",
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						if conference := p.Source.(output.Team).Conference; conference != "" {
+							return conference, nil
+						}
+						return nil, nil
+					},
+				},
+				"rank": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(output.Team).Rank, nil
+					},
+				},
+				"mean":         floatField(func(t output.Team) float64 { return t.MeanELO }),
+				"stdDev":       floatField(func(t output.Team) float64 { return t.StdDev }),
+				"percentile5":  floatField(func(t output.Team) float64 { return t.Pct5 }),
+				"percentile25": floatField(func(t output.Team) float64 { return t.Pct25 }),
+				"median":       floatField(func(t output.Team) float64 { return t.Median }),
+				"percentile75": floatField(func(t output.Team) float64 { return t.Pct75 }),
+				"percentile95": floatField(func(t output.Team) float64 { return t.Pct95 }),
+				"map":          floatField(func(t output.Team) float64 { return t.MAP }),
+				"skewness":     floatField(func(t output.Team) float64 { return t.Skewness }),
+				"ci90Low":      floatField(func(t output.Team) float64 { return t.CI90Low }),
+				"ci90High":     floatField(func(t output.Team) float64 { return t.CI90High }),
+				"distribution": &graphql.Field{
+					Type: graphql.NewNonNull(distributionType),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return s.elo.Teams[p.Source.(output.Team).TeamID].Dist, nil
+					},
+				},
+				"history": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ratingPointType))),
+					Description: "The team's rating after each day it played",
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						points := []model.RatingPoint{}
+						return append(points, s.elo.History[p.Source.(output.Team).TeamID]...), nil
+					},
+				},
+				"upcoming": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(scheduledGameType))),
+					Description: "The team's next games, soonest first",
+					Args: graphql.FieldConfigArgument{
+						"limit": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						games := s.scheduledGames(p.Source.(output.Team).TeamID, "")
+						if limit, ok := p.Args["limit"].(int); ok && limit >= 0 {
+							games = games[:min(limit, len(games))]
+						}
+						return games, nil
+					},
+				},
+			}
+		}),
+	})
+
+	// gameField resolves a plain field of a scheduled game
+	gameField := func(get func(ScheduledGameOutput) any, t graphql.Output) *graphql.Field {
+		return &graphql.Field{
+			Type: t,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return get(p.Source.(ScheduledGameOutput)), nil
+			},
+		}
+	}
+	// probField resolves a pre-game probability, null if either team is unrated
+	probField := func(get func(ScheduledGameOutput) *float64) *graphql.Field {
+		return &graphql.Field{
+			Type: graphql.Float,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if prob := get(p.Source.(ScheduledGameOutput)); prob != nil {
+					return *prob, nil
+				}
+				return nil, nil
+			},
+		}
+	}
+	nonNullString := graphql.NewNonNull(graphql.String)
+
+	scheduledGameType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ScheduledGame",
+		Description: "An upcoming game with the model's pre-game win probabilities",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           gameField(func(g ScheduledGameOutput) any { return g.GameID }, nonNullString),
+				"date":         gameField(func(g ScheduledGameOutput) any { return g.Date }, nonNullString),
+				"state":        gameField(func(g ScheduledGameOutput) any { return g.State }, nonNullString),
+				"neutralSite":  gameField(func(g ScheduledGameOutput) any { return g.NeutralSite }, graphql.NewNonNull(graphql.Boolean)),
+				"homeTeamName": gameField(func(g ScheduledGameOutput) any { return g.HomeTeam }, nonNullString),
+				"awayTeamName": gameField(func(g ScheduledGameOutput) any { return g.AwayTeam }, nonNullString),
+				"home": &graphql.Field{
+					Type:        teamType,
+					Description: "The home team, or null if it is unrated",
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return s.graphqlTeam(p.Context, p.Source.(ScheduledGameOutput).HomeID), nil
+					},
+				},
+				"away": &graphql.Field{
+					Type:        teamType,
+					Description: "The away team, or null if it is unrated",
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return s.graphqlTeam(p.Context, p.Source.(ScheduledGameOutput).AwayID), nil
+					},
+				},
+				"homeWinProb": probField(func(g ScheduledGameOutput) *float64 { return g.HomeWinProb }),
+				"awayWinProb": probField(func(g ScheduledGameOutput) *float64 { return g.AwayWinProb }),
+			}
+		}),
+	})
+
+	predictionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Prediction",
+		Description: "Win probabilities for a neutral-site matchup",
+		Fields: graphql.Fields{
+			"teamA": &graphql.Field{
+				Type: graphql.NewNonNull(teamType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(PredictionOutput).TeamA, nil
+				},
+			},
+			"teamB": &graphql.Field{
+				Type: graphql.NewNonNull(teamType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(PredictionOutput).TeamB, nil
+				},
+			},
+			"probAWins": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Float),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(PredictionOutput).ProbAWins, nil
+				},
+			},
+			"probBWins": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Float),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(PredictionOutput).ProbBWins, nil
+				},
+			},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"source": &graphql.Field{
+				Type: nonNullString,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.source, nil
+				},
+			},
+			"season": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.season, nil
+				},
+			},
+			"updated": &graphql.Field{
+				Type:        nonNullString,
+				Description: "When the ratings were last refreshed (RFC 3339)",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.updated.Format(time.RFC3339), nil
+				},
+			},
+			"rankings": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(teamType))),
+				Description: "Ranked teams, all unless top is given",
+				Args: graphql.FieldConfigArgument{
+					"top": &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					rankings := s.elo.GetRankings()
+					if top, ok := p.Args["top"].(int); ok && top >= 0 {
+						rankings = rankings[:min(top, len(rankings))]
+					}
+					return output.Ranked(s.elo, rankings), nil
+				},
+			},
+			"team": &graphql.Field{
+				Type: teamType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: nonNullString},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.graphqlTeam(p.Context, p.Args["id"].(string)), nil
+				},
+			},
+			"predict": &graphql.Field{
+				Type: predictionType,
+				Args: graphql.FieldConfigArgument{
+					"a": &graphql.ArgumentConfig{Type: nonNullString},
+					"b": &graphql.ArgumentConfig{Type: nonNullString},
+					"site": &graphql.ArgumentConfig{
+						Type:        graphql.String,
+						Description: "a's venue: home, away, or neutral (the default)",
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					a, b := p.Args["a"].(string), p.Args["b"].(string)
+					site, _ := p.Args["site"].(string)
+					edge, err := siteEdge(s.elo, site)
+					if err != nil {
+						return nil, err
+					}
+					prob, err := s.elo.PredictMatchupAt(a, b, edge)
+					if err != nil {
+						return nil, err
+					}
+					ranks, _ := p.Context.Value(graphqlRanksKey{}).(map[string]int)
+					return PredictionOutput{
+						TeamA:     output.NewTeam(ranks[a], s.elo.Teams[a]),
+						TeamB:     output.NewTeam(ranks[b], s.elo.Teams[b]),
+						ProbAWins: prob,
+						ProbBWins: 1 - prob,
+					}, nil
+				},
+			},
+			"schedule": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(scheduledGameType))),
+				Description: "Upcoming games, optionally for one team or date (YYYY-MM-DD)",
+				Args: graphql.FieldConfigArgument{
+					"team": &graphql.ArgumentConfig{Type: graphql.String},
+					"date": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					team, _ := p.Args["team"].(string)
+					date, _ := p.Args["date"].(string)
+					return s.scheduledGames(team, date), nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
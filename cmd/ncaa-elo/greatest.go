@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// SeasonTeam is one team's season, ranked among every season rated
+type SeasonTeam struct {
+	Rank       int     `json:"rank"`
+	Season     int     `json:"season"`
+	SeasonRank int     `json:"season_rank"`
+	TeamID     string  `json:"team_id"`
+	TeamName   string  `json:"team_name"`
+	Conference string  `json:"conference,omitempty"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	MeanELO    float64 `json:"mean_elo"`
+	StdDev     float64 `json:"std_dev"`
+}
+
+// greatestCommand implements the greatest command: rate each season in the
+// -season range on its own and list the best single-season teams
+func greatestCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	topN := fs.Int("top", 25, "Number of team-seasons to display")
+	outputFormat := fs.String("format", "table", "Output format: 'table', 'json', or 'csv'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ncaa-bayes-elo greatest -season <first>-<last> [flags]")
+		fmt.Fprintln(fs.Output(), "  Each season in the range is rated on its own from the same prior, so ratings share one scale.")
+		fs.PrintDefaults()
+	}
+	return func() {
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		first, last := engine.seasons.first, engine.seasons.last
+		teams, err := greatestTeams(ctx, engine, first, last)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(teams) == 0 {
+			fmt.Fprintln(os.Stderr, "No completed games found. Try a different season range or data source.")
+			os.Exit(0)
+		}
+		teams = teams[:min(*topN, len(teams))]
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			fmt.Println(output.JSON(teams))
+		case output.FormatCSV:
+			fmt.Print(formatGreatestCSV(teams))
+		default:
+			fmt.Print(formatGreatestTable(teams, first, last))
+		}
+	}
+}
+
+// greatestTeams rates each season from first to last independently and ranks
+// every team-season together by mean rating. Seasons without completed games
+// are skipped.
+func greatestTeams(ctx context.Context, engine *engineFlags, first, last int) ([]SeasonTeam, error) {
+	var teams []SeasonTeam
+	for season := first; season <= last; season++ {
+		fmt.Fprintf(os.Stderr, "Season %d-%d\n", season-1, season)
+		elo, err := engine.loadSeason(ctx, season)
+		if err != nil {
+			return nil, fmt.Errorf("season %d: %w", season, err)
+		}
+		records := model.Records(elo.GameLog)
+		for i, team := range elo.GetRankings() {
+			r := records[team.TeamID]
+			teams = append(teams, SeasonTeam{
+				Season:     season,
+				SeasonRank: i + 1,
+				TeamID:     team.TeamID,
+				TeamName:   team.TeamName,
+				Conference: team.Conference,
+				Wins:       r.Wins,
+				Losses:     r.Losses,
+				MeanELO:    team.Dist.Mean(),
+				StdDev:     team.Dist.Std(),
+			})
+		}
+	}
+
+	sort.SliceStable(teams, func(i, j int) bool { return teams[i].MeanELO > teams[j].MeanELO })
+	for i := range teams {
+		teams[i].Rank = i + 1
+	}
+	return teams, nil
+}
+
+// formatGreatestTable renders the best team-seasons as a text table
+func formatGreatestTable(teams []SeasonTeam, first, last int) string {
+	var sb strings.Builder
+	width := 92
+
+	sb.WriteString(fmt.Sprintf("Greatest Single-Season Teams (%d-%d through %d-%d)\n", first-1, first, last-1, last))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-4s %-7s %-30s %-16s %7s %8s %8s %6s\n", "Rank", "Season", "Team", "Conference", "W-L", "Mean", "StdDev", "Yr Rk"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, t := range teams {
+		sb.WriteString(fmt.Sprintf("%-4d %-7s %-30s %-16s %7s %8.1f %8.1f %6d\n",
+			t.Rank, fmt.Sprintf("%d-%02d", t.Season-1, t.Season%100), output.Truncate(t.TeamName, 30), output.Truncate(t.Conference, 16),
+			fmt.Sprintf("%d-%d", t.Wins, t.Losses), t.MeanELO, t.StdDev, t.SeasonRank))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString("\nEvery season starts from the same prior; Yr Rk is the team's rank within its season.\n")
+	return sb.String()
+}
+
+// formatGreatestCSV renders the best team-seasons as CSV
+func formatGreatestCSV(teams []SeasonTeam) string {
+	var sb strings.Builder
+	sb.WriteString("rank,season,season_rank,team_id,team_name,conference,wins,losses,mean_elo,std_dev\n")
+	for _, t := range teams {
+		sb.WriteString(fmt.Sprintf("%d,%d,%d,%s,\"%s\",\"%s\",%d,%d,%.1f,%.1f\n",
+			t.Rank, t.Season, t.SeasonRank, t.TeamID, t.TeamName, t.Conference, t.Wins, t.Losses, t.MeanELO, t.StdDev))
+	}
+	return sb.String()
+}
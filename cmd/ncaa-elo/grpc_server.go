@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/ratingspb"
+)
+
+// grpcRatings implements the Ratings gRPC service on top of a ratingServer
+type grpcRatings struct {
+	ratingspb.UnimplementedRatingsServer
+	s *ratingServer
+}
+
+// serveGRPC starts the gRPC API in the background, stopping when ctx is done
+func (s *ratingServer) serveGRPC(ctx context.Context, addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("gRPC listen: %w", err)
+	}
+
+	server := grpc.NewServer()
+	ratingspb.RegisterRatingsServer(server, &grpcRatings{s: s})
+
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: gRPC server stopped: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("Serving gRPC on %s\n", addr)
+	return nil
+}
+
+// teamMessage converts a team's rating at a rank, optionally with its posterior
+func teamMessage(rank int, team *model.TeamRating, withDist bool) *ratingspb.Team {
+	t := output.NewTeam(rank, team)
+	msg := &ratingspb.Team{
+		Id:            t.TeamID,
+		Name:          t.TeamName,
+		Rank:          int32(t.Rank),
+		Mean:          t.MeanELO,
+		StdDev:        t.StdDev,
+		Percentile_5:  t.Pct5,
+		Percentile_25: t.Pct25,
+		Median:        t.Median,
+		Percentile_75: t.Pct75,
+		Percentile_95: t.Pct95,
+	}
+	if withDist {
+		msg.Distribution = &ratingspb.Distribution{Values: team.Dist.Values, Probs: team.Dist.Probs}
+	}
+	return msg
+}
+
+// ranks maps team IDs to their current rank. Callers hold the read lock.
+func (s *ratingServer) ranks() map[string]int {
+	return s.elo.Ranks()
+}
+
+// GetRankings implements ratingspb.RatingsServer
+func (g *grpcRatings) GetRankings(ctx context.Context, req *ratingspb.GetRankingsRequest) (*ratingspb.GetRankingsResponse, error) {
+	g.s.mu.RLock()
+	defer g.s.mu.RUnlock()
+
+	rankings := g.s.elo.GetRankings()
+	if req.Top < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid top %d", req.Top)
+	}
+	if req.Top > 0 {
+		rankings = rankings[:min(int(req.Top), len(rankings))]
+	}
+
+	resp := &ratingspb.GetRankingsResponse{
+		Source:      g.s.source,
+		Season:      int32(g.s.season),
+		UpdatedUnix: g.s.updated.Unix(),
+	}
+	for i, team := range rankings {
+		resp.Teams = append(resp.Teams, teamMessage(i+1, team, req.IncludeDistributions))
+	}
+	return resp, nil
+}
+
+// GetTeam implements ratingspb.RatingsServer
+func (g *grpcRatings) GetTeam(ctx context.Context, req *ratingspb.GetTeamRequest) (*ratingspb.Team, error) {
+	g.s.mu.RLock()
+	defer g.s.mu.RUnlock()
+
+	team, ok := g.s.elo.Teams[req.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "team %s not found", req.Id)
+	}
+	return teamMessage(g.s.ranks()[req.Id], team, true), nil
+}
+
+// Predict implements ratingspb.RatingsServer
+func (g *grpcRatings) Predict(ctx context.Context, req *ratingspb.PredictRequest) (*ratingspb.Prediction, error) {
+	g.s.mu.RLock()
+	defer g.s.mu.RUnlock()
+
+	if req.TeamA == "" || req.TeamB == "" {
+		return nil, status.Error(codes.InvalidArgument, "team_a and team_b are required")
+	}
+	edge, err := siteEdge(g.s.elo, req.Site)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	prob, err := g.s.elo.PredictMatchupAt(req.TeamA, req.TeamB, edge)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	ranks := g.s.ranks()
+	return &ratingspb.Prediction{
+		TeamA:     teamMessage(ranks[req.TeamA], g.s.elo.Teams[req.TeamA], false),
+		TeamB:     teamMessage(ranks[req.TeamB], g.s.elo.Teams[req.TeamB], false),
+		ProbAWins: prob,
+		ProbBWins: 1 - prob,
+	}, nil
+}
+
+// GetHistory implements ratingspb.RatingsServer
+func (g *grpcRatings) GetHistory(ctx context.Context, req *ratingspb.GetHistoryRequest) (*ratingspb.GetHistoryResponse, error) {
+	g.s.mu.RLock()
+	defer g.s.mu.RUnlock()
+
+	if _, ok := g.s.elo.Teams[req.Id]; !ok {
+		return nil, status.Errorf(codes.NotFound, "team %s not found", req.Id)
+	}
+
+	resp := &ratingspb.GetHistoryResponse{TeamId: req.Id}
+	for _, p := range g.s.elo.History[req.Id] {
+		resp.Points = append(resp.Points, &ratingspb.RatingPoint{Date: p.Date, Mean: p.Mean, StdDev: p.Std})
+	}
+	return resp, nil
+}
+
+// WatchRatings implements ratingspb.RatingsServer, streaming a snapshot now
+// and after every refresh that applies games
+func (g *grpcRatings) WatchRatings(req *ratingspb.WatchRatingsRequest, stream ratingspb.Ratings_WatchRatingsServer) error {
+	want := make(map[string]bool, len(req.TeamIds))
+	for _, id := range req.TeamIds {
+		want[id] = true
+	}
+
+	applied := 0
+	for {
+		g.s.mu.RLock()
+		update := &ratingspb.RatingUpdate{UpdatedUnix: g.s.updated.Unix(), GamesApplied: int32(applied)}
+		for i, team := range g.s.elo.GetRankings() {
+			if len(want) == 0 || want[team.TeamID] {
+				update.Teams = append(update.Teams, teamMessage(i+1, team, false))
+			}
+		}
+		changed := g.s.changed
+		g.s.mu.RUnlock()
+
+		if err := stream.Send(update); err != nil {
+			return err
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-changed:
+		}
+
+		g.s.mu.RLock()
+		applied = g.s.applied
+		g.s.mu.RUnlock()
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// HistoryOutput is one team's rating at the end of a day for JSON/CSV/Parquet/Arrow output
+type HistoryOutput struct {
+	TeamID   string  `json:"team_id" parquet:"team_id"`
+	TeamName string  `json:"team_name" parquet:"team_name"`
+	Date     string  `json:"date" parquet:"date"`
+	MeanELO  float64 `json:"mean_elo" parquet:"mean_elo"`
+	StdDev   float64 `json:"std_dev" parquet:"std_dev"`
+}
+
+// historyCommand implements the history command: print the day-by-day rating
+// trajectory of one team, or export every team's trajectory for charting
+func historyCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	teamID := fs.String("team", "", "Team ID or name to show (default: all teams)")
+	outputFormat := fs.String("format", "table", "Output format: 'table', 'json', 'jsonl', 'csv', 'parquet', 'arrow', 'arrow-stream', or 'xlsx'")
+	outputFile := fs.String("output", "", "Output file or s3://bucket/key or gs://bucket/key URL (default: stdout)")
+	return func() {
+		if err := checkOutputPath(*outputFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if output.Format(*outputFormat).Binary() && *outputFile == "" {
+			fmt.Fprintf(os.Stderr, "Error: -format %s requires -output\n", *outputFormat)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+
+		elo := engine.mustLoad(ctx)
+
+		var teamIDs []string
+		if *teamID != "" {
+			team, err := findTeam(elo, *teamID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			teamIDs = []string{team.TeamID}
+		} else {
+			for _, team := range elo.GetRankings() {
+				teamIDs = append(teamIDs, team.TeamID)
+			}
+		}
+
+		var points []HistoryOutput
+		for _, id := range teamIDs {
+			for _, p := range elo.History[id] {
+				points = append(points, HistoryOutput{
+					TeamID:   id,
+					TeamName: elo.Teams[id].TeamName,
+					Date:     p.Date,
+					MeanELO:  p.Mean,
+					StdDev:   p.Std,
+				})
+			}
+		}
+		if len(points) == 0 {
+			fmt.Println("No rating history recorded. States saved before history was tracked must be rebuilt.")
+			return
+		}
+
+		if output.Format(*outputFormat).Binary() {
+			err := writeOutput(ctx, *outputFile, func(path string) error { return writeBinary(output.Format(*outputFormat), path, points) })
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Output written to %s\n", *outputFile)
+			return
+		}
+
+		if output.Format(*outputFormat) == output.FormatJSONL {
+			err := writeOutput(ctx, *outputFile, func(path string) error { return writeJSONLines(path, points) })
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+				os.Exit(1)
+			}
+			if *outputFile != "" {
+				fmt.Fprintf(os.Stderr, "Output written to %s\n", *outputFile)
+			}
+			return
+		}
+
+		var text string
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(points, "", "  ")
+			text = string(data) + "\n"
+		case output.FormatCSV:
+			text = formatHistoryCSV(points)
+		default:
+			text = formatHistoryTable(points)
+		}
+
+		if *outputFile != "" {
+			err := writeOutput(ctx, *outputFile, func(path string) error { return os.WriteFile(path, []byte(text), 0644) })
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Output written to %s\n", *outputFile)
+		} else {
+			fmt.Print(text)
+		}
+	}
+}
+
+// formatHistoryTable renders each team's trajectory with the change from its previous day
+func formatHistoryTable(points []HistoryOutput) string {
+	var sb strings.Builder
+
+	// Keep each team's rows together in the order given
+	var order []string
+	byTeam := make(map[string][]HistoryOutput)
+	for _, p := range points {
+		if _, ok := byTeam[p.TeamID]; !ok {
+			order = append(order, p.TeamID)
+		}
+		byTeam[p.TeamID] = append(byTeam[p.TeamID], p)
+	}
+
+	for _, id := range order {
+		teamPoints := byTeam[id]
+		sort.SliceStable(teamPoints, func(i, j int) bool { return teamPoints[i].Date < teamPoints[j].Date })
+
+		sb.WriteString(fmt.Sprintf("\nRating History: %s (ID: %s)\n", teamPoints[0].TeamName, id))
+		sb.WriteString(strings.Repeat("=", 44) + "\n")
+		sb.WriteString(fmt.Sprintf("%-12s %10s %8s %10s\n", "Date", "Mean ELO", "StdDev", "Change"))
+		sb.WriteString(strings.Repeat("-", 44) + "\n")
+		for i, p := range teamPoints {
+			change := ""
+			if i > 0 {
+				change = fmt.Sprintf("%+.1f", p.MeanELO-teamPoints[i-1].MeanELO)
+			}
+			sb.WriteString(fmt.Sprintf("%-12s %10.1f %8.1f %10s\n", p.Date, p.MeanELO, p.StdDev, change))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatHistoryCSV renders one row per team per day, ready for charting
+func formatHistoryCSV(points []HistoryOutput) string {
+	var sb strings.Builder
+
+	sb.WriteString("team_id,team_name,date,mean_elo,std_dev\n")
+
+	for _, p := range points {
+		sb.WriteString(fmt.Sprintf("%s,\"%s\",%s,%.1f,%.1f\n",
+			p.TeamID,
+			p.TeamName,
+			p.Date,
+			p.MeanELO,
+			p.StdDev))
+	}
+
+	return sb.String()
+}
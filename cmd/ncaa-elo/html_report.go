@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// reportTeam is one team's data embedded in the HTML report
+type reportTeam struct {
+	output.Team
+	Sparkline string      `json:"-"`
+	History   []float64   `json:"history"`
+	Dist      [][]float64 `json:"dist"` // [value, probability] pairs with non-negligible mass
+}
+
+// reportData is everything the HTML report template renders
+type reportData struct {
+	Title       string
+	Generated   string
+	Games       int
+	Conferences bool // Whether to show the conference column
+	Teams       []reportTeam
+}
+
+// formatHTML renders a self-contained HTML report: a sortable rankings table
+// with trajectory sparklines, and each team's posterior chart on click
+func formatHTML(elo *model.BayesianELO, teams []output.Team, season int) (string, error) {
+	data := reportData{
+		Title:       fmt.Sprintf("NCAA Men's Basketball Bayesian ELO Rankings (%d-%d Season)", season-1, season),
+		Generated:   time.Now().Format("2006-01-02 15:04:05"),
+		Games:       len(elo.GameLog),
+		Conferences: output.HasConferences(teams),
+	}
+
+	for _, t := range teams {
+		team := reportTeam{Team: t}
+		for _, p := range elo.History[t.TeamID] {
+			team.History = append(team.History, p.Mean)
+		}
+		team.Sparkline = sparklinePoints(team.History, 120, 24)
+
+		// Trim the grid's empty tails to keep the page small
+		dist := elo.Teams[t.TeamID].Dist
+		for i, v := range dist.Values {
+			if dist.Probs[i] >= 1e-5 {
+				team.Dist = append(team.Dist, []float64{v, dist.Probs[i]})
+			}
+		}
+		data.Teams = append(data.Teams, team)
+	}
+
+	var sb strings.Builder
+	if err := reportTemplate.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// sparklinePoints scales a series into SVG polyline points within width x height
+func sparklinePoints(series []float64, width, height float64) string {
+	if len(series) < 2 {
+		return ""
+	}
+
+	lo, hi := series[0], series[0]
+	for _, v := range series {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	span := hi - lo
+	if span == 0 {
+		span = 1
+	}
+
+	points := make([]string, len(series))
+	for i, v := range series {
+		x := float64(i) / float64(len(series)-1) * width
+		y := height - 2 - (v-lo)/span*(height-4)
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	return strings.Join(points, " ")
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1100px; color: #222; }
+h1 { font-size: 1.5em; margin-bottom: 0.2em; }
+.meta { color: #666; margin-top: 0; }
+table { border-collapse: collapse; width: 100%; font-size: 0.9em; }
+th, td { padding: 4px 8px; text-align: right; border-bottom: 1px solid #e4e4e4; }
+th { background: #1f4e78; color: #fff; cursor: pointer; user-select: none; position: sticky; top: 0; }
+th.sorted-asc::after { content: " \25B2"; }
+th.sorted-desc::after { content: " \25BC"; }
+td.name, th.name { text-align: left; }
+tbody tr { cursor: pointer; }
+tbody tr:hover { background: #f2f6fa; }
+tr.detail td { background: #fafafa; text-align: left; }
+polyline { fill: none; stroke: #1f4e78; stroke-width: 1.5; }
+canvas { max-width: 100%; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">Generated {{.Generated}} from {{.Games}} games. Click a column to sort, or a team to see its rating distribution.</p>
+<table id="rankings">
+<thead>
+<tr>
+<th data-type="num">Rank</th><th class="name" data-type="str">Team</th>{{if .Conferences}}<th class="name" data-type="str">Conference</th>{{end}}<th data-type="num">W-L</th>
+<th data-type="num">Mean</th><th data-type="num">StdDev</th><th data-type="num">5th%</th>
+<th data-type="num">Median</th><th data-type="num">95th%</th><th data-type="none">Trajectory</th>
+</tr>
+</thead>
+<tbody>
+{{range $i, $t := .Teams}}<tr data-team="{{$i}}">
+<td>{{$t.Rank}}</td><td class="name">{{$t.TeamName}}</td>{{if $.Conferences}}<td class="name">{{$t.Conference}}</td>{{end}}<td data-sort="{{$t.Wins}}">{{$t.Wins}}-{{$t.Losses}}</td>
+<td>{{printf "%.1f" $t.MeanELO}}</td><td>{{printf "%.1f" $t.StdDev}}</td><td>{{printf "%.1f" $t.Pct5}}</td>
+<td>{{printf "%.1f" $t.Median}}</td><td>{{printf "%.1f" $t.Pct95}}</td>
+<td>{{if $t.Sparkline}}<svg width="120" height="24"><polyline points="{{$t.Sparkline}}"/></svg>{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+<script>
+const teams = {{.Teams}};
+
+// Sort rows by the clicked column, toggling direction on repeat clicks
+document.querySelectorAll("#rankings th").forEach((th, col) => {
+  if (th.dataset.type === "none") return;
+  th.addEventListener("click", () => {
+    const tbody = document.querySelector("#rankings tbody");
+    closeDetail();
+    const asc = !th.classList.contains("sorted-asc");
+    document.querySelectorAll("#rankings th").forEach(h => h.classList.remove("sorted-asc", "sorted-desc"));
+    th.classList.add(asc ? "sorted-asc" : "sorted-desc");
+    const key = row => {
+      const cell = row.children[col];
+      const text = cell.dataset.sort || cell.textContent;
+      return th.dataset.type === "num" ? parseFloat(text) : text.toLowerCase();
+    };
+    const rows = Array.from(tbody.rows);
+    rows.sort((a, b) => (key(a) < key(b) ? -1 : key(a) > key(b) ? 1 : 0) * (asc ? 1 : -1));
+    rows.forEach(r => tbody.appendChild(r));
+  });
+});
+
+function closeDetail() {
+  document.querySelectorAll("tr.detail").forEach(r => r.remove());
+}
+
+// Show the team's posterior distribution below its row
+document.querySelectorAll("#rankings tbody tr").forEach(row => {
+  row.addEventListener("click", () => {
+    const open = row.nextElementSibling && row.nextElementSibling.classList.contains("detail");
+    closeDetail();
+    if (open) return;
+    const team = teams[row.dataset.team];
+    const detail = document.createElement("tr");
+    detail.className = "detail";
+    const cell = document.createElement("td");
+    cell.colSpan = 9;
+    const canvas = document.createElement("canvas");
+    canvas.width = 1000;
+    canvas.height = 220;
+    cell.appendChild(canvas);
+    detail.appendChild(cell);
+    row.after(detail);
+    drawDistribution(canvas, team);
+  });
+});
+
+function drawDistribution(canvas, team) {
+  const ctx = canvas.getContext("2d");
+  const pad = 30, w = canvas.width - 2 * pad, h = canvas.height - 2 * pad;
+  const lo = team.dist[0][0], hi = team.dist[team.dist.length - 1][0];
+  const peak = Math.max(...team.dist.map(d => d[1]));
+  const x = v => pad + (hi > lo ? (v - lo) / (hi - lo) : 0.5) * w;
+  const barWidth = Math.max(1, w / team.dist.length - 1);
+
+  ctx.fillStyle = "#1f4e78";
+  team.dist.forEach(([v, p]) => {
+    const barHeight = p / peak * h;
+    ctx.fillRect(x(v) - barWidth / 2, pad + h - barHeight, barWidth, barHeight);
+  });
+
+  ctx.fillStyle = "#222";
+  ctx.font = "12px sans-serif";
+  ctx.fillText(team.team_name + ": mean " + team.mean_elo.toFixed(1) + ", std " + team.std_dev.toFixed(1), pad, pad - 10);
+  ctx.fillText(lo.toFixed(0), pad, canvas.height - 8);
+  ctx.fillText(hi.toFixed(0), pad + w - 30, canvas.height - 8);
+
+  // Mark the mean
+  ctx.strokeStyle = "#c0392b";
+  ctx.beginPath();
+  ctx.moveTo(x(team.mean_elo), pad);
+  ctx.lineTo(x(team.mean_elo), pad + h);
+  ctx.stroke();
+}
+</script>
+</body>
+</html>
+`))
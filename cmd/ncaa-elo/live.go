@@ -0,0 +1,254 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// liveCommand implements the live command: poll today's scoreboard, showing
+// in-progress games alongside pre-game model probabilities and applying
+// results as games go final
+func liveCommand(fs *flag.FlagSet) func() {
+	dataSource := fs.String("source", "espn", "Data source: "+sourceChoices())
+	season := fs.Int("season", 2025, "Season year (e.g., 2025 for 2024-2025 season)")
+	interval := fs.Int("interval", 60, "Seconds between scoreboard polls")
+	metricsAddr := registerMetricsFlag(fs)
+	wsAddr := fs.String("ws-addr", "", "Push game and rating events to WebSocket clients at /ws on this address (e.g. :8081)")
+	webhooks := registerWebhookFlags(fs)
+	cacheOpts := registerCacheFlags(fs)
+	clientOpts := registerClientFlags(fs)
+	filter := registerFilterFlags(fs)
+	return func() {
+		if *interval < 1 {
+			fmt.Fprintf(os.Stderr, "Invalid interval: %d (must be at least 1 second)\n", *interval)
+			os.Exit(1)
+		}
+
+		// Runs until interrupted
+		ctx, cancel := commandContext(0)
+		defer cancel()
+
+		startMetricsServer(*metricsAddr)
+		events := newEventHub()
+		startEventServer(*wsAddr, events)
+
+		fmt.Fprintln(os.Stderr, "NCAA Bayesian ELO Live Scoreboard")
+		fmt.Fprintln(os.Stderr, "=================================")
+		fmt.Fprintf(os.Stderr, "Season: %d-%d\n", *season-1, *season)
+		fmt.Fprintf(os.Stderr, "Data Source: %s\n\n", *dataSource)
+
+		clientConfig, err := clientOpts.config(*dataSource, cacheOpts.cacheDir())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		source, err := newGameSource(*dataSource, clientConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		store, err := openGameStore(ctx, cacheOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// The same filters run on earlier results and on games as they go final
+		filters, err := filter.fetched(ctx, *dataSource, clientConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		games, _, err := loadGames(ctx, store, *dataSource, *season, sources.DateRange{}, cacheOpts, clientConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching games: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Today's games are handled by the polling loop, so only rate earlier
+		// results. Days turn over in the -timezone zone games are dated by.
+		loc := clientConfig.Location
+		today := sources.GameDay(time.Now(), loc)
+		todayKey := today.Format("2006-01-02")
+		var completedGames []model.Game
+		for _, g := range games {
+			if g.Completed && g.Date.Format("2006-01-02") < todayKey {
+				completedGames = append(completedGames, g)
+			}
+		}
+		completedGames = filterGames(completedGames, filters)
+
+		elo := model.NewBayesianELO()
+		start := time.Now()
+		if err := processGames(ctx, elo, completedGames, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error processing games: %v\n", err)
+			os.Exit(1)
+		}
+		recordRun(elo, len(completedGames), start)
+
+		pregame := make(map[string]float64) // Home win probability before tip-off
+		applied := make(map[string]bool)
+
+		// Earlier days stay on the board until their last games go final, so a
+		// late game still going after midnight isn't dropped
+		days := []time.Time{today}
+		for {
+			var scoreboard []model.Game
+			var polling []time.Time
+			fetched := false
+			for _, day := range days {
+				games, err := source.GetDate(ctx, day)
+				if ctx.Err() != nil {
+					fmt.Println("\nStopping live scoreboard")
+					return
+				}
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not fetch scoreboard for %s: %v\n", day.Format("2006-01-02"), err)
+					polling = append(polling, day)
+					continue
+				}
+				fetched = true
+				scoreboard = append(scoreboard, games...)
+				if day.Equal(today) || !allFinal(games) {
+					polling = append(polling, day)
+				}
+			}
+			days = polling
+
+			if fetched {
+				for _, g := range scoreboard {
+					key := g.Key()
+					if _, seen := pregame[key]; !seen {
+						edge := elo.HomeAdvantage
+						if g.NeutralSite {
+							edge = 0
+						}
+						if prob, err := elo.PredictMatchupAt(g.HomeTeamID, g.AwayTeamID, edge); err == nil {
+							pregame[key] = prob
+						} else {
+							pregame[key] = -1
+						}
+					}
+					if _, problem := model.GameOutcome(g); g.Completed && problem != "" && !applied[key] {
+						fmt.Fprintf(os.Stderr, "Warning: not rating %s at %s: %s\n", g.AwayTeam, g.HomeTeam, problem)
+						applied[key] = true
+					}
+					if g.Completed && !applied[key] {
+						applied[key] = true
+						for _, rated := range filterGames([]model.Game{g}, filters) {
+							start := time.Now()
+							logged, before, ranks := len(elo.GameLog), ratingMeans(elo), elo.Ranks()
+							elo.ProcessGame(rated)
+							recordRun(elo, 1, start)
+							events.publish(ratingEvents(elo, logged, before)...)
+							webhooks.send(ctx, webhooks.alerts(elo, logged, ranks))
+							fmt.Printf("FINAL: %s %d, %s %d - ratings updated\n",
+								rated.AwayTeam, rated.AwayScore, rated.HomeTeam, rated.HomeScore)
+						}
+					}
+				}
+				fmt.Print(formatLiveScoreboard(scoreboard, pregame, loc))
+			}
+
+			select {
+			case <-ctx.Done():
+				fmt.Println("\nStopping live scoreboard")
+				return
+			case <-time.After(time.Duration(*interval) * time.Second):
+			}
+
+			// Add the next day's scoreboard after midnight
+			if day := sources.GameDay(time.Now(), loc); !day.Equal(today) {
+				today = day
+				days = append(days, day)
+			}
+		}
+	}
+}
+
+// allFinal reports whether every game is over: final, or called off
+func allFinal(games []model.Game) bool {
+	for _, g := range games {
+		if !g.Completed && g.Status == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// formatLiveScoreboard renders the scoreboard with pre-game home/away
+// probabilities, giving times in loc
+func formatLiveScoreboard(games []model.Game, pregame map[string]float64, loc *time.Location) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("\nLive Scoreboard - %s\n", time.Now().In(loc).Format("2006-01-02 15:04:05 MST")))
+	sb.WriteString(strings.Repeat("=", 107) + "\n")
+	sb.WriteString(fmt.Sprintf("%-12s %-30s %5s  %-30s %5s %6s %6s %6s\n",
+		"Status", "Away", "Score", "Home", "Score", "Away%", "Home%", "Live%"))
+	sb.WriteString(strings.Repeat("-", 107) + "\n")
+
+	for _, g := range games {
+		awayPct, homePct, livePct := "   n/a", "   n/a", "   n/a"
+		if prob := pregame[g.Key()]; prob >= 0 {
+			awayPct = fmt.Sprintf("%5.1f%%", (1-prob)*100)
+			homePct = fmt.Sprintf("%5.1f%%", prob*100)
+			livePct = fmt.Sprintf("%5.1f%%", model.LiveHomeWinProbability(prob, g)*100)
+		}
+
+		awayScore, homeScore := "", ""
+		if g.State != "pre" {
+			awayScore = fmt.Sprintf("%d", g.AwayScore)
+			homeScore = fmt.Sprintf("%d", g.HomeScore)
+		}
+
+		sb.WriteString(fmt.Sprintf("%-12s %-30s %5s  %-30s %5s %6s %6s %6s\n",
+			liveStatus(g, loc),
+			output.Truncate(g.AwayTeam, 30),
+			awayScore,
+			output.Truncate(g.HomeTeam, 30),
+			homeScore,
+			awayPct,
+			homePct,
+			livePct))
+	}
+
+	sb.WriteString(strings.Repeat("=", 107) + "\n")
+	sb.WriteString("Away%/Home% are pre-game model estimates; Live% is the home team's in-game win probability.\n")
+
+	return sb.String()
+}
+
+// liveStatus describes a game's progress for the scoreboard, with the
+// tip-off time in loc for games not started, when the source gives one
+func liveStatus(g model.Game, loc *time.Location) string {
+	switch {
+	case g.Completed || g.State == "post":
+		return "Final"
+	case g.State == "in":
+		switch {
+		case g.Period == 1:
+			return "1st " + g.Clock
+		case g.Period == 2:
+			return "2nd " + g.Clock
+		case g.Period == 3:
+			return "OT " + g.Clock
+		case g.Period > 3:
+			return fmt.Sprintf("%dOT %s", g.Period-2, g.Clock)
+		}
+		return "In Progress"
+	case !g.Start.IsZero():
+		return g.Start.In(loc).Format("3:04 PM")
+	default:
+		return "Scheduled"
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/cache"
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+func main() {
+	if len(os.Args) > 1 {
+		switch name := os.Args[1]; name {
+		case "help", "-h", "-help", "--help":
+			runHelp(os.Args[2:])
+			return
+		case "__complete":
+			runComplete(os.Args[2:])
+			return
+		default:
+			if cmd := findCommand(name); cmd != nil {
+				cmd.run(os.Args[2:])
+				return
+			}
+			if !strings.HasPrefix(name, "-") {
+				fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", name)
+				printUsage(os.Stderr)
+				os.Exit(2)
+			}
+		}
+	}
+
+	// Flags without a command rank teams, as before there were subcommands
+	findCommand("rank").run(os.Args[1:])
+}
+
+// rateSeason loads a season's games within a date range and processes the completed ones through
+// a new Bayesian ELO engine. With strict set, any unfetchable date is an error.
+// A checkpointer, if given, resumes from and periodically saves partial progress.
+// Filters, if any, run in order on the completed games before processing.
+func rateSeason(ctx context.Context, store cache.GameStore, dataSource string, season int, window sources.DateRange, cacheOpts *cacheFlags, clientConfig sources.Config, strict bool, cp *checkpointer, filters ...gameFilter) (*model.BayesianELO, error) {
+	completedGames, err := completedSeasonGames(ctx, store, dataSource, season, window, cacheOpts, clientConfig, strict, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Process games through Bayesian ELO, skipping any a checkpoint already covers
+	elo := cp.resume()
+	if len(completedGames) == 0 {
+		return elo, nil
+	}
+	processed := elo.ProcessedGames()
+	var newGames []model.Game
+	for _, g := range completedGames {
+		if !processed[g.Key()] {
+			newGames = append(newGames, g)
+		}
+	}
+
+	start := time.Now()
+	if err := processGames(ctx, elo, newGames, cp.afterDay(elo)); err != nil {
+		// Keep whatever was finished so the next run can pick up from here
+		if ctx.Err() != nil {
+			if saveErr := cp.save(elo); saveErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", saveErr)
+			} else if cp != nil {
+				fmt.Fprintf(os.Stderr, "Checkpoint saved to %s; rerun to resume\n", cp.path)
+			}
+		}
+		return nil, fmt.Errorf("processing games: %w", err)
+	}
+	cp.finish()
+	recordRun(elo, len(newGames), start)
+	return elo, nil
+}
+
+// completedSeasonGames loads a season's completed games within a date range
+// and runs them through the filters. With strict set, any unfetchable date is
+// an error.
+func completedSeasonGames(ctx context.Context, store cache.GameStore, dataSource string, season int, window sources.DateRange, cacheOpts *cacheFlags, clientConfig sources.Config, strict bool, filters ...gameFilter) ([]model.Game, error) {
+	games, failedDates, err := loadGames(ctx, store, dataSource, season, window, cacheOpts, clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("fetching games: %w", err)
+	}
+	if strict && len(failedDates) > 0 {
+		return nil, fmt.Errorf("%d dates could not be fetched (-strict): %s", len(failedDates), formatDates(failedDates))
+	}
+
+	// Filter to completed games only
+	var completedGames []model.Game
+	for _, g := range games {
+		if g.Completed {
+			completedGames = append(completedGames, g)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetched %d total games, %d completed\n", len(games), len(completedGames))
+	completedGames = dedupeGames(completedGames)
+	return ratableGames(filterGames(completedGames, filters)), nil
+}
+
+// dedupeGames drops repeated listings of a game, keeping the first: the same
+// ID again, or the same two teams on the same date under another ID, as API
+// hiccups and rescheduled entries produce. Teams don't play twice in a day,
+// so a repeat would only credit the result twice.
+func dedupeGames(games []model.Game) []model.Game {
+	seen := make(map[string]bool)
+	var kept []model.Game
+	for _, g := range games {
+		matchup := matchupKey(g.Date.Format("2006-01-02"), g.HomeTeamID, g.AwayTeamID)
+		if seen[g.Key()] || seen[matchup] {
+			continue
+		}
+		seen[g.Key()], seen[matchup] = true, true
+		kept = append(kept, g)
+	}
+	if n := len(games) - len(kept); n > 0 {
+		fmt.Fprintf(os.Stderr, "Dropped %d duplicate games\n", n)
+	}
+	return kept
+}
+
+// ratableGames drops completed games the rating can't use, naming each:
+// ties, games without a score, and games whose winner is missing or
+// contradicts the score. An override can supply the result.
+func ratableGames(games []model.Game) []model.Game {
+	var kept []model.Game
+	for _, g := range games {
+		if _, problem := model.GameOutcome(g); problem != "" {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s %s at %s (%s): %s\n",
+				g.Date.Format("2006-01-02"), g.AwayTeam, g.HomeTeam, g.ID, problem)
+			continue
+		}
+		kept = append(kept, g)
+	}
+	return kept
+}
+
+// commandContext returns a context cancelled by Ctrl+C, SIGTERM, or the
+// optional timeout, so long fetches and processing runs abort cleanly
+func commandContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// loadGames returns a season's games within a date range, preferring stored data as allowed by
+// the cache flags, along with any dates that could not be fetched. The store
+// may be nil, in which case everything is fetched.
+func loadGames(ctx context.Context, store cache.GameStore, dataSource string, season int, window sources.DateRange, cacheOpts *cacheFlags, clientConfig sources.Config) ([]model.Game, []time.Time, error) {
+	// Clear stored data if requested
+	if *cacheOpts.clear && store != nil {
+		if err := store.Clear(season, dataSource); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not clear cache: %v\n", err)
+		} else {
+			fmt.Fprintln(os.Stderr, "Cache cleared")
+		}
+	}
+
+	dates := window.SeasonDates(season)
+	if len(dates) == 0 {
+		fmt.Fprintf(os.Stderr, "Season %d hasn't started yet\n", season)
+		return nil, nil, nil
+	}
+
+	return loadDates(ctx, store, dataSource, season, dates, cacheOpts, clientConfig)
+}
+
+// loadDates returns the games on the given dates of a season, using stored
+// dates where possible and fetching (and storing) the rest
+func loadDates(ctx context.Context, store cache.GameStore, dataSource string, season int, dates []time.Time, cacheOpts *cacheFlags, clientConfig sources.Config) ([]model.Game, []time.Time, error) {
+	source, err := newGameSource(dataSource, clientConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Use settled dates from the cache, fetching only the rest (typically the
+	// days since the last run plus today)
+	gamesByDate := make(map[time.Time][]model.Game)
+	var missing []time.Time
+	for _, date := range dates {
+		if cacheOpts.readable() && store != nil {
+			if cachedGames, ok := store.Get(season, dataSource, date); ok {
+				// Games are dated on read from their start time, so the
+				// cache serves any -timezone. Older caches hold ESPN games
+				// whose start time didn't parse; they belong to the day they
+				// were fetched for.
+				for i := range cachedGames {
+					switch {
+					case !cachedGames[i].Start.IsZero():
+						cachedGames[i].Date = sources.GameDay(cachedGames[i].Start, clientConfig.Location)
+					case cachedGames[i].Date.IsZero():
+						cachedGames[i].Date = date
+					}
+				}
+				gamesByDate[date] = cachedGames
+				continue
+			}
+		}
+		missing = append(missing, date)
+	}
+
+	if len(gamesByDate) > 0 {
+		fmt.Fprintf(os.Stderr, "Using cached data for %d of %d dates\n", len(gamesByDate), len(dates))
+	}
+
+	var failed []time.Time
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "Fetching games from %s to %s...\n", missing[0].Format("2006-01-02"), missing[len(missing)-1].Format("2006-01-02"))
+
+		var fetched map[time.Time][]model.Game
+		fetched, failed, err = fetchDates(ctx, missing, source.Workers(), source.GetDate)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for date, games := range fetched {
+			gamesByDate[date] = games
+
+			// Failed dates are never cached, so they're retried next run
+			if cacheOpts.writable() && store != nil {
+				if err := store.Put(season, dataSource, date, games); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not cache games: %v\n", err)
+				}
+			}
+		}
+	}
+
+	// Apply the file cache retention policy, keeping the season being loaded
+	fileCache, isCache := store.(*cache.Cache)
+	if policy := cacheOpts.policy(); isCache && (policy.KeepSeasons > 0 || policy.MaxBytes > 0) {
+		if _, err := fileCache.Prune(policy, dataSource, season); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not prune cache: %v\n", err)
+		}
+	}
+
+	return sources.CombineDates(dates, gamesByDate), failed, nil
+}
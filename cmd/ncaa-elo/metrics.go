@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus metrics, exposed by long-running commands given -metrics-addr
+var (
+	apiRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ncaa_elo_api_request_duration_seconds",
+		Help:    "Latency of scoreboard API requests.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"host", "code"})
+
+	fetchErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ncaa_elo_fetch_errors_total",
+		Help: "Scoreboard API requests that failed or returned an error status.",
+	}, []string{"host"})
+
+	gamesProcessed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ncaa_elo_games_processed_total",
+		Help: "Games applied to the ratings.",
+	})
+
+	teamsTracked = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ncaa_elo_teams_tracked",
+		Help: "Teams with a rating distribution.",
+	})
+
+	lastUpdate = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ncaa_elo_last_update_timestamp_seconds",
+		Help: "Unix time the ratings were last updated.",
+	})
+
+	processingDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ncaa_elo_processing_duration_seconds",
+		Help:    "Time spent processing games per run.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 4, 10),
+	})
+)
+
+// recordRun updates the processing metrics after a run, started at start,
+// that applied games to the ratings
+func recordRun(elo *model.BayesianELO, games int, start time.Time) {
+	gamesProcessed.Add(float64(games))
+	teamsTracked.Set(float64(len(elo.Teams)))
+	lastUpdate.SetToCurrentTime()
+	processingDuration.Observe(time.Since(start).Seconds())
+}
+
+// metricsTransport records the latency and failures of API requests
+type metricsTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	apiRequestDuration.WithLabelValues(req.URL.Host, code).Observe(time.Since(start).Seconds())
+	if err != nil || resp.StatusCode >= 400 {
+		fetchErrors.WithLabelValues(req.URL.Host).Inc()
+	}
+
+	return resp, err
+}
+
+// registerMetricsFlag adds the -metrics-addr flag to a flag set
+func registerMetricsFlag(fs *flag.FlagSet) *string {
+	return fs.String("metrics-addr", "", "Serve Prometheus metrics at /metrics on this address (e.g. :9090)")
+}
+
+// startMetricsServer serves /metrics in the background if addr is set
+func startMetricsServer(addr string) {
+	if addr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Warning: metrics server stopped: %v\n", err)
+		}
+	}()
+	fmt.Printf("Serving metrics on %s/metrics\n", addr)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// Mover is a team's ranking at both ends of a movers report. A rank of 0
+// means the team hadn't played yet.
+type Mover struct {
+	TeamID     string  `json:"team_id"`
+	TeamName   string  `json:"team_name"`
+	FromRank   int     `json:"from_rank"`
+	ToRank     int     `json:"to_rank"`
+	RankChange int     `json:"rank_change"` // Spots gained
+	FromELO    float64 `json:"from_elo"`
+	ToELO      float64 `json:"to_elo"`
+	Change     float64 `json:"change"`
+}
+
+// MoversReport compares the rankings as of two dates
+type MoversReport struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Top     int     `json:"top"`
+	Risers  []Mover `json:"risers"`
+	Fallers []Mover `json:"fallers"`
+	Entered []Mover `json:"entered"` // New to the top N
+	Dropped []Mover `json:"dropped"` // Fell out of the top N
+}
+
+// moversCommand implements the movers command: who rose, fell, entered, and
+// left the top N between two dates
+func moversCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	from := fs.String("from", "", "Earlier date, YYYY-MM-DD (default: a week before -to)")
+	to := fs.String("to", "", "Later date, YYYY-MM-DD (default: the latest game)")
+	topN := fs.Int("top", 25, "Report teams entering and leaving the top N")
+	count := fs.Int("count", 10, "Number of risers and fallers to list")
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	return func() {
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		if *to == "" {
+			*to = elo.LastGameDate()
+		}
+		toDate, err := time.Parse("2006-01-02", *to)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid -to date %q (expected YYYY-MM-DD)\n", *to)
+			os.Exit(1)
+		}
+		if *from == "" {
+			*from = toDate.AddDate(0, 0, -7).Format("2006-01-02")
+		}
+		if _, err := time.Parse("2006-01-02", *from); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid -from date %q (expected YYYY-MM-DD)\n", *from)
+			os.Exit(1)
+		}
+		if *from >= *to {
+			fmt.Fprintln(os.Stderr, "Error: -from must be before -to")
+			os.Exit(1)
+		}
+
+		report := moversReport(elo, *from, *to, *topN, *count)
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Print(formatMovers(report))
+		}
+	}
+}
+
+// rankingsAsOf ranks the teams that had played by the end of date by their
+// rating then, returning each team's rank and rating
+func rankingsAsOf(elo *model.BayesianELO, date string) (map[string]int, map[string]float64) {
+	ratings := make(map[string]float64)
+	var ids []string
+	for id, points := range elo.History {
+		if len(points) == 0 || points[0].Date > date {
+			continue
+		}
+		ratings[id] = elo.RatingAsOf(id, date)
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ratings[ids[i]] != ratings[ids[j]] {
+			return ratings[ids[i]] > ratings[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	ranks := make(map[string]int, len(ids))
+	for i, id := range ids {
+		ranks[id] = i + 1
+	}
+	return ranks, ratings
+}
+
+// moversReport compares the rankings at the end of from and to
+func moversReport(elo *model.BayesianELO, from, to string, topN, count int) MoversReport {
+	fromRanks, fromRatings := rankingsAsOf(elo, from)
+	toRanks, toRatings := rankingsAsOf(elo, to)
+	report := MoversReport{
+		From:    from,
+		To:      to,
+		Top:     topN,
+		Risers:  []Mover{},
+		Fallers: []Mover{},
+		Entered: []Mover{},
+		Dropped: []Mover{},
+	}
+
+	var moved []Mover
+	for id, toRank := range toRanks {
+		m := Mover{
+			TeamID:   id,
+			TeamName: elo.Teams[id].TeamName,
+			FromRank: fromRanks[id],
+			ToRank:   toRank,
+			FromELO:  elo.PriorMean(),
+			ToELO:    toRatings[id],
+		}
+		if m.FromRank > 0 {
+			m.FromELO = fromRatings[id]
+			m.RankChange = m.FromRank - m.ToRank
+		}
+		m.Change = m.ToELO - m.FromELO
+
+		inBefore := m.FromRank > 0 && m.FromRank <= topN
+		inAfter := m.ToRank <= topN
+		switch {
+		case inAfter && !inBefore:
+			report.Entered = append(report.Entered, m)
+		case inBefore && !inAfter:
+			report.Dropped = append(report.Dropped, m)
+		}
+		if m.FromRank > 0 {
+			moved = append(moved, m)
+		}
+	}
+	sort.Slice(report.Entered, func(i, j int) bool { return report.Entered[i].ToRank < report.Entered[j].ToRank })
+	sort.Slice(report.Dropped, func(i, j int) bool { return report.Dropped[i].FromRank < report.Dropped[j].FromRank })
+
+	// Most spots gained first, breaking ties by the rating change
+	sort.Slice(moved, func(i, j int) bool {
+		if moved[i].RankChange != moved[j].RankChange {
+			return moved[i].RankChange > moved[j].RankChange
+		}
+		if moved[i].Change != moved[j].Change {
+			return moved[i].Change > moved[j].Change
+		}
+		return moved[i].ToRank < moved[j].ToRank
+	})
+	for _, m := range moved[:min(count, len(moved))] {
+		if m.RankChange > 0 {
+			report.Risers = append(report.Risers, m)
+		}
+	}
+	for i := len(moved) - 1; i >= max(0, len(moved)-count); i-- {
+		if moved[i].RankChange < 0 {
+			report.Fallers = append(report.Fallers, moved[i])
+		}
+	}
+	return report
+}
+
+// formatMovers renders a movers report as text tables
+func formatMovers(report MoversReport) string {
+	var sb strings.Builder
+	width := 72
+
+	sb.WriteString(fmt.Sprintf("Rankings Movers: %s to %s\n", report.From, report.To))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+
+	section := func(title string, movers []Mover) {
+		sb.WriteString("\n" + title + "\n")
+		if len(movers) == 0 {
+			sb.WriteString("  None\n")
+			return
+		}
+		sb.WriteString(fmt.Sprintf("  %-30s %6s %6s %8s %8s %8s\n", "Team", "Was", "Now", "Spots", "ELO", "Change"))
+		sb.WriteString("  " + strings.Repeat("-", width-2) + "\n")
+		for _, m := range movers {
+			was, spots := "-", "-"
+			if m.FromRank > 0 {
+				was, spots = fmt.Sprint(m.FromRank), fmt.Sprintf("%+d", m.RankChange)
+			}
+			sb.WriteString(fmt.Sprintf("  %-30s %6s %6d %8s %8.1f %s\n",
+				output.Truncate(m.TeamName, 30), was, m.ToRank, spots, m.ToELO, output.PadLeft(output.FormatTrend(m.Change), 8)))
+		}
+	}
+	section("Risers", report.Risers)
+	section("Fallers", report.Fallers)
+	section(fmt.Sprintf("Entered the Top %d", report.Top), report.Entered)
+	section(fmt.Sprintf("Dropped from the Top %d", report.Top), report.Dropped)
+	return sb.String()
+}
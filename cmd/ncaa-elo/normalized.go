@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// NormalizedRecord is a team's actual record next to the record it would be
+// expected to post against a standard schedule
+type NormalizedRecord struct {
+	Rank        int     `json:"rank"`
+	TeamID      string  `json:"team_id"`
+	TeamName    string  `json:"team_name"`
+	MeanELO     float64 `json:"mean_elo"`
+	Wins        int     `json:"wins"`
+	Losses      int     `json:"losses"`
+	OpponentELO float64 `json:"avg_opponent_elo"` // Opponents' current ratings, net of home court
+	StdWins     float64 `json:"standard_wins"`
+	StdLosses   float64 `json:"standard_losses"`
+	RecordGap   float64 `json:"record_gap"` // Actual wins scaled to the standard schedule's length, less StdWins
+}
+
+// StandardSchedule is the slate every team is measured against: the games
+// the top teams have played, at their sites, against opponents at their
+// current ratings
+type StandardSchedule struct {
+	TopN        int     `json:"top_n"`
+	Games       int     `json:"games"`            // The median top team's games played
+	OpponentELO float64 `json:"avg_opponent_elo"` // Net of home court
+	pool        []float64
+}
+
+// NormalizedReport is every team's record against the standard schedule
+type NormalizedReport struct {
+	Schedule StandardSchedule   `json:"schedule"`
+	Teams    []NormalizedRecord `json:"teams"`
+}
+
+// normalizedCommand implements the normalized command: each team's expected
+// record against the same standard schedule, so records compare across very
+// different schedules
+func normalizedCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	slateTop := fs.Int("slate-top", 50, "Build the standard schedule from the games of this many top teams")
+	topN := fs.Int("top", 25, "Number of teams to display")
+	showAll := fs.Bool("all", false, "Show all teams, not just top N")
+	sortBy := fs.String("sort", "rank", "Order teams by 'rank' or by 'gap', the actual record's lead over the standard one")
+	outputFormat := fs.String("format", "table", "Output format: 'table', 'json', or 'csv'")
+	return func() {
+		if *sortBy != "rank" && *sortBy != "gap" {
+			fmt.Fprintf(os.Stderr, "Error: invalid -sort %q (expected rank or gap)\n", *sortBy)
+			os.Exit(1)
+		}
+		if *slateTop < 1 {
+			fmt.Fprintln(os.Stderr, "Error: -slate-top must be at least 1")
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		schedule := standardSchedule(elo, *slateTop)
+		if schedule.Games == 0 {
+			fmt.Fprintln(os.Stderr, "Error: no games have been rated")
+			os.Exit(1)
+		}
+		report := NormalizedReport{Schedule: schedule, Teams: normalizedRecords(elo, schedule)}
+		if *sortBy == "gap" {
+			sort.SliceStable(report.Teams, func(i, j int) bool { return report.Teams[i].RecordGap > report.Teams[j].RecordGap })
+		}
+		if !*showAll {
+			report.Teams = report.Teams[:min(*topN, len(report.Teams))]
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		case output.FormatCSV:
+			fmt.Print(formatNormalizedCSV(report.Teams))
+		default:
+			fmt.Print(formatNormalizedTable(report, *engine.season))
+		}
+	}
+}
+
+// opponentSlate lists each team's opponents' current ratings, less the home
+// court edge the team had (plus the edge it faced on the road)
+func opponentSlate(elo *model.BayesianELO) map[string][]float64 {
+	slate := make(map[string][]float64)
+	for _, g := range elo.GameLog {
+		winner, okW := elo.Teams[g.WinnerID]
+		loser, okL := elo.Teams[g.LoserID]
+		if !okW || !okL {
+			continue
+		}
+		edge := 0.0 // The winner's home court edge
+		switch g.HomeAdvantage {
+		case "H":
+			edge = elo.HomeAdvantage
+		case "A":
+			edge = -elo.HomeAdvantage
+		}
+		slate[g.WinnerID] = append(slate[g.WinnerID], loser.Dist.Mean()-edge)
+		slate[g.LoserID] = append(slate[g.LoserID], winner.Dist.Mean()+edge)
+	}
+	return slate
+}
+
+// standardSchedule pools the games of the top n teams into one slate, as
+// long as the median top team's season
+func standardSchedule(elo *model.BayesianELO, n int) StandardSchedule {
+	slate := opponentSlate(elo)
+	rankings := elo.GetRankings()
+	s := StandardSchedule{TopN: min(n, len(rankings))}
+	var counts []int
+	for _, team := range rankings[:s.TopN] {
+		s.pool = append(s.pool, slate[team.TeamID]...)
+		counts = append(counts, len(slate[team.TeamID]))
+	}
+	if len(s.pool) == 0 {
+		return s
+	}
+	sort.Ints(counts)
+	s.Games = counts[len(counts)/2]
+	for _, opp := range s.pool {
+		s.OpponentELO += opp / float64(len(s.pool))
+	}
+	return s
+}
+
+// normalizedRecords works out every rated team's expected record against the
+// standard schedule, averaging over its rating distribution, in rank order
+func normalizedRecords(elo *model.BayesianELO, s StandardSchedule) []NormalizedRecord {
+	// The chance of winning a game drawn from the slate at each grid rating,
+	// shared by every team
+	grid := elo.NewPrior().Values
+	slateWin := make([]float64, len(grid))
+	for i, v := range grid {
+		for _, opp := range s.pool {
+			slateWin[i] += elo.WinProbability(v-opp) / float64(len(s.pool))
+		}
+	}
+
+	slate := opponentSlate(elo)
+	records := model.Records(elo.GameLog)
+	var out []NormalizedRecord
+	for i, team := range elo.GetRankings() {
+		winPct := 0.0
+		for j, p := range team.Dist.Probs {
+			winPct += p * slateWin[j]
+		}
+		rec := records[team.TeamID]
+		r := NormalizedRecord{
+			Rank:      i + 1,
+			TeamID:    team.TeamID,
+			TeamName:  team.TeamName,
+			MeanELO:   team.Dist.Mean(),
+			Wins:      rec.Wins,
+			Losses:    rec.Losses,
+			StdWins:   winPct * float64(s.Games),
+			StdLosses: (1 - winPct) * float64(s.Games),
+		}
+		if games := rec.Wins + rec.Losses; games > 0 {
+			for _, opp := range slate[team.TeamID] {
+				r.OpponentELO += opp / float64(games)
+			}
+			r.RecordGap = float64(rec.Wins)/float64(games)*float64(s.Games) - r.StdWins
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// formatNormalizedTable renders records against the standard schedule as a
+// text table
+func formatNormalizedTable(report NormalizedReport, season int) string {
+	var sb strings.Builder
+	width := 88
+	s := report.Schedule
+
+	sb.WriteString(fmt.Sprintf("Records Against a Standard Schedule (%d-%d Season)\n", season-1, season))
+	sb.WriteString(fmt.Sprintf("%d games like the top %d teams' (average opponent %.1f)\n", s.Games, s.TopN, s.OpponentELO))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-4s %-30s %8s %8s %9s %12s %8s\n", "Rank", "Team", "Mean", "Record", "Opp", "Standard", "Gap"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, t := range report.Teams {
+		sb.WriteString(fmt.Sprintf("%-4d %-30s %8.1f %8s %9.1f %12s %+8.1f\n",
+			t.Rank, output.Truncate(t.TeamName, 30), t.MeanELO,
+			fmt.Sprintf("%d-%d", t.Wins, t.Losses), t.OpponentELO,
+			fmt.Sprintf("%.1f-%.1f", t.StdWins, t.StdLosses), t.RecordGap))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString("\nOpp is the average opponent's current rating, net of home court. Standard is the expected\n")
+	sb.WriteString("record against the standard schedule; Gap is the actual record's lead over it, per as many games.\n")
+	return sb.String()
+}
+
+// formatNormalizedCSV renders records against the standard schedule as CSV
+func formatNormalizedCSV(teams []NormalizedRecord) string {
+	var sb strings.Builder
+	sb.WriteString("rank,team_id,team_name,mean_elo,wins,losses,avg_opponent_elo,standard_wins,standard_losses,record_gap\n")
+	for _, t := range teams {
+		sb.WriteString(fmt.Sprintf("%d,%s,\"%s\",%.1f,%d,%d,%.1f,%.2f,%.2f,%.2f\n",
+			t.Rank, t.TeamID, t.TeamName, t.MeanELO, t.Wins, t.Losses, t.OpponentELO, t.StdWins, t.StdLosses, t.RecordGap))
+	}
+	return sb.String()
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// notifyTargets are the notify subcommands: post the latest day's rankings
+// and biggest movers to Slack, or email a report on the last several days
+var notifyTargets = []command{
+	{name: "slack", flags: notifySlackCommand},
+	{name: "email", flags: notifyEmailCommand},
+}
+
+// notifyFlags holds the flags both notify targets take
+type notifyFlags struct {
+	engine *engineFlags
+	top    *int
+	dryRun *bool
+}
+
+// registerNotifyFlags adds the flags both notify targets take to a flag set
+func registerNotifyFlags(fs *flag.FlagSet) notifyFlags {
+	return notifyFlags{
+		engine: registerEngineFlags(fs),
+		top:    fs.Int("top", 25, "Number of top teams to post"),
+		dryRun: fs.Bool("dry-run", false, "Print the message payload instead of posting it"),
+	}
+}
+
+// notifySlackCommand implements notify slack
+func notifySlackCommand(fs *flag.FlagSet) func() {
+	n := registerNotifyFlags(fs)
+	webhookURL := fs.String("webhook-url", os.Getenv("SLACK_WEBHOOK_URL"), "Slack incoming webhook URL (env: SLACK_WEBHOOK_URL)")
+	return func() {
+		if *webhookURL == "" && !*n.dryRun {
+			fmt.Fprintln(os.Stderr, "Error: set SLACK_WEBHOOK_URL or -webhook-url")
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*n.engine.timeout)
+		defer cancel()
+
+		day := latestFeedDay(n.engine.mustLoad(ctx), *n.top)
+		body, err := json.Marshal(slackMessage(day, *n.engine.season))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding message: %v\n", err)
+			os.Exit(1)
+		}
+		if *n.dryRun {
+			fmt.Println(string(body))
+			return
+		}
+
+		client := &http.Client{Timeout: 10 * time.Second}
+		if err := postJSON(ctx, client, *webhookURL, body); err != nil {
+			fmt.Fprintf(os.Stderr, "Error posting to Slack: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Posted top %d after %s to Slack\n", len(day.Top), day.Date)
+	}
+}
+
+// notifyEmailCommand implements notify email
+func notifyEmailCommand(fs *flag.FlagSet) func() {
+	n := registerNotifyFlags(fs)
+	smtpHost := fs.String("smtp-host", os.Getenv("SMTP_HOST"), "SMTP server host (env: SMTP_HOST)")
+	smtpPort := fs.Int("smtp-port", envInt("SMTP_PORT", 587), "SMTP server port (env: SMTP_PORT)")
+	smtpUser := fs.String("smtp-user", os.Getenv("SMTP_USERNAME"), "SMTP username; empty sends without authenticating (env: SMTP_USERNAME)")
+	from := fs.String("from", os.Getenv("SMTP_FROM"), "Sender address (env: SMTP_FROM)")
+	to := fs.String("to", os.Getenv("NCAA_ELO_EMAIL_TO"), "Comma-separated recipient addresses (env: NCAA_ELO_EMAIL_TO)")
+	subject := fs.String("subject", "", "Subject line (default: the report title and dates)")
+	days := fs.Int("days", 7, "Report rating changes and upsets over this many days")
+	return func() {
+		recipients := splitAddresses(*to)
+		if !*n.dryRun && (*smtpHost == "" || *from == "" || len(recipients) == 0) {
+			fmt.Fprintln(os.Stderr, "Error: set -smtp-host, -from, and -to (or SMTP_HOST, SMTP_FROM, and NCAA_ELO_EMAIL_TO)")
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*n.engine.timeout)
+		defer cancel()
+
+		report := buildPeriodReport(n.engine.mustLoad(ctx), *n.engine.season, *n.top, *days)
+		msg, err := report.email(*from, recipients, *subject)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error building message: %v\n", err)
+			os.Exit(1)
+		}
+		if *n.dryRun {
+			os.Stdout.Write(msg)
+			return
+		}
+
+		cfg := smtpConfig{Host: *smtpHost, Port: *smtpPort, Username: *smtpUser, Password: os.Getenv("SMTP_PASSWORD"), From: *from}
+		if err := sendEmail(cfg, recipients, msg); err != nil {
+			fmt.Fprintf(os.Stderr, "Error sending email: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Emailed the report to %d recipients\n", len(recipients))
+	}
+}
+
+// envInt reads an integer environment variable, returning def if it is unset
+// or invalid
+func envInt(name string, def int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil {
+		return n
+	}
+	return def
+}
+
+// latestFeedDay returns the rankings and movers after the last game day,
+// exiting if the ratings have no history to summarize
+func latestFeedDay(elo *model.BayesianELO, topN int) feedDay {
+	days := feedDays(elo, 1, topN)
+	if len(days) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no rating history to report (re-rate the season or resave the state)")
+		os.Exit(1)
+	}
+	return days[0]
+}
+
+// slackMessage formats a day's rankings as a Slack Block Kit message, with a
+// plain text fallback for notifications
+func slackMessage(day feedDay, season int) map[string]any {
+	var top strings.Builder
+	for i, t := range day.Top {
+		top.WriteString(fmt.Sprintf("%d. *%s* %.1f (%s)\n", i+1, slackEscape(t.TeamName), t.Rating, output.FormatTrend(t.Change)))
+	}
+
+	blocks := []map[string]any{
+		{"type": "header", "text": map[string]any{"type": "plain_text", "text": feedTitle(season)}},
+		{"type": "context", "elements": []map[string]any{{"type": "mrkdwn", "text": fmt.Sprintf("Top %d after games on %s", len(day.Top), day.Date)}}},
+		{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": top.String()}},
+	}
+
+	if len(day.Movers) > 0 {
+		var movers strings.Builder
+		movers.WriteString("*Biggest movers*\n")
+		for _, m := range day.Movers {
+			movers.WriteString(fmt.Sprintf("• %s: %s to %.1f\n", slackEscape(m.TeamName), output.FormatTrend(m.Change), m.Rating))
+		}
+		blocks = append(blocks, map[string]any{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": movers.String()}})
+	}
+
+	return map[string]any{
+		"text":   fmt.Sprintf("%s: top %d after %s", feedTitle(season), len(day.Top), day.Date),
+		"blocks": blocks,
+	}
+}
+
+// slackEscape escapes the characters Slack treats as markup
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"golang.org/x/oauth2/google"
+)
+
+// objectURL parses an object storage output path (s3://bucket/key or
+// gs://bucket/key), reporting false for a local file path
+func objectURL(output string) (*url.URL, bool, error) {
+	if !strings.HasPrefix(output, "s3://") && !strings.HasPrefix(output, "gs://") {
+		return nil, false, nil
+	}
+	u, err := url.Parse(output)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid output URL %q: %w", output, err)
+	}
+	if u.Host == "" || strings.Trim(u.Path, "/") == "" || strings.HasSuffix(u.Path, "/") {
+		return nil, true, fmt.Errorf("invalid output URL %q (expected %s://bucket/path/file)", output, u.Scheme)
+	}
+	return u, true, nil
+}
+
+// checkOutputPath rejects malformed object storage URLs before any work is done
+func checkOutputPath(output string) error {
+	_, _, err := objectURL(output)
+	return err
+}
+
+// writeOutput creates an output by calling write with the file path to write
+// to. For object storage URLs, write fills a temporary file with the same name,
+// which is then uploaded using credentials from the environment.
+func writeOutput(ctx context.Context, output string, write func(path string) error) error {
+	u, remote, err := objectURL(output)
+	if err != nil {
+		return err
+	}
+	if !remote {
+		return write(output)
+	}
+
+	dir, err := os.MkdirTemp("", "ncaa-bayes-elo-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// Keep the object's name so writers that go by extension pick the same format
+	local := filepath.Join(dir, path.Base(u.Path))
+	if err := write(local); err != nil {
+		return err
+	}
+	return uploadObject(ctx, u, local)
+}
+
+// uploadObject uploads a local file to object storage
+func uploadObject(ctx context.Context, u *url.URL, local string) error {
+	f, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	key := strings.TrimPrefix(u.Path, "/")
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	switch u.Scheme {
+	case "s3":
+		err = uploadS3(ctx, u.Host, key, contentType, f)
+	case "gs":
+		err = uploadGCS(ctx, u.Host, key, contentType, f)
+	}
+	if err != nil {
+		return fmt.Errorf("upload to %s: %w", u, err)
+	}
+	return nil
+}
+
+// uploadS3 puts an object using the standard AWS credential chain (environment,
+// shared config and credentials files, or instance role). AWS_REGION must name
+// the bucket's region; AWS_ENDPOINT_URL_S3 selects an S3-compatible service.
+func uploadS3(ctx context.Context, bucket, key, contentType string, body io.ReadSeeker) error {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if cfg.Region == "" {
+		return fmt.Errorf("no AWS region configured (set AWS_REGION)")
+	}
+
+	_, err = s3.NewFromConfig(cfg).PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+// uploadGCS uploads an object through the Cloud Storage JSON API using
+// Application Default Credentials (GOOGLE_APPLICATION_CREDENTIALS, gcloud
+// login, or the metadata server)
+func uploadGCS(ctx context.Context, bucket, key, contentType string, body io.Reader) error {
+	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/devstorage.read_write")
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("https://storage.googleapis.com/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		url.PathEscape(bucket), url.QueryEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// GameOverride corrects or drops one game from the source, picked by its ID
+// or by date and teams. Only the fields given are changed.
+type GameOverride struct {
+	ID      string         `yaml:"id" toml:"id"` // The game's ID, as in the game log
+	Date    string         `yaml:"date" toml:"date"`
+	Teams   []string       `yaml:"teams" toml:"teams"`     // The two teams, by ID or name, in either order
+	Exclude bool           `yaml:"exclude" toml:"exclude"` // Leave the game out entirely
+	Neutral *bool          `yaml:"neutral" toml:"neutral"` // Whether the game was at a neutral site
+	Home    string         `yaml:"home" toml:"home"`       // The team that hosted, when the source has them swapped
+	Scores  map[string]int `yaml:"scores" toml:"scores"`   // Each team's final score, by ID or name
+}
+
+func (o GameOverride) String() string {
+	if o.ID != "" {
+		return "game " + o.ID
+	}
+	return fmt.Sprintf("%s %s", o.Date, strings.Join(o.Teams, " vs "))
+}
+
+// loadOverrides reads a YAML or TOML (by .toml extension) overrides file: a
+// "games" list of corrections
+func loadOverrides(path string) ([]GameOverride, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Games []GameOverride `yaml:"games" toml:"games"`
+	}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(data, &file)
+	} else {
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, o := range file.Games {
+		if o.ID == "" {
+			if _, err := time.Parse("2006-01-02", o.Date); err != nil {
+				return nil, fmt.Errorf("game %d: give an id, or a date (YYYY-MM-DD) and teams", i+1)
+			}
+			if len(o.Teams) != 2 {
+				return nil, fmt.Errorf("game %d (%s): expected two teams", i+1, o.Date)
+			}
+		}
+		if o.Scores != nil && len(o.Scores) != 2 {
+			return nil, fmt.Errorf("%s: scores need both teams", o)
+		}
+		if !o.Exclude && o.Neutral == nil && o.Home == "" && o.Scores == nil {
+			return nil, fmt.Errorf("%s: nothing to override (set exclude, neutral, home, or scores)", o)
+		}
+	}
+	return file.Games, nil
+}
+
+// gameSide resolves a team named in an override to the side of g it played:
+// true for home, false for away
+func gameSide(g model.Game, name string) (bool, error) {
+	teams := map[string]*model.TeamRating{
+		g.HomeTeamID: {TeamID: g.HomeTeamID, TeamName: g.HomeTeam},
+		g.AwayTeamID: {TeamID: g.AwayTeamID, TeamName: g.AwayTeam},
+	}
+	matches := matchTeams(teams, name)
+	if len(matches) != 1 {
+		return false, fmt.Errorf("%q isn't one of %s and %s", name, g.AwayTeam, g.HomeTeam)
+	}
+	return matches[0].TeamID == g.HomeTeamID, nil
+}
+
+// matches reports whether an override is for g: by ID, or by its teams
+// playing g on its date
+func (o GameOverride) matches(g model.Game) bool {
+	if o.ID != "" {
+		return g.Key() == o.ID
+	}
+	if g.Date.Format("2006-01-02") != o.Date {
+		return false
+	}
+	first, err := gameSide(g, o.Teams[0])
+	if err != nil {
+		return false
+	}
+	second, err := gameSide(g, o.Teams[1])
+	return err == nil && first != second
+}
+
+// apply corrects g with the override
+func (o GameOverride) apply(g model.Game) (model.Game, error) {
+	if o.Neutral != nil {
+		g.NeutralSite = *o.Neutral
+	}
+	if o.Home != "" {
+		home, err := gameSide(g, o.Home)
+		if err != nil {
+			return g, err
+		}
+		if !home {
+			g.HomeTeamID, g.AwayTeamID = g.AwayTeamID, g.HomeTeamID
+			g.HomeTeam, g.AwayTeam = g.AwayTeam, g.HomeTeam
+			g.HomeScore, g.AwayScore = g.AwayScore, g.HomeScore
+		}
+	}
+	if o.Scores != nil {
+		for name, score := range o.Scores {
+			home, err := gameSide(g, name)
+			if err != nil {
+				return g, err
+			}
+			if home {
+				g.HomeScore = score
+			} else {
+				g.AwayScore = score
+			}
+		}
+		switch {
+		case g.HomeScore > g.AwayScore:
+			g.WinnerID = g.HomeTeamID
+		case g.AwayScore > g.HomeScore:
+			g.WinnerID = g.AwayTeamID
+		default:
+			return g, errors.New("scores are tied")
+		}
+	}
+	return g, nil
+}
+
+// overridesFilter applies the overrides to the games they match, dropping
+// excluded games and warning about overrides that match no game or can't
+// be applied
+func overridesFilter(overrides []GameOverride) gameFilter {
+	return func(games []model.Game) []model.Game {
+		used := make([]bool, len(overrides))
+		var kept []model.Game
+		applied, excluded := 0, 0
+	next:
+		for _, g := range games {
+			for j, o := range overrides {
+				if !o.matches(g) {
+					continue
+				}
+				used[j] = true
+				if o.Exclude {
+					excluded++
+					continue next
+				}
+				corrected, err := o.apply(g)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: override %s: %v\n", o, err)
+					continue
+				}
+				g = corrected
+				applied++
+			}
+			kept = append(kept, g)
+		}
+
+		for j, o := range overrides {
+			if !used[j] {
+				fmt.Fprintf(os.Stderr, "Warning: %s matches no completed game\n", o)
+			}
+		}
+		if applied > 0 {
+			fmt.Fprintf(os.Stderr, "Applied %d game overrides\n", applied)
+		}
+		if excluded > 0 {
+			fmt.Fprintf(os.Stderr, "Excluded %d games\n", excluded)
+		}
+		return kept
+	}
+}
+
+// skipForfeits drops forfeited games, which say nothing about the teams'
+// strength
+func skipForfeits(games []model.Game) []model.Game {
+	var kept []model.Game
+	for _, g := range games {
+		if g.Status != model.StatusForfeit {
+			kept = append(kept, g)
+		}
+	}
+	if n := len(games) - len(kept); n > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d forfeits\n", n)
+	}
+	return kept
+}
+
+// exclusionList collects repeated -exclude flags, each a game ID or a
+// comma-separated list of them
+type exclusionList []string
+
+func (e *exclusionList) String() string {
+	return strings.Join(*e, ",")
+}
+
+func (e *exclusionList) Set(value string) error {
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			*e = append(*e, id)
+		}
+	}
+	return nil
+}
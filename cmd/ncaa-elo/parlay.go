@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// Parlay is a set of picks that all have to win, priced from model
+// probabilities as if the games were independent
+type Parlay struct {
+	Legs         []MatchupPrediction `json:"legs"` // Each leg's pick is its first team
+	Probability  float64             `json:"probability"`
+	DecimalOdds  float64             `json:"fair_decimal_odds"`
+	AmericanOdds string              `json:"fair_american_odds"`
+}
+
+// parlayCommand implements the parlay command: the chance that every pick wins
+// and the odds that would make the parlay a fair bet
+func parlayCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	legsFile := fs.String("file", "", "Read picks from this file too, one per line (# starts a comment)")
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ncaa-bayes-elo parlay [flags] <pick>...")
+		fmt.Fprintln(fs.Output(), "  Each pick names the team to win first: \"Duke vs UNC\" (neutral), \"Kansas at Baylor\" (on the road),")
+		fmt.Fprintln(fs.Output(), "  or either followed by home, away, or neutral, e.g. parlay \"Duke vs UNC home\" \"Gonzaga at Saint Mary's\"")
+		fs.PrintDefaults()
+	}
+	return func() {
+		lines := fs.Args()
+		if *legsFile != "" {
+			fileLines, err := readPickLines(*legsFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			lines = append(lines, fileLines...)
+		}
+		if len(lines) == 0 {
+			fs.Usage()
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		parlay, err := priceParlay(elo, lines)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(parlay, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Print(formatParlay(parlay, elo.HomeAdvantage))
+		}
+	}
+}
+
+// readPickLines reads the non-blank, non-comment lines of a file
+func readPickLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// priceParlay predicts each pick and multiplies their chances
+func priceParlay(elo *model.BayesianELO, lines []string) (Parlay, error) {
+	parlay := Parlay{Probability: 1}
+	picked := make(map[string]bool)
+	for _, line := range lines {
+		leg, err := predictMatchupLine(elo, line)
+		if err != nil {
+			return parlay, fmt.Errorf("%q: %w", line, err)
+		}
+		key := matchupKey("", leg.TeamID, leg.OpponentID)
+		if picked[key] {
+			return parlay, fmt.Errorf("%q: %s and %s are already in the parlay", line, leg.TeamName, leg.OpponentName)
+		}
+		picked[key] = true
+		parlay.Legs = append(parlay.Legs, leg)
+		parlay.Probability *= leg.WinProb
+	}
+	parlay.DecimalOdds = 1 / parlay.Probability
+	parlay.AmericanOdds = americanOdds(parlay.Probability)
+	return parlay, nil
+}
+
+// americanOdds formats the fair moneyline for a win probability: the stake
+// to win 100 on a favorite ("-150"), or the win on a 100 stake otherwise ("+240")
+func americanOdds(p float64) string {
+	switch {
+	case p <= 0:
+		return "-"
+	case p >= 1:
+		return "-∞"
+	case p > 0.5:
+		return fmt.Sprintf("-%.0f", math.Round(100*p/(1-p)))
+	default:
+		return fmt.Sprintf("+%.0f", math.Round(100*(1-p)/p))
+	}
+}
+
+// formatParlay renders a parlay's legs and price as text, noting the home
+// team's rating edge
+func formatParlay(p Parlay, homeEdge float64) string {
+	var sb strings.Builder
+	width := 80
+
+	sb.WriteString("Parlay\n")
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-28s %-7s %-28s %6s %8s\n", "Pick", "Venue", "Opponent", "Win %", "Fair"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, leg := range p.Legs {
+		sb.WriteString(fmt.Sprintf("%-28s %-7s %-28s %5.1f%% %8s\n",
+			output.Truncate(leg.TeamName, 28), leg.Venue, output.Truncate(leg.OpponentName, 28), leg.WinProb*100, americanOdds(leg.WinProb)))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("All %d legs hit: %.2f%%\n", len(p.Legs), p.Probability*100))
+	sb.WriteString(fmt.Sprintf("Fair odds: %.2f decimal, %s American\n", p.DecimalOdds, p.AmericanOdds))
+	sb.WriteString(fmt.Sprintf("\nLegs are treated as independent; win probabilities give the home team a %g-point edge.\n", homeEdge))
+	return sb.String()
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// writeParquet writes rows to a Snappy-compressed Parquet file, with one
+// column per field named by its parquet tag
+func writeParquet[T any](path string, rows []T) error {
+	if err := parquet.WriteFile(path, rows, parquet.Compression(&parquet.Snappy)); err != nil {
+		return fmt.Errorf("failed to write parquet file: %w", err)
+	}
+	return nil
+}
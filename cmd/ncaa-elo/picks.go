@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// Pick is the model's pick for one game on a day's slate
+type Pick struct {
+	GameID     string  `json:"game_id"`
+	Date       string  `json:"date"`
+	HomeTeamID string  `json:"home_team_id"`
+	HomeTeam   string  `json:"home_team"`
+	AwayTeamID string  `json:"away_team_id"`
+	AwayTeam   string  `json:"away_team"`
+	Neutral    bool    `json:"neutral_site"`
+	PickID     string  `json:"pick_id"`
+	Pick       string  `json:"pick"`
+	WinProb    float64 `json:"win_prob"` // The pick's chances, home court counted
+	Tier       string  `json:"confidence"`
+	Status     string  `json:"status"`            // "scheduled", "in progress", or "final"
+	Correct    *bool   `json:"correct,omitempty"` // Whether the pick won, once final
+}
+
+// confidenceTiers name picks by the favorite's win probability, from the
+// first tier whose minimum it reaches
+var confidenceTiers = []struct {
+	min  float64
+	name string
+}{
+	{0.85, "Lock"},
+	{0.70, "Strong"},
+	{0.60, "Lean"},
+	{0, "Toss-up"},
+}
+
+// picksCommand implements the picks command: the model's pick in every game on
+// a date, most confident first
+func picksCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	dateFlag := fs.String("date", "today", "Date of the games to pick: 'today', 'yesterday', 'tomorrow', or YYYY-MM-DD")
+	outputFormat := fs.String("format", "table", "Output format: 'table', 'json', or 'markdown'")
+	return func() {
+		today, err := engine.client.today()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		date, err := parsePickDate(*dateFlag, today)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		games, err := gamesOn(ctx, engine, date)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching games: %v\n", err)
+			os.Exit(1)
+		}
+		picks, unrated := makePicks(elo, games)
+		if unrated > 0 {
+			fmt.Fprintf(os.Stderr, "Skipped %d games with unrated teams\n", unrated)
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			fmt.Println(output.JSON(picks))
+		case output.FormatMarkdown:
+			fmt.Print(formatPicksMarkdown(picks, date))
+		default:
+			fmt.Print(formatPicksTable(picks, date))
+		}
+	}
+}
+
+// parsePickDate reads -date relative to today, the current game day
+func parsePickDate(value string, today time.Time) (time.Time, error) {
+	switch value {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return date, fmt.Errorf("invalid -date %q (expected today, yesterday, tomorrow, or YYYY-MM-DD)", value)
+	}
+	return date, nil
+}
+
+// gamesOn fetches one date's games for the engine's source and season
+func gamesOn(ctx context.Context, engine *engineFlags, date time.Time) ([]model.Game, error) {
+	clientConfig, err := engine.client.config(*engine.dataSource, engine.cache.cacheDir())
+	if err != nil {
+		return nil, err
+	}
+	store, err := openGameStore(ctx, engine.cache)
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := store.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	games, failed, err := loadDates(ctx, store, *engine.dataSource, *engine.season, []time.Time{date}, engine.cache, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("could not fetch games for %s", date.Format("2006-01-02"))
+	}
+	return games, nil
+}
+
+// makePicks picks the favorite in each game, most confident first, and counts
+// the games left out for involving a team without a rating
+func makePicks(elo *model.BayesianELO, games []model.Game) ([]Pick, int) {
+	picks := []Pick{}
+	unrated := 0
+	for _, g := range games {
+		homeELO := elo.HomeAdvantage
+		if g.NeutralSite {
+			homeELO = 0
+		}
+		prob, err := elo.PredictMatchupAt(g.HomeTeamID, g.AwayTeamID, homeELO)
+		if err != nil {
+			unrated++
+			continue
+		}
+
+		p := Pick{
+			GameID:     g.ID,
+			Date:       g.Date.Format("2006-01-02"),
+			HomeTeamID: g.HomeTeamID,
+			HomeTeam:   g.HomeTeam,
+			AwayTeamID: g.AwayTeamID,
+			AwayTeam:   g.AwayTeam,
+			Neutral:    g.NeutralSite,
+			PickID:     g.HomeTeamID,
+			Pick:       g.HomeTeam,
+			WinProb:    prob,
+			Status:     "scheduled",
+		}
+		if prob < 0.5 {
+			p.PickID, p.Pick, p.WinProb = g.AwayTeamID, g.AwayTeam, 1-prob
+		}
+		for _, tier := range confidenceTiers {
+			if p.WinProb >= tier.min {
+				p.Tier = tier.name
+				break
+			}
+		}
+		switch {
+		case g.Completed && g.WinnerID != "":
+			p.Status = "final"
+			correct := g.WinnerID == p.PickID
+			p.Correct = &correct
+		case g.State == "in":
+			p.Status = "in progress"
+		}
+		picks = append(picks, p)
+	}
+	sort.SliceStable(picks, func(i, j int) bool { return picks[i].WinProb > picks[j].WinProb })
+	return picks, unrated
+}
+
+// pickMatchup describes a pick's game as "Away @ Home", or "Away vs Home" at
+// a neutral site
+func pickMatchup(p Pick) string {
+	if p.Neutral {
+		return p.AwayTeam + " vs " + p.HomeTeam
+	}
+	return p.AwayTeam + " @ " + p.HomeTeam
+}
+
+// pickResult shows whether a final pick won
+func pickResult(p Pick) string {
+	switch {
+	case p.Correct == nil:
+		return ""
+	case *p.Correct:
+		return "✓"
+	default:
+		return "✗"
+	}
+}
+
+// formatPicksTable renders a day's picks as a text table
+func formatPicksTable(picks []Pick, date time.Time) string {
+	var sb strings.Builder
+	width := 104
+
+	sb.WriteString(fmt.Sprintf("Picks for %s\n", date.Format("Monday, January 2, 2006")))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	if len(picks) == 0 {
+		sb.WriteString("No games with rated teams\n")
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("%-52s %-26s %7s  %-8s %s\n", "Game", "Pick", "Win %", "Tier", "Result"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, p := range picks {
+		line := fmt.Sprintf("%-52s %-26s %6.1f%%  %-8s %s",
+			output.Truncate(pickMatchup(p), 52), output.Truncate(p.Pick, 26), p.WinProb*100, p.Tier, pickResult(p))
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(pickRecord(picks))
+	return sb.String()
+}
+
+// formatPicksMarkdown renders a day's picks as a Markdown table
+func formatPicksMarkdown(picks []Pick, date time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Picks for %s\n\n", date.Format("Monday, January 2, 2006")))
+	if len(picks) == 0 {
+		sb.WriteString("No games with rated teams.\n")
+		return sb.String()
+	}
+	sb.WriteString("| Game | Pick | Win % | Confidence | Result |\n")
+	sb.WriteString("|------|------|------:|------------|:------:|\n")
+	for _, p := range picks {
+		sb.WriteString(fmt.Sprintf("| %s | **%s** | %.1f%% | %s | %s |\n",
+			pickMatchup(p), p.Pick, p.WinProb*100, p.Tier, pickResult(p)))
+	}
+	if record := pickRecord(picks); record != "" {
+		sb.WriteString("\n" + record)
+	}
+	return sb.String()
+}
+
+// pickRecord summarizes how the final picks did, or is empty before any finish
+func pickRecord(picks []Pick) string {
+	right, final := 0, 0
+	for _, p := range picks {
+		if p.Correct != nil {
+			final++
+			if *p.Correct {
+				right++
+			}
+		}
+	}
+	if final == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Picks so far: %d-%d\n", right, final-right)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Colors for the first and second team in a plot
+var plotColors = []color.RGBA{
+	{R: 0x1f, G: 0x4e, B: 0x78, A: 0xff},
+	{R: 0xc0, G: 0x39, B: 0x2b, A: 0xff},
+}
+
+// plotCommand implements the plot command: render a team's ELO posterior,
+// optionally overlaid with an opponent's for a matchup, to an SVG, PNG, or PDF
+// file
+func plotCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	teamID := fs.String("team", "", "Team ID or name to plot (required)")
+	vsID := fs.String("vs", "", "Opponent team ID or name to overlay for a matchup")
+	outputFile := fs.String("output", "", "Image file or s3:// or gs:// URL; the extension (.svg, .png, .pdf) picks the format (default: <team>.svg)")
+	width := fs.Float64("width", 8, "Image width in inches")
+	height := fs.Float64("height", 4, "Image height in inches")
+	return func() {
+		if *teamID == "" {
+			fmt.Fprintln(os.Stderr, "Usage: ncaa-bayes-elo plot -team <team> [-vs <team>] [-output file.svg]")
+			os.Exit(1)
+		}
+		if *outputFile != "" {
+			if err := checkOutputPath(*outputFile); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if err := checkPlotFormat(*outputFile); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+
+		elo := engine.mustLoad(ctx)
+
+		team, err := findTeam(elo, *teamID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		teams := []*model.TeamRating{team}
+		if *vsID != "" {
+			opponent, err := findTeam(elo, *vsID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			teams = append(teams, opponent)
+		}
+		if *outputFile == "" {
+			*outputFile = team.TeamID + ".svg"
+		}
+
+		title := fmt.Sprintf("%s ELO Posterior", teams[0].TeamName)
+		if len(teams) == 2 {
+			prob, err := elo.PredictMatchup(teams[0].TeamID, teams[1].TeamID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error predicting matchup: %v\n", err)
+				os.Exit(1)
+			}
+			title = fmt.Sprintf("%s vs %s (%.1f%% / %.1f%%)", teams[0].TeamName, teams[1].TeamName, prob*100, (1-prob)*100)
+		}
+
+		err = writeOutput(ctx, *outputFile, func(path string) error {
+			return plotDistributions(path, title, teams, vg.Length(*width)*vg.Inch, vg.Length(*height)*vg.Inch)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Plot written to %s\n", *outputFile)
+	}
+}
+
+// checkPlotFormat rejects image file extensions the plot library can't write
+func checkPlotFormat(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg", ".png", ".pdf", ".eps", ".jpg", ".jpeg", ".tif", ".tiff":
+		return nil
+	}
+	return fmt.Errorf("unsupported image format %q (use .svg, .png, or .pdf)", filepath.Ext(path))
+}
+
+// plotDistributions draws each team's posterior as a filled density curve with
+// its mean marked, trimmed to the range where any team has meaningful mass
+func plotDistributions(path, title string, teams []*model.TeamRating, width, height vg.Length) error {
+	// Find the grid range holding non-negligible probability
+	lo, hi := -1, -1
+	for _, team := range teams {
+		for i, p := range team.Dist.Probs {
+			if p < 1e-4 {
+				continue
+			}
+			if lo < 0 || i < lo {
+				lo = i
+			}
+			if i > hi {
+				hi = i
+			}
+		}
+	}
+	if lo < 0 {
+		return fmt.Errorf("no probability mass to plot")
+	}
+
+	p := plot.New()
+	p.Title.Text = title
+	p.X.Label.Text = "ELO"
+	p.Y.Label.Text = "Probability"
+	p.Add(plotter.NewGrid())
+
+	for i, team := range teams {
+		c := plotColors[i%len(plotColors)]
+
+		points := make(plotter.XYs, 0, hi-lo+1)
+		for j := lo; j <= hi; j++ {
+			points = append(points, plotter.XY{X: team.Dist.Values[j], Y: team.Dist.Probs[j]})
+		}
+		line, err := plotter.NewLine(points)
+		if err != nil {
+			return err
+		}
+		line.Color = c
+		line.Width = vg.Points(1.5)
+		line.FillColor = color.NRGBA{R: c.R, G: c.G, B: c.B, A: 0x40}
+
+		// A vertical line at the mean
+		mean := team.Dist.Mean()
+		var peak float64
+		for _, prob := range team.Dist.Probs {
+			peak = max(peak, prob)
+		}
+		meanLine, err := plotter.NewLine(plotter.XYs{{X: mean, Y: 0}, {X: mean, Y: peak}})
+		if err != nil {
+			return err
+		}
+		meanLine.Color = c
+		meanLine.Dashes = []vg.Length{vg.Points(4), vg.Points(3)}
+
+		p.Add(line, meanLine)
+		p.Legend.Add(fmt.Sprintf("%s (%.0f ± %.0f)", team.TeamName, mean, team.Dist.Std()), line)
+	}
+	p.Legend.Top = true
+
+	if err := p.Save(width, height, path); err != nil {
+		return fmt.Errorf("failed to save plot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/cache"
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+
+	_ "github.com/lib/pq" // Registers the "postgres" database/sql driver
+)
+
+// postgresSchema creates the tables used by PostgresStore. Each game keeps its
+// full record in data alongside extracted columns for dashboards and queries.
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS fetched_dates (
+	source     TEXT        NOT NULL,
+	season     INTEGER     NOT NULL,
+	date       DATE        NOT NULL,
+	fetched_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (source, season, date)
+);
+
+CREATE TABLE IF NOT EXISTS games (
+	source       TEXT        NOT NULL,
+	season       INTEGER     NOT NULL,
+	date         DATE        NOT NULL,
+	seq          INTEGER     NOT NULL,
+	game_id      TEXT        NOT NULL,
+	game_time    TIMESTAMPTZ NOT NULL,
+	home_team_id TEXT        NOT NULL,
+	home_team    TEXT        NOT NULL,
+	away_team_id TEXT        NOT NULL,
+	away_team    TEXT        NOT NULL,
+	home_score   INTEGER     NOT NULL,
+	away_score   INTEGER     NOT NULL,
+	neutral_site BOOLEAN     NOT NULL,
+	completed    BOOLEAN     NOT NULL,
+	winner_id    TEXT        NOT NULL,
+	data         JSONB       NOT NULL,
+	PRIMARY KEY (source, season, date, seq),
+	FOREIGN KEY (source, season, date) REFERENCES fetched_dates ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS team_ratings (
+	source    TEXT             NOT NULL,
+	season    INTEGER          NOT NULL,
+	as_of     DATE             NOT NULL,
+	team_id   TEXT             NOT NULL,
+	team_name TEXT             NOT NULL,
+	rank      INTEGER          NOT NULL,
+	mean_elo  DOUBLE PRECISION NOT NULL,
+	std_dev   DOUBLE PRECISION NOT NULL,
+	pct_5     DOUBLE PRECISION NOT NULL,
+	pct_25    DOUBLE PRECISION NOT NULL,
+	median    DOUBLE PRECISION NOT NULL,
+	pct_75    DOUBLE PRECISION NOT NULL,
+	pct_95    DOUBLE PRECISION NOT NULL,
+	PRIMARY KEY (source, season, as_of, team_id)
+);
+`
+
+// PostgresStore shares game data and rating history through a PostgreSQL
+// database, so several machines can run the rater against the same data
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore connects to the database and creates any missing tables
+func NewPostgresStore(ctx context.Context, dsn string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, postgresSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+// Close closes the database connection
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
+
+// Get retrieves a date's games if they were fetched after the date settled
+func (s *PostgresStore) Get(season int, source string, date time.Time) ([]model.Game, bool) {
+	var fetchedAt time.Time
+	err := s.db.QueryRow(
+		`SELECT fetched_at FROM fetched_dates WHERE source = $1 AND season = $2 AND date = $3`,
+		source, season, date.Format("2006-01-02"),
+	).Scan(&fetchedAt)
+	if err != nil {
+		return nil, false
+	}
+
+	// Dates fetched before they settled (e.g. today) must be refetched
+	if fetchedAt.Before(date.AddDate(0, 0, 1).Add(cache.SettleTime)) {
+		return nil, false
+	}
+
+	rows, err := s.db.Query(
+		`SELECT data FROM games WHERE source = $1 AND season = $2 AND date = $3 ORDER BY seq`,
+		source, season, date.Format("2006-01-02"),
+	)
+	if err != nil {
+		return nil, false
+	}
+	defer rows.Close()
+
+	var games []model.Game
+	for rows.Next() {
+		var data []byte
+		var game model.Game
+		if err := rows.Scan(&data); err != nil {
+			return nil, false
+		}
+		if err := json.Unmarshal(data, &game); err != nil {
+			return nil, false
+		}
+		games = append(games, game)
+	}
+	if rows.Err() != nil {
+		return nil, false
+	}
+	return games, true
+}
+
+// Put replaces a date's games in the database
+func (s *PostgresStore) Put(season int, source string, date time.Time, games []model.Game) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	day := date.Format("2006-01-02")
+
+	// Replacing the date row cascades to its previously stored games
+	if _, err := tx.Exec(`DELETE FROM fetched_dates WHERE source = $1 AND season = $2 AND date = $3`, source, season, day); err != nil {
+		return fmt.Errorf("failed to clear date: %w", err)
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO fetched_dates (source, season, date, fetched_at) VALUES ($1, $2, $3, $4)`,
+		source, season, day, time.Now(),
+	); err != nil {
+		return fmt.Errorf("failed to record date: %w", err)
+	}
+
+	for i, g := range games {
+		data, err := json.Marshal(g)
+		if err != nil {
+			return fmt.Errorf("failed to marshal game: %w", err)
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO games (source, season, date, seq, game_id, game_time, home_team_id, home_team,
+				away_team_id, away_team, home_score, away_score, neutral_site, completed, winner_id, data)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+			source, season, day, i, g.ID, g.Date, g.HomeTeamID, g.HomeTeam,
+			g.AwayTeamID, g.AwayTeam, g.HomeScore, g.AwayScore, g.NeutralSite, g.Completed, g.WinnerID, data,
+		); err != nil {
+			return fmt.Errorf("failed to insert game: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Clear removes a season's stored games; rating history is kept
+func (s *PostgresStore) Clear(season int, source string) error {
+	_, err := s.db.Exec(`DELETE FROM fetched_dates WHERE source = $1 AND season = $2`, source, season)
+	return err
+}
+
+// SaveRatings records every team's rating as of a date, replacing any
+// earlier snapshot for the same day
+func (s *PostgresStore) SaveRatings(season int, source string, asOf time.Time, rankings []*model.TeamRating) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	day := asOf.Format("2006-01-02")
+	if _, err := tx.Exec(`DELETE FROM team_ratings WHERE source = $1 AND season = $2 AND as_of = $3`, source, season, day); err != nil {
+		return fmt.Errorf("failed to clear ratings: %w", err)
+	}
+
+	for i, team := range rankings {
+		if _, err := tx.Exec(
+			`INSERT INTO team_ratings (source, season, as_of, team_id, team_name, rank, mean_elo, std_dev,
+				pct_5, pct_25, median, pct_75, pct_95)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+			source, season, day, team.TeamID, team.TeamName, i+1, team.Dist.Mean(), team.Dist.Std(),
+			team.Dist.Percentile(5), team.Dist.Percentile(25), team.Dist.Percentile(50),
+			team.Dist.Percentile(75), team.Dist.Percentile(95),
+		); err != nil {
+			return fmt.Errorf("failed to insert rating: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// MatchupPrediction is one matchup from a slate, from the first team's side
+type MatchupPrediction struct {
+	Matchup      string  `json:"matchup"` // As written in the file
+	TeamID       string  `json:"team_id"`
+	TeamName     string  `json:"team_name"`
+	OpponentID   string  `json:"opponent_id"`
+	OpponentName string  `json:"opponent_name"`
+	Venue        string  `json:"venue"` // "home", "away", or "neutral" for the first team
+	WinProb      float64 `json:"win_prob"`
+	Favorite     string  `json:"favorite"`
+}
+
+// predictCommand implements the predict command: neutral-site win probabilities
+// for two teams given by ID or name, as "predict A B" or "predict 'A vs B'",
+// or a whole slate of matchups read from a file
+func predictCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	slateFile := fs.String("file", "", "Predict every matchup in this file, one per line: \"A vs B\" (neutral), \"A at B\" (B hosts), or either followed by home, away, or neutral for the first team")
+	outputFormat := fs.String("format", "table", "Output format for -file: 'table', 'json', or 'csv'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ncaa-bayes-elo predict [flags] <team> <team>")
+		fmt.Fprintln(fs.Output(), "       ncaa-bayes-elo predict [flags] -file <matchups>")
+		fs.PrintDefaults()
+	}
+	return func() {
+		if fs.NArg() == 0 && *slateFile == "" {
+			fs.Usage()
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		if *slateFile != "" {
+			predictions, err := predictSlate(elo, *slateFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			switch output.Format(*outputFormat) {
+			case output.FormatJSON:
+				fmt.Println(output.JSON(predictions))
+			case output.FormatCSV:
+				fmt.Print(formatSlateCSV(predictions))
+			default:
+				fmt.Print(formatSlateTable(predictions))
+			}
+			return
+		}
+
+		a, b, err := matchupArgs(elo, fs.Args())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := printPrediction(elo, a.TeamID, b.TeamID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error predicting matchup: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// matchupArgs resolves two teams given as two arguments, or as one or more
+// arguments split like "A vs B"
+func matchupArgs(elo *model.BayesianELO, args []string) (*model.TeamRating, *model.TeamRating, error) {
+	if len(args) == 2 {
+		a, err := findTeam(elo, args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		b, err := findTeam(elo, args[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		return a, b, nil
+	}
+	return splitMatchup(elo, strings.Join(args, " "))
+}
+
+// printPrediction prints both teams' win probabilities for a matchup
+func printPrediction(elo *model.BayesianELO, team1ID, team2ID string) error {
+	prob, err := elo.PredictMatchup(team1ID, team2ID)
+	if err != nil {
+		return err
+	}
+
+	team1 := elo.Teams[team1ID]
+	team2 := elo.Teams[team2ID]
+
+	fmt.Printf("Matchup Prediction:\n")
+	fmt.Printf("  %s vs %s\n", team1.TeamName, team2.TeamName)
+	fmt.Printf("  %s win probability: %.1f%%\n", team1.TeamName, prob*100)
+	fmt.Printf("  %s win probability: %.1f%%\n", team2.TeamName, (1-prob)*100)
+	return nil
+}
+
+// predictSlate predicts every matchup in a file, skipping blank lines and
+// lines starting with #. A line that doesn't name two teams is an error
+// giving its line number.
+func predictSlate(elo *model.BayesianELO, path string) ([]MatchupPrediction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	predictions := []MatchupPrediction{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p, err := predictMatchupLine(elo, line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		predictions = append(predictions, p)
+	}
+	return predictions, scanner.Err()
+}
+
+// predictMatchupLine predicts one matchup: "A vs B" or "A, B" at a neutral
+// site, "A at B" or "A @ B" with B at home, and any of them followed by
+// "home", "away", or "neutral" (after a space or comma) to set the first
+// team's venue
+func predictMatchupLine(elo *model.BayesianELO, line string) (MatchupPrediction, error) {
+	text, venue := line, ""
+	if i := strings.LastIndexAny(text, " ,"); i > 0 {
+		switch word := strings.ToLower(strings.TrimSpace(text[i+1:])); word {
+		case "home", "away", "neutral":
+			text, venue = strings.TrimSpace(strings.TrimRight(text[:i], " ,")), word
+		}
+	}
+
+	var a, b *model.TeamRating
+	var err error
+	lower := strings.ToLower(text)
+	if left, right, ok := cutAny(lower, " at ", " @ "); ok {
+		if a, err = findTeam(elo, left); err != nil {
+			return MatchupPrediction{}, err
+		}
+		if b, err = findTeam(elo, right); err != nil {
+			return MatchupPrediction{}, err
+		}
+		if venue == "" {
+			venue = "away"
+		}
+	} else if a, b, err = splitMatchup(elo, text); err != nil {
+		return MatchupPrediction{}, err
+	}
+	if venue == "" {
+		venue = "neutral"
+	}
+	if a == b {
+		return MatchupPrediction{}, fmt.Errorf("%s can't play itself", a.TeamName)
+	}
+
+	homeELO := 0.0
+	switch venue {
+	case "home":
+		homeELO = elo.HomeAdvantage
+	case "away":
+		homeELO = -elo.HomeAdvantage
+	}
+	prob, err := elo.PredictMatchupAt(a.TeamID, b.TeamID, homeELO)
+	if err != nil {
+		return MatchupPrediction{}, err
+	}
+	p := MatchupPrediction{
+		Matchup:      line,
+		TeamID:       a.TeamID,
+		TeamName:     a.TeamName,
+		OpponentID:   b.TeamID,
+		OpponentName: b.TeamName,
+		Venue:        venue,
+		WinProb:      prob,
+		Favorite:     a.TeamName,
+	}
+	if prob < 0.5 {
+		p.Favorite = b.TeamName
+	}
+	return p, nil
+}
+
+// cutAny cuts s around the first of the separators it contains
+func cutAny(s string, seps ...string) (before, after string, found bool) {
+	for _, sep := range seps {
+		if before, after, found = strings.Cut(s, sep); found {
+			return before, after, true
+		}
+	}
+	return s, "", false
+}
+
+// formatSlateTable renders slate predictions as a text table
+func formatSlateTable(predictions []MatchupPrediction) string {
+	var sb strings.Builder
+	width := 86
+
+	sb.WriteString("Matchup Predictions\n")
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-28s %-7s %-28s %9s %9s\n", "Team", "Venue", "Opponent", "Team Win", "Opp Win"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, p := range predictions {
+		sb.WriteString(fmt.Sprintf("%-28s %-7s %-28s %8.1f%% %8.1f%%\n",
+			output.Truncate(p.TeamName, 28), p.Venue, output.Truncate(p.OpponentName, 28), p.WinProb*100, (1-p.WinProb)*100))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	return sb.String()
+}
+
+// formatSlateCSV renders slate predictions as CSV
+func formatSlateCSV(predictions []MatchupPrediction) string {
+	var sb strings.Builder
+	sb.WriteString("team_id,team_name,opponent_id,opponent_name,venue,win_prob,opponent_win_prob,favorite\n")
+	for _, p := range predictions {
+		sb.WriteString(fmt.Sprintf("%s,\"%s\",%s,\"%s\",%s,%.4f,%.4f,\"%s\"\n",
+			p.TeamID, p.TeamName, p.OpponentID, p.OpponentName, p.Venue, p.WinProb, 1-p.WinProb, p.Favorite))
+	}
+	return sb.String()
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+
+	"golang.org/x/term"
+)
+
+// progressRedraw is the minimum time between progress bar redraws
+const progressRedraw = 100 * time.Millisecond
+
+// progress reports a long-running step on stderr. On a terminal it redraws a
+// bar in place; elsewhere (logs, CI) it prints only the final line. It is not
+// safe for concurrent use.
+type progress struct {
+	w      io.Writer
+	tty    bool
+	label  string
+	unit   string
+	total  int
+	done   int
+	errors int
+	start  time.Time
+	drawn  time.Time
+}
+
+// newProgress starts reporting progress through total units of work
+func newProgress(label, unit string, total int) *progress {
+	return &progress{
+		w:     os.Stderr,
+		tty:   term.IsTerminal(int(os.Stderr.Fd())),
+		label: label,
+		unit:  unit,
+		total: total,
+		start: time.Now(),
+	}
+}
+
+// Add records n more units done, errs of which failed
+func (p *progress) Add(n, errs int) {
+	p.done += n
+	p.errors += errs
+	if p.tty && time.Since(p.drawn) >= progressRedraw {
+		p.drawn = time.Now()
+		fmt.Fprintf(p.w, "\r%s\x1b[K", p.line())
+	}
+}
+
+// Finish prints the final progress line
+func (p *progress) Finish() {
+	if p.tty {
+		fmt.Fprintf(p.w, "\r%s\x1b[K\n", p.line())
+	} else {
+		fmt.Fprintln(p.w, p.line())
+	}
+}
+
+// line renders the bar, count, failures, rate, and either the ETA or, once
+// done, the elapsed time
+func (p *progress) line() string {
+	const width = 30
+	filled := width
+	if p.total > 0 {
+		filled = min(width, p.done*width/p.total)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s [%s%s] %d/%d %s", p.label, strings.Repeat("=", filled), strings.Repeat(" ", width-filled), p.done, p.total, p.unit)
+	if p.errors > 0 {
+		fmt.Fprintf(&sb, ", %d failed", p.errors)
+	}
+
+	elapsed := time.Since(p.start)
+	rate := float64(p.done) / elapsed.Seconds()
+	if p.done > 0 {
+		fmt.Fprintf(&sb, ", %.1f/s", rate)
+	}
+	switch {
+	case p.done >= p.total:
+		fmt.Fprintf(&sb, " in %s", elapsed.Round(100*time.Millisecond))
+	case rate > 0:
+		eta := time.Duration(float64(p.total-p.done) / rate * float64(time.Second))
+		fmt.Fprintf(&sb, ", ETA %s", eta.Round(time.Second))
+	}
+	return sb.String()
+}
+
+// processGames runs games through the engine like ProcessGamesFunc, showing a
+// progress bar on stderr and finishing with the processing throughput
+func processGames(ctx context.Context, elo *model.BayesianELO, games []model.Game, afterDay func(date string) error) error {
+	if len(games) == 0 {
+		return nil
+	}
+
+	perDay := make(map[string]int)
+	for _, g := range games {
+		perDay[g.Date.Format("2006-01-02")]++
+	}
+
+	bar := newProgress("Processing", "games", len(games))
+	start, logged := time.Now(), len(elo.GameLog)
+	err := elo.ProcessGamesFunc(ctx, games, func(date string) error {
+		bar.Add(perDay[date], 0)
+		if afterDay != nil {
+			return afterDay(date)
+		}
+		return nil
+	})
+	bar.Finish()
+	if err != nil {
+		return err
+	}
+
+	elapsed := time.Since(start)
+	fmt.Fprintf(os.Stderr, "Processed %d games over %d days for %d teams in %s (%.0f games/s, %.1f days/s)\n",
+		len(elo.GameLog)-logged, len(perDay), len(elo.Teams), elapsed.Round(time.Millisecond),
+		float64(len(games))/elapsed.Seconds(), float64(len(perDay))/elapsed.Seconds())
+	return nil
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// rankCommand implements the rank command, also run when no command is given:
+// rate the season and print or export the rankings
+func rankCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	dataSource, season := engine.dataSource, engine.season
+	topN := fs.Int("top", 25, "Number of top teams to display")
+	outputFormat := fs.String("format", "table", "Output format: 'table', 'json', 'jsonl', 'csv', 'html', 'atom', 'rss', 'parquet', 'arrow', 'arrow-stream', or 'xlsx'")
+	outputFile := fs.String("output", "", "Output file or s3://bucket/key or gs://bucket/key URL (default: stdout)")
+	showAll := fs.Bool("all", false, "Show all teams, not just top N")
+	trendDays := fs.Int("trend", 7, "Show each team's rating change over this many days in the table (0 = hide)")
+	formGames := fs.Int("form", 0, "Also rate each team from only its last N games, shown next to the season rating (0 = off)")
+	colorMode := fs.String("color", "auto", "Color the table: 'auto' (only on a terminal, unless NO_COLOR is set), 'always', or 'never'")
+	highlight := fs.String("highlight", "", "Highlight a team's row in the colored table, by ID or name")
+	var conferences conferenceList
+	fs.Var(&conferences, "conference", "Only rank teams in this conference; repeat or comma-separate for several (e.g. \"Big Ten,SEC\")")
+	sortBy := fs.String("sort", "mean", "Rank teams by 'mean' rating or 'lcb', the lower credible bound (-lcb-percentile), a conservative order that holds back teams with thin schedules")
+	lcbPercentile := fs.Float64("lcb-percentile", 5, "With -sort lcb, the percentile of each team's distribution to rank by")
+	perConference := fs.Int("top-per-conference", 0, "Show the best N teams of each conference, grouped by conference, instead of the national top N (0 = off)")
+	teamID := fs.String("team", "", "Show the detailed distribution for a team, by ID or name")
+	predict := fs.String("predict", "", "Predict a matchup of teams by ID or name: 'id1,id2' or 'duke vs unc'")
+	saveState := fs.String("save-state", "", "Save the full rating state (distributions and game log) to this file")
+	feedDayCount := fs.Int("feed-days", defaultFeedDays, "Game days included in atom/rss feeds")
+	feedURL := fs.String("feed-url", defaultFeedURL, "Link for atom/rss feed entries")
+	posterior := fs.Bool("posterior", false, "Include each team's full distribution (values and probs) in json/jsonl output")
+	gameLogFile := fs.String("gamelog", "", "Write every processed game with pre/post ratings to this file or object storage URL (.json for JSON, .jsonl for JSON Lines, .parquet for Parquet, .arrow for Arrow IPC, .xlsx for Excel, otherwise CSV)")
+
+	return func() {
+		for _, path := range []string{*outputFile, *gameLogFile} {
+			if err := checkOutputPath(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if output.Format(*outputFormat).Binary() && *outputFile == "" {
+			fmt.Fprintf(os.Stderr, "Error: -format %s requires -output\n", *outputFormat)
+			os.Exit(1)
+		}
+		if *posterior && output.Format(*outputFormat) != output.FormatJSON && output.Format(*outputFormat) != output.FormatJSONL {
+			fmt.Fprintln(os.Stderr, "Error: -posterior requires -format json or jsonl")
+			os.Exit(1)
+		}
+
+		switch {
+		case *sortBy != "mean" && *sortBy != "lcb":
+			fmt.Fprintf(os.Stderr, "Error: invalid -sort %q (expected mean or lcb)\n", *sortBy)
+			os.Exit(1)
+		case *lcbPercentile <= 0 || *lcbPercentile >= 100:
+			fmt.Fprintln(os.Stderr, "Error: -lcb-percentile must be between 0 and 100")
+			os.Exit(1)
+		}
+
+		var stdout *os.File
+		if *outputFile == "" {
+			stdout = os.Stdout
+		}
+		color, err := useColor(*colorMode, stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+
+		fmt.Fprintln(os.Stderr, "NCAA Bayesian ELO Rating System")
+		fmt.Fprintln(os.Stderr, "================================")
+		fmt.Fprintf(os.Stderr, "Season: %d-%d\n", *season-1, *season)
+		fmt.Fprintf(os.Stderr, "Data Source: %s\n\n", *dataSource)
+
+		elo := engine.mustLoad(ctx)
+
+		// A saved state keeps the K factor it was rated with
+		kNote := ""
+		if elo.KFactor == model.OptimalKFactor {
+			kNote = " (optimized via cross-validation)"
+		}
+		fmt.Fprintf(os.Stderr, "K Factor: %.2f%s\n\n", elo.KFactor, kNote)
+
+		if *saveState != "" {
+			if err := elo.SaveState(*saveState, *dataSource, *season); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving state: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "State saved to %s\n\n", *saveState)
+		}
+
+		if *gameLogFile != "" {
+			err := writeOutput(ctx, *gameLogFile, func(path string) error { return writeGameLog(path, elo.GameLog) })
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing game log: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Game log written to %s\n\n", *gameLogFile)
+		}
+
+		// Handle specific team lookup
+		if *teamID != "" {
+			team, err := findTeam(elo, *teamID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			elo.PrintTeamDistribution(team.TeamID)
+			printTeamGames(elo, team.TeamID)
+			return
+		}
+
+		// Handle matchup prediction
+		if *predict != "" {
+			a, b, err := splitMatchup(elo, *predict)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if err := printPrediction(elo, a.TeamID, b.TeamID); err != nil {
+				fmt.Fprintf(os.Stderr, "Error predicting matchup: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		style := output.TableStyle{Color: color}
+		if *highlight != "" {
+			team, err := findTeam(elo, *highlight)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: -highlight: %v\n", err)
+				os.Exit(1)
+			}
+			style.Highlight = team.TeamID
+		}
+
+		// Get rankings, keeping each team's national rank when filtering
+		rankings := elo.GetRankings()
+		if *sortBy == "lcb" {
+			sortByLowerBound(rankings, *lcbPercentile)
+		}
+		ranks := make(map[string]int, len(rankings))
+		for i, team := range rankings {
+			ranks[team.TeamID] = i + 1
+		}
+		if len(conferences) > 0 {
+			if rankings, err = filterConferences(rankings, conferences); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: -conference: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if *perConference > 0 {
+			if rankings, err = topPerConference(rankings, *perConference); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: -top-per-conference: %v\n", err)
+				os.Exit(1)
+			}
+			style.Groups = true
+		}
+
+		// Determine how many to show
+		showCount := *topN
+		if *showAll || *perConference > 0 {
+			showCount = len(rankings)
+		}
+		if showCount > len(rankings) {
+			showCount = len(rankings)
+		}
+
+		// Prepare output
+		teamOutputs := output.Ranked(elo, rankings[:showCount])
+		for i := range teamOutputs {
+			teamOutputs[i].Rank = ranks[teamOutputs[i].TeamID]
+		}
+		if *formGames > 0 {
+			form := formRatings(elo, *formGames)
+			for i := range teamOutputs {
+				teamOutputs[i].FormELO = form[teamOutputs[i].TeamID]
+			}
+		}
+
+		// The rankings workbook adds team detail and game log sheets
+		if output.Format(*outputFormat) == output.FormatXLSX {
+			err := writeOutput(ctx, *outputFile, func(path string) error { return writeRankingsWorkbook(path, elo, teamOutputs) })
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Output written to %s\n", *outputFile)
+			return
+		}
+
+		if output.Format(*outputFormat).Binary() {
+			err := writeOutput(ctx, *outputFile, func(path string) error { return writeBinary(output.Format(*outputFormat), path, teamOutputs) })
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Output written to %s\n", *outputFile)
+			return
+		}
+
+		if output.Format(*outputFormat) == output.FormatJSONL {
+			err := writeOutput(ctx, *outputFile, func(path string) error {
+				if *posterior {
+					return writeJSONLines(path, output.Posteriors(elo, teamOutputs))
+				}
+				return writeJSONLines(path, teamOutputs)
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+				os.Exit(1)
+			}
+			if *outputFile != "" {
+				fmt.Fprintf(os.Stderr, "Output written to %s\n", *outputFile)
+			}
+			return
+		}
+
+		// Output based on format
+		var text string
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			if *posterior {
+				text = output.JSON(output.Posteriors(elo, teamOutputs))
+			} else {
+				text = output.JSON(teamOutputs)
+			}
+		case output.FormatCSV:
+			text = output.CSV(teamOutputs)
+		case output.FormatHTML:
+			var err error
+			if text, err = formatHTML(elo, teamOutputs, *season); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		case output.FormatAtom, output.FormatRSS:
+			days := feedDays(elo, *feedDayCount, showCount)
+			var err error
+			if output.Format(*outputFormat) == output.FormatAtom {
+				text, err = formatAtom(days, *season, *feedURL)
+			} else {
+				text, err = formatRSS(days, *season, *feedURL)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		default:
+			var trends map[string]float64
+			if *trendDays > 0 {
+				trends = output.Trends(elo, *trendDays)
+			}
+			text = output.Table(teamOutputs, *season, trends, *trendDays, style)
+		}
+
+		// Write output
+		if *outputFile != "" {
+			err := writeOutput(ctx, *outputFile, func(path string) error { return os.WriteFile(path, []byte(text), 0644) })
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Output written to %s\n", *outputFile)
+		} else {
+			fmt.Print(text)
+		}
+	}
+}
+
+// sortByLowerBound orders rankings by each team's rating at percentile p of
+// its distribution, highest first, so a team needs both a high rating and
+// the games to back it up to rank well (as TrueSkill ranks by mean minus a
+// multiple of the deviation)
+func sortByLowerBound(rankings []*model.TeamRating, p float64) {
+	bounds := make(map[string]float64, len(rankings))
+	for _, team := range rankings {
+		bounds[team.TeamID] = team.Dist.Percentile(p)
+	}
+	sort.SliceStable(rankings, func(i, j int) bool { return bounds[rankings[i].TeamID] > bounds[rankings[j].TeamID] })
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// ScheduledGame is one of a team's remaining games with its win probability
+type ScheduledGame struct {
+	Date         string   `json:"date"`
+	OpponentID   string   `json:"opponent_id"`
+	OpponentName string   `json:"opponent_name"`
+	Site         string   `json:"site"`     // "home", "away", or "neutral"
+	WinProb      *float64 `json:"win_prob"` // Null for unrated opponents
+	ExpectedWins float64  `json:"expected_wins"`
+}
+
+// TeamSchedule is a team's remaining schedule and projected final record
+type TeamSchedule struct {
+	TeamID        string          `json:"team_id"`
+	TeamName      string          `json:"team_name"`
+	Wins          int             `json:"wins"`
+	Losses        int             `json:"losses"`
+	Games         []ScheduledGame `json:"games"`
+	ProjectedWins float64         `json:"projected_wins"`
+	Unrated       int             `json:"unrated"` // Games against opponents without ratings
+}
+
+// scheduleCommand implements the schedule command: a team's remaining games with
+// win probabilities and running expected wins
+func scheduleCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ncaa-bayes-elo schedule [flags] <team>")
+		fs.PrintDefaults()
+	}
+	return func() {
+		if fs.NArg() == 0 {
+			fs.Usage()
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		team, err := findTeam(elo, strings.Join(fs.Args(), " "))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		games, err := remainingSchedule(ctx, elo, engine)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching schedule: %v\n", err)
+			os.Exit(1)
+		}
+
+		schedule := teamSchedule(elo, team, games)
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(schedule, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Print(formatSchedule(schedule, *engine.season, elo.HomeAdvantage))
+		}
+	}
+}
+
+// teamSchedule picks a team's games out of the remaining schedule and
+// predicts each with home court counted
+func teamSchedule(elo *model.BayesianELO, team *model.TeamRating, games []model.Game) TeamSchedule {
+	rec := model.Records(elo.GameLog)[team.TeamID]
+	schedule := TeamSchedule{
+		TeamID:   team.TeamID,
+		TeamName: team.TeamName,
+		Wins:     rec.Wins,
+		Losses:   rec.Losses,
+		Games:    []ScheduledGame{},
+	}
+
+	var teamGames []model.Game
+	for _, g := range games {
+		if g.HomeTeamID == team.TeamID || g.AwayTeamID == team.TeamID {
+			teamGames = append(teamGames, g)
+		}
+	}
+	sort.SliceStable(teamGames, func(i, j int) bool { return teamGames[i].Date.Before(teamGames[j].Date) })
+
+	expected := float64(rec.Wins)
+	for _, g := range teamGames {
+		sg := ScheduledGame{Date: g.Date.Format("2006-01-02")}
+		homeELO := 0.0
+		switch {
+		case g.NeutralSite:
+			sg.Site = "neutral"
+		case g.HomeTeamID == team.TeamID:
+			sg.Site, homeELO = "home", elo.HomeAdvantage
+		default:
+			sg.Site, homeELO = "away", -elo.HomeAdvantage
+		}
+		sg.OpponentID, sg.OpponentName = g.AwayTeamID, g.AwayTeam
+		if g.AwayTeamID == team.TeamID {
+			sg.OpponentID, sg.OpponentName = g.HomeTeamID, g.HomeTeam
+		}
+
+		if prob, err := elo.PredictMatchupAt(team.TeamID, sg.OpponentID, homeELO); err == nil {
+			sg.WinProb = &prob
+			expected += prob
+		} else {
+			schedule.Unrated++
+		}
+		sg.ExpectedWins = expected
+		schedule.Games = append(schedule.Games, sg)
+	}
+	schedule.ProjectedWins = expected
+	return schedule
+}
+
+// formatSchedule renders a team's remaining schedule as a text table, noting
+// the home team's rating edge
+func formatSchedule(s TeamSchedule, season int, homeEdge float64) string {
+	var sb strings.Builder
+	width := 72
+
+	sb.WriteString(fmt.Sprintf("%s Remaining Schedule (%d-%d Season)\n", s.TeamName, season-1, season))
+	sb.WriteString(fmt.Sprintf("Current record: %d-%d\n", s.Wins, s.Losses))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	if len(s.Games) == 0 {
+		sb.WriteString("No games remain\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("%-10s %-36s %8s %14s\n", "Date", "Opponent", "Win %", "Expected Wins"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	sites := map[string]string{"home": "vs", "away": "@", "neutral": "n"}
+	for _, g := range s.Games {
+		prob := "-"
+		if g.WinProb != nil {
+			prob = fmt.Sprintf("%.1f%%", *g.WinProb*100)
+		}
+		sb.WriteString(fmt.Sprintf("%-10s %-36s %8s %14.1f\n",
+			g.Date, output.Truncate(sites[g.Site]+" "+g.OpponentName, 36), prob, g.ExpectedWins))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+
+	games := float64(s.Wins + s.Losses + len(s.Games) - s.Unrated)
+	sb.WriteString(fmt.Sprintf("Projected record: %.1f-%.1f", s.ProjectedWins, games-s.ProjectedWins))
+	if s.Unrated > 0 {
+		sb.WriteString(fmt.Sprintf(" (leaving out %d against unrated opponents)", s.Unrated))
+	}
+	sb.WriteString(fmt.Sprintf("\nWin probabilities give the home team a %g-point edge.\n", homeEdge))
+	return sb.String()
+}
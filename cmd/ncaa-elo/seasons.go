@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/cache"
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// seasonsFlag is the -season flag: one season ("2025") or a range of them
+// rated in sequence ("2020-2025")
+type seasonsFlag struct {
+	first, last int
+}
+
+func (s *seasonsFlag) String() string {
+	if s.first >= s.last {
+		return strconv.Itoa(s.last)
+	}
+	return fmt.Sprintf("%d-%d", s.first, s.last)
+}
+
+func (s *seasonsFlag) Set(value string) error {
+	first, last, isRange := strings.Cut(strings.TrimSpace(value), "-")
+	from, err := strconv.Atoi(first)
+	if err != nil {
+		return fmt.Errorf("expected a season year like 2025 or a range like 2020-2025")
+	}
+	to := from
+	if isRange {
+		if to, err = strconv.Atoi(last); err != nil {
+			return fmt.Errorf("expected a season year like 2025 or a range like 2020-2025")
+		}
+	}
+	if to < from {
+		return fmt.Errorf("season range %d-%d runs backwards", from, to)
+	}
+	s.first, s.last = from, to
+	return nil
+}
+
+// regressToPrior blends every team's distribution back toward the prior,
+// moving its mean that fraction of the way to the prior mean and widening
+// it to reflect roster turnover between seasons
+func regressToPrior(elo *model.BayesianELO, fraction float64) {
+	prior := elo.NewPrior()
+	for _, team := range elo.Teams {
+		blended := make([]float64, len(team.Dist.Probs))
+		for i, p := range team.Dist.Probs {
+			blended[i] = (1-fraction)*p + fraction*prior.Probs[i]
+		}
+		team.Dist.Probs = blended
+		team.Dist.Normalize()
+	}
+}
+
+// rateSeasons rates a range of seasons in sequence on one engine, regressing
+// every team toward the prior by decay before each season after the first.
+// The game log and history cover only the last season, and teams that didn't
+// play in it are dropped, so records and trends read as that season's.
+func rateSeasons(ctx context.Context, store cache.GameStore, dataSource string, first, last int, decay float64, cacheOpts *cacheFlags, clientConfig sources.Config, strict bool, filters ...gameFilter) (*model.BayesianELO, error) {
+	elo := model.NewBayesianELO()
+	start, total := time.Now(), 0
+	for season := first; season <= last; season++ {
+		fmt.Fprintf(os.Stderr, "Season %d-%d\n", season-1, season)
+		games, err := completedSeasonGames(ctx, store, dataSource, season, sources.DateRange{}, cacheOpts, clientConfig, strict, filters...)
+		if err != nil {
+			return nil, fmt.Errorf("season %d: %w", season, err)
+		}
+		if season > first {
+			regressToPrior(elo, decay)
+			elo.GameLog = []model.GameResult{}
+			elo.History = make(map[string][]model.RatingPoint)
+		}
+		if err := processGames(ctx, elo, games, nil); err != nil {
+			return nil, fmt.Errorf("processing season %d: %w", season, err)
+		}
+		total += len(games)
+	}
+	for id := range elo.Teams {
+		if _, ok := elo.History[id]; !ok {
+			delete(elo.Teams, id)
+		}
+	}
+	recordRun(elo, total, start)
+	return elo, nil
+}
+
+// loadSeason rates one season from a fresh prior, as load does for -season
+func (f *engineFlags) loadSeason(ctx context.Context, season int) (*model.BayesianELO, error) {
+	if *f.loadState != "" {
+		return nil, errors.New("rating several seasons can't be combined with -load-state")
+	}
+	if *f.start != "" || *f.end != "" || *f.asOf != "" {
+		return nil, errors.New("rating several seasons can't be combined with -start, -end, or -as-of")
+	}
+	f.seasons.first, f.seasons.last = season, season
+	return f.load(ctx)
+}
+
+// seasonList is a comma-separated list of seasons and season ranges, e.g.
+// "2015,2019,2023-2025"
+type seasonList []int
+
+func (s *seasonList) String() string {
+	years := make([]string, len(*s))
+	for i, season := range *s {
+		years[i] = strconv.Itoa(season)
+	}
+	return strings.Join(years, ",")
+}
+
+func (s *seasonList) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		var r seasonsFlag
+		if err := r.Set(part); err != nil {
+			return err
+		}
+		for season := r.first; season <= r.last; season++ {
+			*s = append(*s, season)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// SeriesOutcome is one way a series can end, from the first team's side
+type SeriesOutcome struct {
+	Wins   int     `json:"wins"`
+	Losses int     `json:"losses"`
+	Prob   float64 `json:"prob"`
+}
+
+// SeriesOdds is a best-of-n series between two teams
+type SeriesOdds struct {
+	TeamID        string          `json:"team_id"`
+	TeamName      string          `json:"team_name"`
+	OpponentID    string          `json:"opponent_id"`
+	OpponentName  string          `json:"opponent_name"`
+	Games         int             `json:"games"`
+	Sites         string          `json:"sites"`      // The first team's venue in each game: H, A, or N
+	GameProbs     []float64       `json:"game_probs"` // The first team's chances in each game on its own
+	WinProb       float64         `json:"win_prob"`
+	ExpectedGames float64         `json:"expected_games"`
+	Outcomes      []SeriesOutcome `json:"outcomes"`
+}
+
+// seriesCommand implements the series command: the chances of each team winning
+// a best-of-n series, game by game venues counted
+func seriesCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	games := fs.Int("games", 7, "Series length: best of this many games (odd)")
+	pattern := fs.String("sites", "", "The first team's venue in each game, as H, A, and N letters (e.g. HHAAHAH) or home-first blocks (e.g. 2-2-1-1-1, 2-3-2); default all neutral")
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ncaa-bayes-elo series [flags] <team> <team>")
+		fs.PrintDefaults()
+	}
+	return func() {
+		if fs.NArg() == 0 {
+			fs.Usage()
+			os.Exit(1)
+		}
+		sites, err := seriesSites(*pattern, *games)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		a, b, err := matchupArgs(elo, fs.Args())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if a == b {
+			fmt.Fprintf(os.Stderr, "Error: %s can't play itself\n", a.TeamName)
+			os.Exit(1)
+		}
+
+		odds := seriesOdds(elo, a, b, sites)
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(odds, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Print(formatSeries(odds, elo.HomeAdvantage))
+		}
+	}
+}
+
+// seriesSites reads a venue pattern for a best-of-n series: a letter per game
+// (H, A, or N), or blocks of games alternating between the first team's home
+// and the opponent's, starting at home. The pattern may be longer than n
+// (a 2-2-1-1-1 pattern fits a best of 5); empty means all neutral.
+func seriesSites(pattern string, n int) (string, error) {
+	if n < 1 || n%2 == 0 {
+		return "", fmt.Errorf("-games must be odd, got %d", n)
+	}
+	if pattern == "" {
+		return strings.Repeat("N", n), nil
+	}
+
+	sites := strings.ToUpper(pattern)
+	if strings.ContainsAny(pattern, "0123456789") {
+		var sb strings.Builder
+		for i, block := range strings.Split(pattern, "-") {
+			count, err := strconv.Atoi(block)
+			if err != nil || count < 1 {
+				return "", fmt.Errorf("invalid -sites %q (expected letters like HHAAHAH or blocks like 2-2-1-1-1)", pattern)
+			}
+			site := "H"
+			if i%2 == 1 {
+				site = "A"
+			}
+			sb.WriteString(strings.Repeat(site, count))
+		}
+		sites = sb.String()
+	}
+	if strings.Trim(sites, "HAN") != "" {
+		return "", fmt.Errorf("invalid -sites %q (expected letters like HHAAHAH or blocks like 2-2-1-1-1)", pattern)
+	}
+	if len(sites) < n {
+		return "", fmt.Errorf("-sites %q covers %d games, fewer than %d", pattern, len(sites), n)
+	}
+	return sites[:n], nil
+}
+
+// seriesOdds works out a series exactly, averaging over both teams' rating
+// distributions rather than treating the games as independent: an upset in
+// game 1 is evidence the underdog is better than thought, which carries into
+// game 2
+func seriesOdds(elo *model.BayesianELO, a, b *model.TeamRating, sites string) SeriesOdds {
+	n := len(sites)
+	odds := SeriesOdds{
+		TeamID:       a.TeamID,
+		TeamName:     a.TeamName,
+		OpponentID:   b.TeamID,
+		OpponentName: b.TeamName,
+		Games:        n,
+		Sites:        sites,
+	}
+	edges := make([]float64, n)
+	for g, site := range sites {
+		switch site {
+		case 'H':
+			edges[g] = elo.HomeAdvantage
+		case 'A':
+			edges[g] = -elo.HomeAdvantage
+		}
+		prob, _ := elo.PredictMatchupAt(a.TeamID, b.TeamID, edges[g])
+		odds.GameProbs = append(odds.GameProbs, prob)
+	}
+
+	// The grid is evenly spaced, so only the rating difference matters:
+	// diffs[k] is the chance a is (k-size+1) steps above b
+	size := len(a.Dist.Values)
+	diffs := make([]float64, 2*size-1)
+	for i, p := range a.Dist.Probs {
+		if p < 1e-12 {
+			continue
+		}
+		for j, q := range b.Dist.Probs {
+			if q > 1e-12 {
+				diffs[i-j+size-1] += p * q
+			}
+		}
+	}
+
+	// For each difference, play out the series over (wins, losses) states
+	need := n/2 + 1
+	ends := make([][]float64, need+1) // ends[w][l]: the series ending w-l
+	for w := range ends {
+		ends[w] = make([]float64, need+1)
+	}
+	probs := make([]float64, n)
+	for k, mass := range diffs {
+		if mass < 1e-12 {
+			continue
+		}
+		diff := a.Dist.Values[0] - b.Dist.Values[0] + float64(k-size+1)*a.Dist.Step()
+		for g := range probs {
+			probs[g] = elo.WinProbability(diff + edges[g])
+		}
+		state := [][]float64{{mass}} // state[w][l] after w+l games
+		for g := 0; g < n; g++ {
+			next := make([][]float64, need+1)
+			for w := range next {
+				next[w] = make([]float64, need+1)
+			}
+			for w := range state {
+				for l, p := range state[w] {
+					if p == 0 {
+						continue
+					}
+					next[w+1][l] += p * probs[g]
+					next[w][l+1] += p * (1 - probs[g])
+				}
+			}
+			for w := range next {
+				for l := range next[w] {
+					if w == need || l == need {
+						ends[w][l] += next[w][l]
+						next[w][l] = 0
+					}
+				}
+			}
+			state = next
+		}
+	}
+
+	for l := 0; l < need; l++ {
+		odds.Outcomes = append(odds.Outcomes, SeriesOutcome{Wins: need, Losses: l, Prob: ends[need][l]})
+		odds.WinProb += ends[need][l]
+		odds.ExpectedGames += float64(need+l) * ends[need][l]
+	}
+	for w := need - 1; w >= 0; w-- {
+		odds.Outcomes = append(odds.Outcomes, SeriesOutcome{Wins: w, Losses: need, Prob: ends[w][need]})
+		odds.ExpectedGames += float64(w+need) * ends[w][need]
+	}
+	return odds
+}
+
+// formatSeries renders series odds as text, noting the home team's rating
+// edge
+func formatSeries(s SeriesOdds, homeEdge float64) string {
+	var sb strings.Builder
+	width := 60
+	names := map[byte]string{'H': "home", 'A': "away", 'N': "neutral"}
+
+	sb.WriteString(fmt.Sprintf("Best of %d: %s vs %s\n", s.Games, s.TeamName, s.OpponentName))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	for g, prob := range s.GameProbs {
+		sb.WriteString(fmt.Sprintf("Game %d (%s): %s %.1f%%\n", g+1, names[s.Sites[g]], s.TeamName, prob*100))
+	}
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%s wins the series: %.1f%%\n", s.TeamName, s.WinProb*100))
+	sb.WriteString(fmt.Sprintf("%s wins the series: %.1f%%\n", s.OpponentName, (1-s.WinProb)*100))
+	sb.WriteString(fmt.Sprintf("Expected games: %.2f\n\n", s.ExpectedGames))
+	for _, o := range s.Outcomes {
+		sb.WriteString(fmt.Sprintf("  %d-%d  %5.1f%%\n", o.Wins, o.Losses, o.Prob*100))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("Outcomes are %s's wins-losses. Home teams get a %g-point edge.\n", output.Truncate(s.TeamName, 24), homeEdge))
+	return sb.String()
+}
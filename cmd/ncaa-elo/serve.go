@@ -0,0 +1,455 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/cache"
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// scheduleDays is how many days of upcoming games the server keeps, starting today
+const scheduleDays = 7
+
+// ratingServer serves a rated season over HTTP and gRPC. Handlers read a
+// snapshot of the engine under the read lock; refreshes apply new games to
+// the engine itself without it and then swap in a new snapshot.
+type ratingServer struct {
+	mu       sync.RWMutex
+	elo      *model.BayesianELO // Snapshot read by handlers
+	live     *model.BayesianELO // Engine only refreshes touch
+	source   string
+	season   int
+	updated  time.Time
+	applied  int           // Games applied by the latest refresh
+	changed  chan struct{} // Closed and replaced when a refresh applies games
+	schedule []model.Game  // Games not yet final over the next scheduleDays days
+	events   *eventHub     // Game and rating events for WebSocket subscribers
+	webhooks *webhookFlags // Alerts sent after refreshes; nil disables them
+}
+
+// serveCommand implements the serve command: start the REST API backed by a
+// rated season or saved state, refreshing it with newly completed games on a
+// schedule
+func serveCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	refreshInterval := fs.Duration("refresh-interval", time.Hour, "How often to fetch and apply newly completed games (0 = never)")
+	grpcAddr := fs.String("grpc-addr", "", "Also serve the gRPC API on this address (e.g. :9000)")
+	webhooks := registerWebhookFlags(fs)
+	return func() {
+		// Loading honors -timeout; serving runs until interrupted
+		loadCtx, cancelLoad := commandContext(*engine.timeout)
+		elo := engine.mustLoad(loadCtx)
+		cancelLoad()
+
+		ctx, cancel := commandContext(0)
+		defer cancel()
+
+		s := newRatingServer(elo, *engine.dataSource, *engine.season)
+		s.webhooks = webhooks
+
+		if err := s.withSources(ctx, engine, s.refreshSchedule); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not load upcoming games: %v\n", err)
+		}
+
+		server := &http.Server{Addr: *addr, Handler: s.routes(), ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(shutdownCtx)
+		}()
+
+		if *grpcAddr != "" {
+			if err := s.serveGRPC(ctx, *grpcAddr); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if *refreshInterval > 0 {
+			go s.refreshLoop(ctx, engine, *refreshInterval)
+		}
+
+		fmt.Printf("Serving %d teams on %s\n", len(elo.Teams), *addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// newRatingServer wraps a rated season for serving
+func newRatingServer(elo *model.BayesianELO, source string, season int) *ratingServer {
+	teamsTracked.Set(float64(len(elo.Teams)))
+	lastUpdate.SetToCurrentTime()
+
+	return &ratingServer{
+		elo:     elo.Snapshot(),
+		live:    elo,
+		source:  source,
+		season:  season,
+		updated: time.Now(),
+		changed: make(chan struct{}),
+		events:  newEventHub(),
+	}
+}
+
+// routes registers the API endpoints
+func (s *ratingServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rankings", s.handleRankings)
+	mux.HandleFunc("GET /teams/{id}", s.handleTeam)
+	mux.HandleFunc("GET /predict", s.handlePredict)
+	mux.HandleFunc("GET /history/{id}", s.handleHistory)
+	mux.HandleFunc("GET /games", s.handleGames)
+	mux.HandleFunc("GET /schedule", s.handleSchedule)
+	mux.Handle("/graphql", s.graphqlHandler())
+	mux.Handle("GET /ws", s.events)
+	mux.Handle("GET /metrics", promhttp.Handler())
+	return mux
+}
+
+// refreshLoop periodically fetches the days since the last processed game and
+// applies any newly completed games
+func (s *ratingServer) refreshLoop(ctx context.Context, engine *engineFlags, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := s.withSources(ctx, engine, s.refresh); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Warning: refresh failed: %v\n", err)
+		}
+	}
+}
+
+// withSources opens the game store and client configuration a refresh
+// fetches through and passes them to fn
+func (s *ratingServer) withSources(ctx context.Context, engine *engineFlags, fn func(context.Context, cache.GameStore, *engineFlags, sources.Config) error) error {
+	clientConfig, err := engine.client.config(s.source, engine.cache.cacheDir())
+	if err != nil {
+		return err
+	}
+	store, err := openGameStore(ctx, engine.cache)
+	if err != nil {
+		return err
+	}
+	if closer, ok := store.(io.Closer); ok {
+		defer closer.Close()
+	}
+	return fn(ctx, store, engine, clientConfig)
+}
+
+// refresh applies newly completed games to the engine, then publishes a new
+// snapshot. Refreshes run one at a time, so the engine needs no server lock
+// and handlers go on reading the previous snapshot until it's swapped in.
+func (s *ratingServer) refresh(ctx context.Context, store cache.GameStore, engine *engineFlags, clientConfig sources.Config) error {
+	if err := s.refreshSchedule(ctx, store, engine, clientConfig); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not refresh upcoming games: %v\n", err)
+	}
+
+	filters, err := engine.filter.fetched(ctx, s.source, clientConfig)
+	if err != nil {
+		return err
+	}
+	dates := updateDates(s.live, s.season, sources.GameDay(time.Now(), clientConfig.Location))
+	newGames, failed, err := fetchNewGames(ctx, s.live, store, s.source, s.season, dates, engine.cache, clientConfig, filters)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	logged, before, ranks := len(s.live.GameLog), ratingMeans(s.live), s.live.Ranks()
+	if err := s.live.ProcessGames(ctx, newGames); err != nil {
+		return err
+	}
+	recordRun(s.live, len(newGames), start)
+	s.events.publish(ratingEvents(s.live, logged, before)...)
+	if alerts := s.webhooks.alerts(s.live, logged, ranks); len(alerts) > 0 {
+		go s.webhooks.send(ctx, alerts) // Don't hold up the new ratings while delivering
+	}
+	snapshot := s.live.Snapshot()
+
+	s.mu.Lock()
+	s.elo = snapshot
+	s.updated = time.Now()
+	if len(newGames) > 0 {
+		s.applied = len(newGames)
+		close(s.changed)
+		s.changed = make(chan struct{})
+	}
+	updated := s.updated
+	s.mu.Unlock()
+
+	fmt.Printf("%s: applied %d new games", updated.Format("2006-01-02 15:04:05"), len(newGames))
+	if len(failed) > 0 {
+		fmt.Printf(" (%d dates failed, retrying next refresh)", len(failed))
+	}
+	fmt.Println()
+	return nil
+}
+
+// refreshSchedule replaces the upcoming games with those scheduled from today
+// through the next scheduleDays days that haven't gone final
+func (s *ratingServer) refreshSchedule(ctx context.Context, store cache.GameStore, engine *engineFlags, clientConfig sources.Config) error {
+	today := sources.GameDay(time.Now(), clientConfig.Location)
+	dates := sources.DatesBetween(today, today.AddDate(0, 0, scheduleDays-1))
+
+	games, _, err := loadDates(ctx, store, s.source, s.season, dates, engine.cache, clientConfig)
+	if err != nil {
+		return err
+	}
+
+	var schedule []model.Game
+	for _, g := range games {
+		if !g.Completed {
+			schedule = append(schedule, g)
+		}
+	}
+	sort.SliceStable(schedule, func(i, j int) bool { return schedule[i].Date.Before(schedule[j].Date) })
+
+	s.mu.Lock()
+	s.schedule = schedule
+	s.mu.Unlock()
+	return nil
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, map[string]string{"error": fmt.Sprintf(format, args...)})
+}
+
+// handleRankings serves GET /rankings?top=N (default: all teams)
+func (s *ratingServer) handleRankings(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rankings := s.elo.GetRankings()
+	if top := r.URL.Query().Get("top"); top != "" {
+		n, err := strconv.Atoi(top)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid top %q", top)
+			return
+		}
+		rankings = rankings[:min(n, len(rankings))]
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"source":   s.source,
+		"season":   s.season,
+		"updated":  s.updated,
+		"rankings": output.Ranked(s.elo, rankings),
+	})
+}
+
+// handleTeam serves GET /teams/{id}: the team's rating, rank, and full posterior
+func (s *ratingServer) handleTeam(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id := r.PathValue("id")
+	for i, team := range s.elo.GetRankings() {
+		if team.TeamID == id {
+			t := output.NewTeam(i+1, team)
+			t.SetRecords(output.Splits(s.elo)[id])
+			writeJSON(w, http.StatusOK, output.Posterior{
+				Team:   t,
+				Values: team.Dist.Values,
+				Probs:  team.Dist.Probs,
+			})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "team %s not found", id)
+}
+
+// PredictionOutput is a matchup prediction
+type PredictionOutput struct {
+	TeamA     output.Team `json:"team_a"`
+	TeamB     output.Team `json:"team_b"`
+	ProbAWins float64     `json:"prob_a_wins"`
+	ProbBWins float64     `json:"prob_b_wins"`
+}
+
+// siteEdge returns the rating edge for a team at a site: "home", "away", or
+// "neutral" (the default when empty)
+func siteEdge(elo *model.BayesianELO, site string) (float64, error) {
+	switch site {
+	case "", "neutral":
+		return 0, nil
+	case "home":
+		return elo.HomeAdvantage, nil
+	case "away":
+		return -elo.HomeAdvantage, nil
+	}
+	return 0, fmt.Errorf("invalid site %q (expected home, away, or neutral)", site)
+}
+
+// handlePredict serves GET /predict?a=X&b=Y, with site=home or site=away
+// for a's venue (default: neutral)
+func (s *ratingServer) handlePredict(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q := r.URL.Query()
+	a, b := q.Get("a"), q.Get("b")
+	if a == "" || b == "" {
+		writeError(w, http.StatusBadRequest, "a and b team IDs are required")
+		return
+	}
+	edge, err := siteEdge(s.elo, q.Get("site"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	prob, err := s.elo.PredictMatchupAt(a, b, edge)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "%v", err)
+		return
+	}
+
+	ranks, splits := s.ranks(), output.Splits(s.elo)
+	teamA, teamB := output.NewTeam(ranks[a], s.elo.Teams[a]), output.NewTeam(ranks[b], s.elo.Teams[b])
+	teamA.SetRecords(splits[a])
+	teamB.SetRecords(splits[b])
+	writeJSON(w, http.StatusOK, PredictionOutput{
+		TeamA:     teamA,
+		TeamB:     teamB,
+		ProbAWins: prob,
+		ProbBWins: 1 - prob,
+	})
+}
+
+// handleHistory serves GET /history/{id}: the team's rating after each day it played
+func (s *ratingServer) handleHistory(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id := r.PathValue("id")
+	team, ok := s.elo.Teams[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "team %s not found", id)
+		return
+	}
+
+	points := []HistoryOutput{}
+	for _, p := range s.elo.History[id] {
+		points = append(points, HistoryOutput{
+			TeamID:   id,
+			TeamName: team.TeamName,
+			Date:     p.Date,
+			MeanELO:  p.Mean,
+			StdDev:   p.Std,
+		})
+	}
+	writeJSON(w, http.StatusOK, points)
+}
+
+// handleGames serves GET /games with optional team, date, since, and until
+// (YYYY-MM-DD) filters
+func (s *ratingServer) handleGames(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q := r.URL.Query()
+	team, date, since, until := q.Get("team"), q.Get("date"), q.Get("since"), q.Get("until")
+
+	games := []GameLogOutput{}
+	for _, g := range gameLogOutputs(s.elo.GameLog) {
+		if team != "" && g.WinnerID != team && g.LoserID != team {
+			continue
+		}
+		if (date != "" && g.Date != date) || (since != "" && g.Date < since) || (until != "" && g.Date > until) {
+			continue
+		}
+		games = append(games, g)
+	}
+	writeJSON(w, http.StatusOK, games)
+}
+
+// ScheduledGameOutput is an upcoming game with the model's pre-game
+// probabilities, which are null when either team is unrated
+type ScheduledGameOutput struct {
+	GameID      string   `json:"game_id"`
+	Date        string   `json:"date"`
+	HomeID      string   `json:"home_id"`
+	HomeTeam    string   `json:"home_team"`
+	AwayID      string   `json:"away_id"`
+	AwayTeam    string   `json:"away_team"`
+	NeutralSite bool     `json:"neutral_site"`
+	State       string   `json:"state"`
+	HomeWinProb *float64 `json:"home_win_prob"`
+	AwayWinProb *float64 `json:"away_win_prob"`
+}
+
+// scheduledGames lists upcoming games, optionally for one team or date.
+// Callers hold the read lock.
+func (s *ratingServer) scheduledGames(team, date string) []ScheduledGameOutput {
+	games := []ScheduledGameOutput{}
+	for _, g := range s.schedule {
+		if team != "" && g.HomeTeamID != team && g.AwayTeamID != team {
+			continue
+		}
+		if date != "" && g.Date.Format("2006-01-02") != date {
+			continue
+		}
+
+		out := ScheduledGameOutput{
+			GameID:      g.ID,
+			Date:        g.Date.Format("2006-01-02"),
+			HomeID:      g.HomeTeamID,
+			HomeTeam:    g.HomeTeam,
+			AwayID:      g.AwayTeamID,
+			AwayTeam:    g.AwayTeam,
+			NeutralSite: g.NeutralSite,
+			State:       g.State,
+		}
+		edge := s.elo.HomeAdvantage
+		if g.NeutralSite {
+			edge = 0
+		}
+		if prob, err := s.elo.PredictMatchupAt(g.HomeTeamID, g.AwayTeamID, edge); err == nil {
+			away := 1 - prob
+			out.HomeWinProb, out.AwayWinProb = &prob, &away
+		}
+		games = append(games, out)
+	}
+	return games
+}
+
+// handleSchedule serves GET /schedule with optional team and date filters:
+// upcoming games with pre-game win probabilities
+func (s *ratingServer) handleSchedule(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q := r.URL.Query()
+	writeJSON(w, http.StatusOK, s.scheduledGames(q.Get("team"), q.Get("date")))
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+
+	"golang.org/x/oauth2/google"
+)
+
+const sheetsAPI = "https://sheets.googleapis.com/v4/spreadsheets/"
+
+// spreadsheetURLPattern extracts the ID from a spreadsheet's browser URL
+var spreadsheetURLPattern = regexp.MustCompile(`/spreadsheets/d/([a-zA-Z0-9_-]+)`)
+
+// sheetsCommand implements the sheets command: push the rankings, team
+// detail, and game log into tabs of a Google Sheet, replacing their previous
+// contents
+func sheetsCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	spreadsheet := fs.String("spreadsheet", os.Getenv("NCAA_ELO_SPREADSHEET"), "Spreadsheet ID or URL (env: NCAA_ELO_SPREADSHEET)")
+	topN := fs.Int("top", 0, "Number of ranked teams to push (0 = all)")
+	return func() {
+		id := *spreadsheet
+		if m := spreadsheetURLPattern.FindStringSubmatch(id); m != nil {
+			id = m[1]
+		}
+		if id == "" {
+			fmt.Fprintln(os.Stderr, "Usage: ncaa-bayes-elo sheets -spreadsheet <id or URL>")
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+
+		elo := engine.mustLoad(ctx)
+		rankings := elo.GetRankings()
+		if *topN > 0 {
+			rankings = rankings[:min(*topN, len(rankings))]
+		}
+
+		client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/spreadsheets")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Google credentials: %v\n", err)
+			os.Exit(1)
+		}
+
+		sheets := rankingsSheets(elo, output.Ranked(elo, rankings))
+		if err := pushSheets(ctx, client, id, sheets); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating spreadsheet: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Updated %d tabs in https://docs.google.com/spreadsheets/d/%s\n", len(sheets), id)
+	}
+}
+
+// pushSheets replaces the contents of a tab per sheet, creating missing tabs,
+// and freezes and bolds each header row
+func pushSheets(ctx context.Context, client *http.Client, id string, sheets []xlsxSheet) error {
+	var meta struct {
+		Sheets []struct {
+			Properties struct {
+				SheetID int    `json:"sheetId"`
+				Title   string `json:"title"`
+			} `json:"properties"`
+		} `json:"sheets"`
+	}
+	if err := sheetsCall(ctx, client, http.MethodGet, id+"?fields=sheets.properties", nil, &meta); err != nil {
+		return err
+	}
+	sheetIDs := make(map[string]int)
+	for _, s := range meta.Sheets {
+		sheetIDs[s.Properties.Title] = s.Properties.SheetID
+	}
+
+	// Add missing tabs
+	var adds []any
+	for _, sheet := range sheets {
+		if _, ok := sheetIDs[sheet.name]; !ok {
+			adds = append(adds, map[string]any{"addSheet": map[string]any{"properties": map[string]any{"title": sheet.name}}})
+		}
+	}
+	if len(adds) > 0 {
+		var reply struct {
+			Replies []struct {
+				AddSheet struct {
+					Properties struct {
+						SheetID int    `json:"sheetId"`
+						Title   string `json:"title"`
+					} `json:"properties"`
+				} `json:"addSheet"`
+			} `json:"replies"`
+		}
+		if err := sheetsCall(ctx, client, http.MethodPost, id+":batchUpdate", map[string]any{"requests": adds}, &reply); err != nil {
+			return err
+		}
+		for _, r := range reply.Replies {
+			sheetIDs[r.AddSheet.Properties.Title] = r.AddSheet.Properties.SheetID
+		}
+	}
+
+	// Replace the values, clearing first so shorter tables leave no stale rows
+	var ranges []string
+	var data []any
+	var formats []any
+	for _, sheet := range sheets {
+		tab := "'" + strings.ReplaceAll(sheet.name, "'", "''") + "'"
+		ranges = append(ranges, tab)
+
+		values := [][]any{toAny(sheet.columns)}
+		values = append(values, sheet.rows...)
+		data = append(data, map[string]any{"range": tab + "!A1", "values": values})
+
+		sheetID := sheetIDs[sheet.name]
+		formats = append(formats,
+			map[string]any{"updateSheetProperties": map[string]any{
+				"properties": map[string]any{"sheetId": sheetID, "gridProperties": map[string]any{"frozenRowCount": 1}},
+				"fields":     "gridProperties.frozenRowCount",
+			}},
+			map[string]any{"repeatCell": map[string]any{
+				"range":  map[string]any{"sheetId": sheetID, "startRowIndex": 0, "endRowIndex": 1},
+				"cell":   map[string]any{"userEnteredFormat": map[string]any{"textFormat": map[string]any{"bold": true}}},
+				"fields": "userEnteredFormat.textFormat.bold",
+			}})
+	}
+	if err := sheetsCall(ctx, client, http.MethodPost, id+"/values:batchClear", map[string]any{"ranges": ranges}, nil); err != nil {
+		return err
+	}
+	if err := sheetsCall(ctx, client, http.MethodPost, id+"/values:batchUpdate", map[string]any{"valueInputOption": "RAW", "data": data}, nil); err != nil {
+		return err
+	}
+	return sheetsCall(ctx, client, http.MethodPost, id+":batchUpdate", map[string]any{"requests": formats}, nil)
+}
+
+// sheetsCall makes a Sheets API request relative to the spreadsheets endpoint,
+// decoding the JSON response into out if it is non-nil
+func sheetsCall(ctx context.Context, client *http.Client, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, sheetsAPI+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&apiErr)
+		if apiErr.Error.Message != "" {
+			return fmt.Errorf("%s %s: %s", method, path, apiErr.Error.Message)
+		}
+		return fmt.Errorf("%s %s: status %s", method, path, resp.Status)
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// toAny converts a string slice to the []any the values API expects
+func toAny(values []string) []any {
+	row := make([]any, len(values))
+	for i, v := range values {
+		row[i] = v
+	}
+	return row
+}
@@ -0,0 +1,450 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// SimulatedTeam is a team's projected final record from simulating the rest
+// of the season
+type SimulatedTeam struct {
+	Rank       int     `json:"rank"`
+	TeamID     string  `json:"team_id"`
+	TeamName   string  `json:"team_name"`
+	MeanELO    float64 `json:"mean_elo"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	Remaining  int     `json:"remaining"`
+	ProjWins   float64 `json:"projected_wins"`
+	ProjLosses float64 `json:"projected_losses"`
+	WinsP10    int     `json:"wins_p10"` // 10th percentile of final wins
+	WinsP90    int     `json:"wins_p90"` // 90th percentile of final wins
+	ProjRank   float64 `json:"projected_rank"`
+	RankP10    int     `json:"rank_p10"` // Best final rank in 10% of simulations
+	RankP90    int     `json:"rank_p90"` // Worst final rank in 10% of simulations
+	ProbFirst  float64 `json:"prob_first"`
+
+	winCounts  []int // winCounts[w] is how many simulations had the team win w more games
+	rankCounts []int // rankCounts[r] is how many simulations had the team finish ranked r+1
+}
+
+// RecordChance is the chance of finishing the season with a given record
+type RecordChance struct {
+	Wins     int     `json:"wins"`
+	Losses   int     `json:"losses"`
+	Prob     float64 `json:"prob"`
+	OrBetter float64 `json:"prob_or_better"`
+}
+
+// RecordDistribution is a team's simulated final records
+type RecordDistribution struct {
+	TeamID    string         `json:"team_id"`
+	TeamName  string         `json:"team_name"`
+	Wins      int            `json:"wins"`
+	Losses    int            `json:"losses"`
+	Remaining int            `json:"remaining"`
+	Records   []RecordChance `json:"records"` // Best record first
+	Ranks     []RankChance   `json:"ranks"`   // Final ranks reached in at least 0.1% of simulations
+}
+
+// RankChance is the chance of finishing the season at a given rank
+type RankChance struct {
+	Rank     int     `json:"rank"`
+	Prob     float64 `json:"prob"`
+	OrBetter float64 `json:"prob_or_better"`
+}
+
+// simulateCommand implements the simulate command: play out the remaining
+// schedule many times with the current ratings and project final records
+func simulateCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	sims := fs.Int("n", 10000, "Number of simulated seasons")
+	topN := fs.Int("top", 25, "Number of top teams to display")
+	showAll := fs.Bool("all", false, "Show all teams, not just top N")
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	teamName := fs.String("team", "", "Show the full distribution of this team's final record instead of the projections table")
+	seed := registerSeedFlag(fs)
+	return func() {
+		if *sims < 1 {
+			fmt.Fprintln(os.Stderr, "Error: -n must be at least 1")
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+
+		elo := engine.mustLoad(ctx)
+		var team *model.TeamRating
+		if *teamName != "" {
+			var err error
+			if team, err = findTeam(elo, *teamName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		schedule, err := remainingSchedule(ctx, elo, engine)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching schedule: %v\n", err)
+			os.Exit(1)
+		}
+		if len(schedule) == 0 {
+			fmt.Printf("No games remain on the %d-%d schedule\n", *engine.season-1, *engine.season)
+			return
+		}
+
+		rng, usedSeed := newRNG(*seed)
+		results, skipped := simulateSeason(elo, schedule, *sims, rng)
+		if skipped > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: skipped %d games involving teams without ratings\n", skipped)
+		}
+		if team != nil {
+			for _, t := range results {
+				if t.TeamID != team.TeamID {
+					continue
+				}
+				dist := recordDistribution(t, *sims)
+				switch output.Format(*outputFormat) {
+				case output.FormatJSON:
+					data, _ := json.MarshalIndent(dist, "", "  ")
+					fmt.Println(string(data))
+				default:
+					fmt.Print(formatRecordDistribution(dist, *sims, usedSeed))
+				}
+			}
+			return
+		}
+		if !*showAll {
+			results = results[:min(*topN, len(results))]
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			fmt.Print(output.JSON(results))
+		default:
+			fmt.Print(formatSimulationTable(results, *engine.season, len(schedule)-skipped, *sims, usedSeed))
+		}
+	}
+}
+
+// registerSeedFlag adds the -seed flag shared by the Monte Carlo commands
+func registerSeedFlag(fs *flag.FlagSet) *uint64 {
+	return fs.Uint64("seed", 0, "Random seed, to reproduce a run's simulations exactly (0 = a new seed each run)")
+}
+
+// newRNG returns a random source for simulations and the seed it was started
+// from, choosing a seed if none was given
+func newRNG(seed uint64) (*rand.Rand, uint64) {
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return rand.New(rand.NewPCG(seed, 0)), seed
+}
+
+// remainingSchedule fetches the season's games from today on that are not yet
+// in the game log, by ID or by date and teams (as for what-if results)
+func remainingSchedule(ctx context.Context, elo *model.BayesianELO, engine *engineFlags) ([]model.Game, error) {
+	return remainingGames(ctx, elo, *engine.dataSource, *engine.season, engine.cache, engine.client)
+}
+
+// remainingGames is remainingSchedule for a given source and season
+func remainingGames(ctx context.Context, elo *model.BayesianELO, dataSource string, season int, cacheOpts *cacheFlags, client *clientFlags) ([]model.Game, error) {
+	clientConfig, err := client.config(dataSource, cacheOpts.cacheDir())
+	if err != nil {
+		return nil, err
+	}
+	today := sources.GameDay(time.Now(), clientConfig.Location)
+	start := time.Date(season-1, time.November, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(season, time.April, 15, 0, 0, 0, 0, time.UTC)
+	if today.After(start) {
+		start = today
+	}
+	if start.After(end) {
+		return nil, nil
+	}
+	store, err := openGameStore(ctx, cacheOpts)
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := store.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	games, failed, err := loadDates(ctx, store, dataSource, season, sources.DatesBetween(start, end), cacheOpts, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d schedule dates could not be fetched\n", len(failed))
+	}
+
+	processed := elo.ProcessedGames()
+	for _, g := range elo.GameLog {
+		processed[matchupKey(g.Date, g.WinnerID, g.LoserID)] = true
+	}
+	var remaining []model.Game
+	for _, g := range games {
+		if g.Status != "" {
+			continue // Cancelled or postponed, or a forfeit
+		}
+		if !processed[g.Key()] && !processed[matchupKey(g.Date.Format("2006-01-02"), g.HomeTeamID, g.AwayTeamID)] {
+			remaining = append(remaining, g)
+		}
+	}
+	return remaining, nil
+}
+
+// simulateSeason plays the schedule n times, drawing each game's winner from
+// its pre-game win probability, and returns every rated team's projection in
+// rating order along with the number of games skipped for unrated teams
+func simulateSeason(elo *model.BayesianELO, schedule []model.Game, n int, rng *rand.Rand) ([]SimulatedTeam, int) {
+	rankings := elo.GetRankings()
+	index := make(map[string]int, len(rankings))
+	for i, team := range rankings {
+		index[team.TeamID] = i
+	}
+
+	type simGame struct {
+		home, away int
+		edge       float64 // Home team's rating edge
+		prob       float64 // Home win probability
+	}
+	var games []simGame
+	remaining := make([]int, len(rankings))
+	skipped := 0
+	for _, g := range schedule {
+		home, okHome := index[g.HomeTeamID]
+		away, okAway := index[g.AwayTeamID]
+		if !okHome || !okAway {
+			skipped++
+			continue
+		}
+		edge := elo.HomeAdvantage
+		if g.NeutralSite {
+			edge = 0
+		}
+		prob, _ := elo.PredictMatchupAt(g.HomeTeamID, g.AwayTeamID, edge)
+		games = append(games, simGame{home, away, edge, prob})
+		remaining[home]++
+		remaining[away]++
+	}
+
+	// Re-rating every simulated season exactly would take far too long, so
+	// final ratings are approximated by a Gaussian (Glicko-style) update of
+	// each team's mean and variance after every simulated game
+	scale := math.Ln10 * elo.KFactor / 400
+	means := make([]float64, len(rankings))
+	variances := make([]float64, len(rankings))
+	for t, team := range rankings {
+		means[t] = team.Dist.Mean()
+		variances[t] = team.Dist.Std() * team.Dist.Std()
+	}
+	mean, variance := make([]float64, len(rankings)), make([]float64, len(rankings))
+	order := make([]int, len(rankings))
+	rerate := func(t int, won bool, p float64) {
+		result := 0.0
+		if won {
+			result = 1
+		}
+		mean[t] += variance[t] * scale * (result - p)
+		variance[t] = 1 / (1/variance[t] + scale*scale*p*(1-p))
+	}
+
+	// counts[t][w] is how many simulations ended with team t winning w more
+	// games, and rankCounts[t][r] how many ended with it ranked r+1
+	counts := make([][]int, len(rankings))
+	rankCounts := make([][]int, len(rankings))
+	for t := range counts {
+		counts[t] = make([]int, remaining[t]+1)
+		rankCounts[t] = make([]int, len(rankings))
+	}
+	wins := make([]int, len(rankings))
+	for s := 0; s < n; s++ {
+		clear(wins)
+		copy(mean, means)
+		copy(variance, variances)
+		for _, g := range games {
+			homeWon := rng.Float64() < g.prob
+			if homeWon {
+				wins[g.home]++
+			} else {
+				wins[g.away]++
+			}
+			p := elo.WinProbability(mean[g.home] + g.edge - mean[g.away])
+			rerate(g.home, homeWon, p)
+			rerate(g.away, !homeWon, 1-p)
+		}
+		for t, w := range wins {
+			counts[t][w]++
+		}
+		for t := range order {
+			order[t] = t
+		}
+		sort.Slice(order, func(i, j int) bool { return mean[order[i]] > mean[order[j]] })
+		for r, t := range order {
+			rankCounts[t][r]++
+		}
+	}
+
+	records := model.Records(elo.GameLog)
+	results := make([]SimulatedTeam, len(rankings))
+	for t, team := range rankings {
+		rec := records[team.TeamID]
+		expected := 0.0
+		for w, c := range counts[t] {
+			expected += float64(w*c) / float64(n)
+		}
+		projRank := 0.0
+		for r, c := range rankCounts[t] {
+			projRank += float64((r+1)*c) / float64(n)
+		}
+		results[t] = SimulatedTeam{
+			Rank:       t + 1,
+			TeamID:     team.TeamID,
+			TeamName:   team.TeamName,
+			MeanELO:    team.Dist.Mean(),
+			Wins:       rec.Wins,
+			Losses:     rec.Losses,
+			Remaining:  remaining[t],
+			ProjWins:   float64(rec.Wins) + expected,
+			ProjLosses: float64(rec.Losses+remaining[t]) - expected,
+			WinsP10:    rec.Wins + countQuantile(counts[t], n, 0.10),
+			WinsP90:    rec.Wins + countQuantile(counts[t], n, 0.90),
+			ProjRank:   projRank,
+			RankP10:    1 + countQuantile(rankCounts[t], n, 0.10),
+			RankP90:    1 + countQuantile(rankCounts[t], n, 0.90),
+			ProbFirst:  float64(rankCounts[t][0]) / float64(n),
+			winCounts:  counts[t],
+			rankCounts: rankCounts[t],
+		}
+	}
+	return results, skipped
+}
+
+// recordDistribution turns a team's simulations into the chance of each
+// final record, best first
+func recordDistribution(t SimulatedTeam, n int) RecordDistribution {
+	dist := RecordDistribution{
+		TeamID:    t.TeamID,
+		TeamName:  t.TeamName,
+		Wins:      t.Wins,
+		Losses:    t.Losses,
+		Remaining: t.Remaining,
+		Records:   []RecordChance{},
+	}
+	orBetter := 0.0
+	for w := len(t.winCounts) - 1; w >= 0; w-- {
+		prob := float64(t.winCounts[w]) / float64(n)
+		orBetter += prob
+		dist.Records = append(dist.Records, RecordChance{
+			Wins:     t.Wins + w,
+			Losses:   t.Losses + t.Remaining - w,
+			Prob:     prob,
+			OrBetter: orBetter,
+		})
+	}
+	orBetter = 0
+	for r, c := range t.rankCounts {
+		prob := float64(c) / float64(n)
+		orBetter += prob
+		if prob >= 0.001 {
+			dist.Ranks = append(dist.Ranks, RankChance{Rank: r + 1, Prob: prob, OrBetter: orBetter})
+		}
+	}
+	return dist
+}
+
+// formatRecordDistribution renders a team's final record chances as a table
+// with a bar for each record
+func formatRecordDistribution(d RecordDistribution, sims int, seed uint64) string {
+	var sb strings.Builder
+	width := 64
+
+	sb.WriteString(fmt.Sprintf("\n%s Final Record Distribution\n", d.TeamName))
+	sb.WriteString(fmt.Sprintf("Now %d-%d with %d games left, simulated %d times (seed %d)\n", d.Wins, d.Losses, d.Remaining, sims, seed))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-8s %7s %9s\n", "Record", "Chance", "Or Better"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	peak := 0.0
+	for _, r := range d.Records {
+		peak = max(peak, r.Prob)
+	}
+	for _, r := range d.Records {
+		bar := ""
+		if peak > 0 {
+			bar = strings.Repeat("█", int(math.Round(r.Prob/peak*32)))
+		}
+		line := fmt.Sprintf("%-8s %6.1f%% %8.1f%%  %s", fmt.Sprintf("%d-%d", r.Wins, r.Losses), r.Prob*100, r.OrBetter*100, bar)
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+
+	sb.WriteString(fmt.Sprintf("\n%-8s %7s %9s\n", "Rank", "Chance", "Or Better"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	peak = 0
+	for _, r := range d.Ranks {
+		peak = max(peak, r.Prob)
+	}
+	for _, r := range d.Ranks {
+		bar := strings.Repeat("█", int(math.Round(r.Prob/peak*32)))
+		line := fmt.Sprintf("%-8s %6.1f%% %8.1f%%  %s", fmt.Sprintf("#%d", r.Rank), r.Prob*100, r.OrBetter*100, bar)
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString("Final ranks re-rate each simulated season approximately.\n")
+	return sb.String()
+}
+
+// countQuantile returns the smallest value whose cumulative share of n
+// outcomes reaches q, given how many outcomes had each value
+func countQuantile(counts []int, n int, q float64) int {
+	cumulative := 0
+	for v, c := range counts {
+		cumulative += c
+		if float64(cumulative) >= q*float64(n) {
+			return v
+		}
+	}
+	return len(counts) - 1
+}
+
+// formatSimulationTable renders projected records as a text table
+func formatSimulationTable(teams []SimulatedTeam, season, games, sims int, seed uint64) string {
+	var sb strings.Builder
+	width := 115
+
+	sb.WriteString(fmt.Sprintf("\nProjected Final Records (%d-%d Season)\n", season-1, season))
+	sb.WriteString(fmt.Sprintf("%d remaining games simulated %d times (seed %d)\n", games, sims, seed))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-4s %-30s %8s %8s %6s %12s %12s %9s %9s %6s\n", "Rank", "Team", "Mean", "Record", "Left", "Projected", "80% Wins", "Proj Rank", "80% Rank", "#1 %"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+
+	for _, t := range teams {
+		sb.WriteString(fmt.Sprintf("%-4d %-30s %8.1f %8s %6d %12s %12s %9.1f %9s %5.1f%%\n",
+			t.Rank,
+			output.Truncate(t.TeamName, 30),
+			t.MeanELO,
+			fmt.Sprintf("%d-%d", t.Wins, t.Losses),
+			t.Remaining,
+			fmt.Sprintf("%.1f-%.1f", t.ProjWins, t.ProjLosses),
+			fmt.Sprintf("%d-%d", t.WinsP10, t.WinsP90),
+			t.ProjRank,
+			fmt.Sprintf("%d-%d", t.RankP10, t.RankP90),
+			t.ProbFirst*100))
+	}
+
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	return sb.String()
+}
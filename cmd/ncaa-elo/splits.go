@@ -0,0 +1,211 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// SiteSplit is a team's performance at one kind of venue
+type SiteSplit struct {
+	Games       int     `json:"games"`
+	Wins        int     `json:"wins"`
+	Losses      int     `json:"losses"`
+	OpponentELO float64 `json:"avg_opponent_elo"` // Opponents' mean pre-game rating
+	Change      float64 `json:"rating_change"`    // Total rating gained or lost
+}
+
+// TeamSplits is a team's home, road, and neutral-site performance
+type TeamSplits struct {
+	Rank     int       `json:"rank"`
+	TeamID   string    `json:"team_id"`
+	TeamName string    `json:"team_name"`
+	Home     SiteSplit `json:"home"`
+	Road     SiteSplit `json:"road"`
+	Neutral  SiteSplit `json:"neutral"`
+}
+
+// splitsCommand implements the splits command: each team's record, opponent
+// strength, and rating change at home, on the road, and at neutral sites
+func splitsCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	topN := fs.Int("top", 25, "Number of teams to display")
+	showAll := fs.Bool("all", false, "Show all teams, not just top N")
+	sortBy := fs.String("sort", "rank", "Order teams by 'rank' or the rating change earned at 'home', on the 'road', or at 'neutral' sites")
+	outputFormat := fs.String("format", "table", "Output format: 'table', 'json', or 'csv'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ncaa-bayes-elo splits [flags] [team]")
+		fs.PrintDefaults()
+	}
+	return func() {
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		splits, err := siteSplits(elo, *sortBy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if elo.GameLog[0].WinnerPostStd == 0 {
+			fmt.Fprintln(os.Stderr, "Warning: this state has no post-game ratings; rating changes are left out (replay it with -as-of to fill them in)")
+		}
+
+		if fs.NArg() > 0 {
+			team, err := findTeam(elo, strings.Join(fs.Args(), " "))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			for _, s := range splits {
+				if s.TeamID == team.TeamID {
+					splits = []TeamSplits{s}
+					break
+				}
+			}
+		} else if !*showAll {
+			splits = splits[:min(*topN, len(splits))]
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			fmt.Println(output.JSON(splits))
+		case output.FormatCSV:
+			fmt.Print(formatSplitsCSV(splits))
+		default:
+			fmt.Print(formatSplitsTable(splits, *engine.season))
+		}
+	}
+}
+
+// siteSplits tallies every rated team's games by venue, ordered by rank or
+// by the rating change earned at one kind of venue
+func siteSplits(elo *model.BayesianELO, sortBy string) ([]TeamSplits, error) {
+	var site func(s *TeamSplits) *SiteSplit
+	switch sortBy {
+	case "rank":
+	case "home":
+		site = func(s *TeamSplits) *SiteSplit { return &s.Home }
+	case "road":
+		site = func(s *TeamSplits) *SiteSplit { return &s.Road }
+	case "neutral":
+		site = func(s *TeamSplits) *SiteSplit { return &s.Neutral }
+	default:
+		return nil, fmt.Errorf("invalid -sort %q (expected rank, home, road, or neutral)", sortBy)
+	}
+	if len(elo.GameLog) == 0 {
+		return nil, fmt.Errorf("no games have been rated")
+	}
+
+	byTeam := make(map[string]*TeamSplits)
+	var splits []*TeamSplits
+	for i, team := range elo.GetRankings() {
+		s := &TeamSplits{Rank: i + 1, TeamID: team.TeamID, TeamName: team.TeamName}
+		byTeam[team.TeamID] = s
+		splits = append(splits, s)
+	}
+
+	add := func(teamID string, won bool, where string, opponentELO, pre, post, postStd float64) {
+		s, ok := byTeam[teamID]
+		if !ok {
+			return
+		}
+		split := &s.Neutral
+		switch where {
+		case "home":
+			split = &s.Home
+		case "road":
+			split = &s.Road
+		}
+		split.Games++
+		if won {
+			split.Wins++
+		} else {
+			split.Losses++
+		}
+		split.OpponentELO += opponentELO // Summed here, averaged below
+		if postStd > 0 {
+			split.Change += post - pre
+		}
+	}
+	for _, g := range elo.GameLog {
+		winnerSite, loserSite := "neutral", "neutral"
+		switch g.HomeAdvantage {
+		case "H":
+			winnerSite, loserSite = "home", "road"
+		case "A":
+			winnerSite, loserSite = "road", "home"
+		}
+		add(g.WinnerID, true, winnerSite, g.LoserELO, g.WinnerELO, g.WinnerPostELO, g.WinnerPostStd)
+		add(g.LoserID, false, loserSite, g.WinnerELO, g.LoserELO, g.LoserPostELO, g.LoserPostStd)
+	}
+
+	out := make([]TeamSplits, len(splits))
+	for i, s := range splits {
+		for _, split := range []*SiteSplit{&s.Home, &s.Road, &s.Neutral} {
+			if split.Games > 0 {
+				split.OpponentELO /= float64(split.Games)
+			}
+		}
+		out[i] = *s
+	}
+	if site != nil {
+		sort.SliceStable(out, func(i, j int) bool { return site(&out[i]).Change > site(&out[j]).Change })
+	}
+	return out, nil
+}
+
+// formatSplitsTable renders venue splits as a text table
+func formatSplitsTable(splits []TeamSplits, season int) string {
+	var sb strings.Builder
+	width := 110
+
+	sb.WriteString(fmt.Sprintf("NCAA Men's Basketball Home/Road/Neutral Splits (%d-%d Season)\n", season-1, season))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-29s | %-24s | %-24s | %s\n", "", "Home", "Road", "Neutral"))
+	sb.WriteString(fmt.Sprintf("%-4s %-24s", "Rank", "Team"))
+	for range 3 {
+		sb.WriteString(fmt.Sprintf(" | %6s %8s %8s", "W-L", "Opp", "Change"))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+
+	for _, s := range splits {
+		sb.WriteString(fmt.Sprintf("%-4d %-24s", s.Rank, output.Truncate(s.TeamName, 24)))
+		for _, split := range []SiteSplit{s.Home, s.Road, s.Neutral} {
+			if split.Games == 0 {
+				sb.WriteString(fmt.Sprintf(" | %6s %8s %8s", "-", "-", "-"))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf(" | %6s %8.1f %s",
+				fmt.Sprintf("%d-%d", split.Wins, split.Losses), split.OpponentELO, output.PadLeft(output.FormatTrend(split.Change), 8)))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString("\nOpp is the average opponent's pre-game rating; Change is the rating earned at that site.\n")
+	return sb.String()
+}
+
+// formatSplitsCSV renders venue splits as CSV
+func formatSplitsCSV(splits []TeamSplits) string {
+	var sb strings.Builder
+	sb.WriteString("rank,team_id,team_name")
+	for _, site := range []string{"home", "road", "neutral"} {
+		sb.WriteString(fmt.Sprintf(",%[1]s_wins,%[1]s_losses,%[1]s_avg_opponent_elo,%[1]s_rating_change", site))
+	}
+	sb.WriteString("\n")
+	for _, s := range splits {
+		sb.WriteString(fmt.Sprintf("%d,%s,\"%s\"", s.Rank, s.TeamID, s.TeamName))
+		for _, split := range []SiteSplit{s.Home, s.Road, s.Neutral} {
+			sb.WriteString(fmt.Sprintf(",%d,%d,%.1f,%.1f", split.Wins, split.Losses, split.OpponentELO, split.Change))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
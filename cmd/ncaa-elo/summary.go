@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// UpsetBand is how often favorites of one strength lost
+type UpsetBand struct {
+	Low      float64 `json:"low"` // Favorite's pre-game win probability, from Low up to High
+	High     float64 `json:"high"`
+	Games    int     `json:"games"`
+	Upsets   int     `json:"upsets"`
+	Rate     float64 `json:"upset_rate"`
+	Expected float64 `json:"expected_rate"` // One less the favorites' average win probability
+}
+
+// RatingSpread summarizes the final rating means
+type RatingSpread struct {
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+	Min    float64 `json:"min"`
+	P10    float64 `json:"p10"`
+	P25    float64 `json:"p25"`
+	Median float64 `json:"median"`
+	P75    float64 `json:"p75"`
+	P90    float64 `json:"p90"`
+	Max    float64 `json:"max"`
+	Bins   []int   `json:"bins"` // Teams per 100 points, from BinStart up
+	BinMin float64 `json:"bin_start"`
+}
+
+// SeasonSummary is a season health check: how many games were rated, how
+// they went, and how spread out the final ratings are
+type SeasonSummary struct {
+	Season        int          `json:"season"`
+	Games         int          `json:"games"`
+	Teams         int          `json:"teams"`
+	FirstDate     string       `json:"first_date"`
+	LastDate      string       `json:"last_date"`
+	HomeGames     int          `json:"home_games"` // Games not at a neutral site
+	HomeWins      int          `json:"home_wins"`
+	HomeWinRate   float64      `json:"home_win_rate"`
+	NeutralGames  int          `json:"neutral_games"`
+	ScoredGames   int          `json:"scored_games"` // Games logged with their scores
+	AvgMargin     float64      `json:"avg_margin"`
+	PeriodGames   int          `json:"period_games"` // Games logged with their number of periods
+	OvertimeGames int          `json:"overtime_games"`
+	OvertimeRate  float64      `json:"overtime_rate"`
+	UpsetBands    []UpsetBand  `json:"upset_bands"`
+	Upsets        int          `json:"upsets"`
+	UpsetRate     float64      `json:"upset_rate"`
+	Ratings       RatingSpread `json:"ratings"`
+}
+
+// summaryCommand implements the summary command: one-stop statistics on the
+// season's games and ratings
+func summaryCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	return func() {
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		summary := seasonSummary(elo)
+		summary.Season = *engine.season
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(summary, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Print(formatSummary(summary))
+		}
+	}
+}
+
+// seasonSummary tallies the game log and final ratings. Margins and
+// overtimes count only the games logged with scores and periods, which
+// states saved by older versions lack.
+func seasonSummary(elo *model.BayesianELO) SeasonSummary {
+	s := SeasonSummary{Games: len(elo.GameLog), Teams: len(elo.Teams)}
+	for tenth := 5; tenth < 10; tenth++ {
+		s.UpsetBands = append(s.UpsetBands, UpsetBand{Low: float64(tenth) / 10, High: float64(tenth+1) / 10})
+	}
+
+	margins := 0
+	for _, g := range elo.GameLog {
+		if s.FirstDate == "" || g.Date < s.FirstDate {
+			s.FirstDate = g.Date
+		}
+		if g.Date > s.LastDate {
+			s.LastDate = g.Date
+		}
+		switch g.HomeAdvantage {
+		case "H":
+			s.HomeGames++
+			s.HomeWins++
+		case "A":
+			s.HomeGames++
+		default:
+			s.NeutralGames++
+		}
+		if g.WinnerScore > g.LoserScore {
+			s.ScoredGames++
+			margins += g.WinnerScore - g.LoserScore
+		}
+		if g.Periods > 0 {
+			s.PeriodGames++
+			if g.Periods > 2 {
+				s.OvertimeGames++
+			}
+		}
+
+		// WinProb is the actual winner's chance, so the favorite lost below 0.5;
+		// even games have no favorite to upset
+		if g.WinProb == 0.5 {
+			continue
+		}
+		favorite := math.Max(g.WinProb, 1-g.WinProb)
+		band := &s.UpsetBands[min(int((favorite-0.5)*10), len(s.UpsetBands)-1)]
+		band.Games++
+		band.Expected += 1 - favorite
+		if g.WinProb < 0.5 {
+			band.Upsets++
+			s.Upsets++
+		}
+	}
+
+	if s.HomeGames > 0 {
+		s.HomeWinRate = float64(s.HomeWins) / float64(s.HomeGames)
+	}
+	if s.ScoredGames > 0 {
+		s.AvgMargin = float64(margins) / float64(s.ScoredGames)
+	}
+	if s.PeriodGames > 0 {
+		s.OvertimeRate = float64(s.OvertimeGames) / float64(s.PeriodGames)
+	}
+	decided := 0
+	for i := range s.UpsetBands {
+		if b := &s.UpsetBands[i]; b.Games > 0 {
+			b.Rate = float64(b.Upsets) / float64(b.Games)
+			b.Expected /= float64(b.Games)
+			decided += b.Games
+		}
+	}
+	if decided > 0 {
+		s.UpsetRate = float64(s.Upsets) / float64(decided)
+	}
+	s.Ratings = ratingSpread(elo)
+	return s
+}
+
+// ratingSpread summarizes the teams' final rating means, with a histogram in
+// 100-point bins
+func ratingSpread(elo *model.BayesianELO) RatingSpread {
+	var means []float64
+	for _, team := range elo.Teams {
+		means = append(means, team.Dist.Mean())
+	}
+	if len(means) == 0 {
+		return RatingSpread{Bins: []int{}}
+	}
+	sort.Float64s(means)
+	quantile := func(q float64) float64 {
+		return means[min(int(q*float64(len(means))), len(means)-1)]
+	}
+
+	r := RatingSpread{
+		Min:    means[0],
+		P10:    quantile(0.10),
+		P25:    quantile(0.25),
+		Median: quantile(0.50),
+		P75:    quantile(0.75),
+		P90:    quantile(0.90),
+		Max:    means[len(means)-1],
+		BinMin: math.Floor(means[0]/100) * 100,
+	}
+	for _, m := range means {
+		r.Mean += m / float64(len(means))
+	}
+	for _, m := range means {
+		r.StdDev += (m - r.Mean) * (m - r.Mean) / float64(len(means))
+	}
+	r.StdDev = math.Sqrt(r.StdDev)
+	r.Bins = make([]int, int((r.Max-r.BinMin)/100)+1)
+	for _, m := range means {
+		r.Bins[int((m-r.BinMin)/100)]++
+	}
+	return r
+}
+
+// formatSummary renders the season summary as text
+func formatSummary(s SeasonSummary) string {
+	var sb strings.Builder
+	width := 64
+
+	sb.WriteString(fmt.Sprintf("Season Summary (%d-%d Season)\n", s.Season-1, s.Season))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-22s %d (%s to %s)\n", "Games", s.Games, s.FirstDate, s.LastDate))
+	sb.WriteString(fmt.Sprintf("%-22s %d\n", "Teams", s.Teams))
+	sb.WriteString(fmt.Sprintf("%-22s %.1f%% of %d (%d neutral-site games)\n", "Home win rate", s.HomeWinRate*100, s.HomeGames, s.NeutralGames))
+	if s.ScoredGames > 0 {
+		sb.WriteString(fmt.Sprintf("%-22s %.1f points (%d games with scores)\n", "Average margin", s.AvgMargin, s.ScoredGames))
+	} else {
+		sb.WriteString(fmt.Sprintf("%-22s not logged\n", "Average margin"))
+	}
+	if s.PeriodGames > 0 {
+		sb.WriteString(fmt.Sprintf("%-22s %.1f%% (%d of %d games)\n", "Overtime", s.OvertimeRate*100, s.OvertimeGames, s.PeriodGames))
+	} else {
+		sb.WriteString(fmt.Sprintf("%-22s not logged\n", "Overtime"))
+	}
+	sb.WriteString(fmt.Sprintf("%-22s %.1f%% (%d games)\n", "Upset rate", s.UpsetRate*100, s.Upsets))
+
+	sb.WriteString("\nUpsets by Favorite's Pre-Game Chance\n")
+	sb.WriteString(fmt.Sprintf("%-10s %7s %7s %8s %9s\n", "Favorite", "Games", "Upsets", "Rate", "Expected"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, b := range s.UpsetBands {
+		sb.WriteString(fmt.Sprintf("%-10s %7d %7d %7.1f%% %8.1f%%\n",
+			fmt.Sprintf("%.0f-%.0f%%", b.Low*100, b.High*100), b.Games, b.Upsets, b.Rate*100, b.Expected*100))
+	}
+
+	r := s.Ratings
+	sb.WriteString("\nFinal Rating Means\n")
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	sb.WriteString(fmt.Sprintf("Mean %.1f, std dev %.1f\n", r.Mean, r.StdDev))
+	sb.WriteString(fmt.Sprintf("Min %.0f | 10%% %.0f | 25%% %.0f | median %.0f | 75%% %.0f | 90%% %.0f | max %.0f\n",
+		r.Min, r.P10, r.P25, r.Median, r.P75, r.P90, r.Max))
+	peak := 0
+	for _, n := range r.Bins {
+		peak = max(peak, n)
+	}
+	for i, n := range r.Bins {
+		bar := ""
+		if peak > 0 {
+			bar = strings.Repeat("█", int(math.Round(float64(n)/float64(peak)*40)))
+		}
+		line := fmt.Sprintf("%4.0f-%-4.0f %4d  %s", r.BinMin+float64(i)*100, r.BinMin+float64(i+1)*100, n, bar)
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	return sb.String()
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// SurpriseGame is the result of one team's game the model least expected
+type SurpriseGame struct {
+	Date     string  `json:"date"`
+	Opponent string  `json:"opponent"`
+	Won      bool    `json:"won"`
+	Prob     float64 `json:"prob"` // The model's pre-game chance of this result
+}
+
+// SurpriseTeam is how far a team's results strayed from the model's
+// pre-game expectations over the season
+type SurpriseTeam struct {
+	Rank              int           `json:"rank"`
+	TeamID            string        `json:"team_id"`
+	TeamName          string        `json:"team_name"`
+	Games             int           `json:"games"`
+	Wins              int           `json:"wins"`
+	Losses            int           `json:"losses"`
+	ExpectedWins      float64       `json:"expected_wins"`      // The sum of pre-game win probabilities
+	WinsOverExpected  float64       `json:"wins_over_expected"` // Positive for teams that beat expectations
+	Surprisal         float64       `json:"surprisal"`          // Sum of -ln(pre-game probability of the result)
+	ExpectedSurprisal float64       `json:"expected_surprisal"` // The sum the model expected: each game's entropy
+	SurpriseIndex     float64       `json:"surprise_index"`     // (Surprisal - ExpectedSurprisal) per game
+	MostSurprising    *SurpriseGame `json:"most_surprising,omitempty"`
+}
+
+// surpriseCommand implements the surprise command: which teams most over- and
+// underperformed the model's pre-game expectations
+func surpriseCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	topN := fs.Int("top", 25, "Number of teams to display")
+	showAll := fs.Bool("all", false, "Show all teams, not just top N")
+	minGames := fs.Int("min-games", 5, "Leave out teams with fewer games")
+	sortBy := fs.String("sort", "over", "Order by wins 'over' expected, most 'under' expected, or 'surprise' index")
+	outputFormat := fs.String("format", "table", "Output format: 'table', 'json', or 'csv'")
+	return func() {
+		var less func(a, b SurpriseTeam) bool
+		switch *sortBy {
+		case "over":
+			less = func(a, b SurpriseTeam) bool { return a.WinsOverExpected > b.WinsOverExpected }
+		case "under":
+			less = func(a, b SurpriseTeam) bool { return a.WinsOverExpected < b.WinsOverExpected }
+		case "surprise":
+			less = func(a, b SurpriseTeam) bool { return a.SurpriseIndex > b.SurpriseIndex }
+		default:
+			fmt.Fprintf(os.Stderr, "Error: invalid -sort %q (expected over, under, or surprise)\n", *sortBy)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		teams := surpriseTeams(elo, *minGames)
+		sort.SliceStable(teams, func(i, j int) bool { return less(teams[i], teams[j]) })
+		if !*showAll {
+			teams = teams[:min(*topN, len(teams))]
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			fmt.Println(output.JSON(teams))
+		case output.FormatCSV:
+			fmt.Print(formatSurpriseCSV(teams))
+		default:
+			fmt.Print(formatSurpriseTable(teams, *engine.season))
+		}
+	}
+}
+
+// surpriseTeams scores every rated team with at least minGames games against
+// the pre-game probabilities in the game log, in rank order
+func surpriseTeams(elo *model.BayesianELO, minGames int) []SurpriseTeam {
+	byTeam := make(map[string]*SurpriseTeam)
+	var teams []*SurpriseTeam
+	for i, team := range elo.GetRankings() {
+		t := &SurpriseTeam{Rank: i + 1, TeamID: team.TeamID, TeamName: team.TeamName}
+		byTeam[team.TeamID] = t
+		teams = append(teams, t)
+	}
+
+	// p is the team's pre-game chance of winning
+	add := func(teamID, opponent, date string, won bool, p float64) {
+		t, ok := byTeam[teamID]
+		if !ok {
+			return
+		}
+		t.Games++
+		t.ExpectedWins += p
+		result := p // The chance of what happened
+		if won {
+			t.Wins++
+		} else {
+			t.Losses++
+			result = 1 - p
+		}
+		t.Surprisal -= math.Log(math.Max(result, 1e-15))
+		if p > 0 && p < 1 {
+			t.ExpectedSurprisal -= p*math.Log(p) + (1-p)*math.Log(1-p)
+		}
+		if t.MostSurprising == nil || result < t.MostSurprising.Prob {
+			t.MostSurprising = &SurpriseGame{Date: date, Opponent: opponent, Won: won, Prob: result}
+		}
+	}
+	for _, g := range elo.GameLog {
+		// WinProb is the actual winner's pre-game chance
+		add(g.WinnerID, g.LoserName, g.Date, true, g.WinProb)
+		add(g.LoserID, g.WinnerName, g.Date, false, 1-g.WinProb)
+	}
+
+	var out []SurpriseTeam
+	for _, t := range teams {
+		if t.Games == 0 || t.Games < minGames {
+			continue
+		}
+		t.WinsOverExpected = float64(t.Wins) - t.ExpectedWins
+		t.SurpriseIndex = (t.Surprisal - t.ExpectedSurprisal) / float64(t.Games)
+		out = append(out, *t)
+	}
+	return out
+}
+
+// formatSurpriseTable renders the surprise scores as a text table
+func formatSurpriseTable(teams []SurpriseTeam, season int) string {
+	var sb strings.Builder
+	width := 116
+
+	sb.WriteString(fmt.Sprintf("Results Against Expectations (%d-%d Season)\n", season-1, season))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-4s %-26s %7s %8s %7s %9s  %s\n", "Rank", "Team", "Record", "Expected", "+/- W", "Surprise", "Most Surprising Result"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, t := range teams {
+		most := ""
+		if g := t.MostSurprising; g != nil {
+			result := "Lost to"
+			if g.Won {
+				result = "Beat"
+			}
+			most = fmt.Sprintf("%s %s %s (%.0f%%)", g.Date, result, output.Truncate(g.Opponent, 26), g.Prob*100)
+		}
+		sb.WriteString(fmt.Sprintf("%-4d %-26s %7s %8s %+7.1f %+9.2f  %s\n",
+			t.Rank, output.Truncate(t.TeamName, 26),
+			fmt.Sprintf("%d-%d", t.Wins, t.Losses),
+			fmt.Sprintf("%.1f-%.1f", t.ExpectedWins, float64(t.Games)-t.ExpectedWins),
+			t.WinsOverExpected, t.SurpriseIndex, most))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString("\nExpected sums the pre-game win probabilities. Surprise is the log loss per game beyond what the\n")
+	sb.WriteString("model expected: positive when results were less predictable than forecast, whichever way they went.\n")
+	return sb.String()
+}
+
+// formatSurpriseCSV renders the surprise scores as CSV
+func formatSurpriseCSV(teams []SurpriseTeam) string {
+	var sb strings.Builder
+	sb.WriteString("rank,team_id,team_name,games,wins,losses,expected_wins,wins_over_expected,surprisal,expected_surprisal,surprise_index\n")
+	for _, t := range teams {
+		sb.WriteString(fmt.Sprintf("%d,%s,\"%s\",%d,%d,%d,%.2f,%.2f,%.3f,%.3f,%.4f\n",
+			t.Rank, t.TeamID, t.TeamName, t.Games, t.Wins, t.Losses,
+			t.ExpectedWins, t.WinsOverExpected, t.Surprisal, t.ExpectedSurprisal, t.SurpriseIndex))
+	}
+	return sb.String()
+}
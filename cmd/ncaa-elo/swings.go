@@ -0,0 +1,154 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// RatingSwing is the change in one team's rating from a single game
+type RatingSwing struct {
+	Date     string  `json:"date"`
+	GameID   string  `json:"game_id"`
+	TeamID   string  `json:"team_id"`
+	TeamName string  `json:"team_name"`
+	Opponent string  `json:"opponent"`
+	Won      bool    `json:"won"`
+	Site     string  `json:"site"`     // "home", "road", or "neutral"
+	WinProb  float64 `json:"win_prob"` // The team's pre-game chance of winning
+	PreELO   float64 `json:"pre_elo"`
+	PostELO  float64 `json:"post_elo"`
+	Change   float64 `json:"change"`
+}
+
+// swingsCommand implements the swings command: the single games that moved
+// ratings the most, league-wide or for one team
+func swingsCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	topN := fs.Int("top", 25, "Number of games to display")
+	direction := fs.String("direction", "both", "Show the biggest gains ('up'), drops ('down'), or 'both'")
+	outputFormat := fs.String("format", "table", "Output format: 'table', 'json', or 'csv'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ncaa-bayes-elo swings [flags] [team]")
+		fs.PrintDefaults()
+	}
+	return func() {
+		if *direction != "both" && *direction != "up" && *direction != "down" {
+			fmt.Fprintf(os.Stderr, "Error: invalid -direction %q (expected both, up, or down)\n", *direction)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		if len(elo.GameLog) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: no games have been rated")
+			os.Exit(1)
+		}
+		if elo.GameLog[0].WinnerPostStd == 0 {
+			fmt.Fprintln(os.Stderr, "Error: this state has no post-game ratings (replay it with -as-of to fill them in)")
+			os.Exit(1)
+		}
+
+		title := "League-Wide"
+		teamID := ""
+		if fs.NArg() > 0 {
+			team, err := findTeam(elo, strings.Join(fs.Args(), " "))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			title, teamID = team.TeamName, team.TeamID
+		}
+
+		swings := ratingSwings(elo.GameLog, teamID, *direction)
+		swings = swings[:min(*topN, len(swings))]
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			fmt.Println(output.JSON(swings))
+		case output.FormatCSV:
+			fmt.Print(formatSwingsCSV(swings))
+		default:
+			fmt.Print(formatSwingsTable(swings, title, *engine.season))
+		}
+	}
+}
+
+// ratingSwings lists each team's rating change from every game in the log,
+// or only teamID's if given, biggest first. direction "up" keeps only gains
+// and "down" only drops.
+func ratingSwings(log []model.GameResult, teamID, direction string) []RatingSwing {
+	swings := []RatingSwing{}
+	add := func(s RatingSwing) {
+		if (teamID != "" && s.TeamID != teamID) || (direction == "up" && s.Change <= 0) || (direction == "down" && s.Change >= 0) {
+			return
+		}
+		swings = append(swings, s)
+	}
+	for _, g := range log {
+		if g.WinnerPostStd == 0 {
+			continue
+		}
+		winnerSite, loserSite := "neutral", "neutral"
+		switch g.HomeAdvantage {
+		case "H":
+			winnerSite, loserSite = "home", "road"
+		case "A":
+			winnerSite, loserSite = "road", "home"
+		}
+		add(RatingSwing{
+			Date: g.Date, GameID: g.GameID, TeamID: g.WinnerID, TeamName: g.WinnerName, Opponent: g.LoserName,
+			Won: true, Site: winnerSite, WinProb: g.WinProb,
+			PreELO: g.WinnerELO, PostELO: g.WinnerPostELO, Change: g.WinnerPostELO - g.WinnerELO,
+		})
+		add(RatingSwing{
+			Date: g.Date, GameID: g.GameID, TeamID: g.LoserID, TeamName: g.LoserName, Opponent: g.WinnerName,
+			Won: false, Site: loserSite, WinProb: 1 - g.WinProb,
+			PreELO: g.LoserELO, PostELO: g.LoserPostELO, Change: g.LoserPostELO - g.LoserELO,
+		})
+	}
+	sort.SliceStable(swings, func(i, j int) bool { return math.Abs(swings[i].Change) > math.Abs(swings[j].Change) })
+	return swings
+}
+
+// formatSwingsTable renders rating swings as a text table
+func formatSwingsTable(swings []RatingSwing, title string, season int) string {
+	var sb strings.Builder
+	width := 112
+
+	sb.WriteString(fmt.Sprintf("Biggest Single-Game Rating Swings: %s (%d-%d Season)\n", title, season-1, season))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-10s %-26s %-6s %-26s %-7s %6s %8s %8s %8s\n", "Date", "Team", "Result", "Opponent", "Site", "Chance", "Before", "After", "Change"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, s := range swings {
+		result := "L"
+		if s.Won {
+			result = "W"
+		}
+		sb.WriteString(fmt.Sprintf("%-10s %-26s %-6s %-26s %-7s %5.0f%% %8.1f %8.1f %s\n",
+			s.Date, output.Truncate(s.TeamName, 26), result, output.Truncate(s.Opponent, 26), s.Site,
+			s.WinProb*100, s.PreELO, s.PostELO, output.PadLeft(output.FormatTrend(s.Change), 8)))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString("\nChance is the team's pre-game win probability; Before and After are its mean rating around the game.\n")
+	return sb.String()
+}
+
+// formatSwingsCSV renders rating swings as CSV
+func formatSwingsCSV(swings []RatingSwing) string {
+	var sb strings.Builder
+	sb.WriteString("date,game_id,team_id,team_name,opponent,won,site,win_prob,pre_elo,post_elo,change\n")
+	for _, s := range swings {
+		sb.WriteString(fmt.Sprintf("%s,%s,%s,\"%s\",\"%s\",%t,%s,%.4f,%.1f,%.1f,%.1f\n",
+			s.Date, s.GameID, s.TeamID, s.TeamName, s.Opponent, s.Won, s.Site, s.WinProb, s.PreELO, s.PostELO, s.Change))
+	}
+	return sb.String()
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// teamCommand implements the team command: a team's rating distribution, looked
+// up by ID or name
+func teamCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ncaa-bayes-elo team [flags] <team>")
+		fs.PrintDefaults()
+	}
+	return func() {
+		if fs.NArg() == 0 {
+			fs.Usage()
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		team, err := findTeam(elo, strings.Join(fs.Args(), " "))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		elo.PrintTeamDistribution(team.TeamID)
+		printTeamGames(elo, team.TeamID)
+	}
+}
+
+// printTeamGames lists a team's games in order, with its pre-game win
+// probability and how each result moved its rating
+func printTeamGames(elo *model.BayesianELO, teamID string) {
+	var games []model.GameResult
+	for _, g := range elo.GameLog {
+		if g.WinnerID == teamID || g.LoserID == teamID {
+			games = append(games, g)
+		}
+	}
+	if len(games) == 0 {
+		return
+	}
+	sort.SliceStable(games, func(i, j int) bool { return games[i].Date < games[j].Date })
+
+	fmt.Printf("\n  %-10s %-32s %-6s %6s %8s %8s %8s\n", "Date", "Opponent", "Result", "Win %", "Pre", "Post", "Change")
+	fmt.Printf("  %s\n", strings.Repeat("-", 84))
+	for _, g := range games {
+		won := g.WinnerID == teamID
+		opponent, prob := g.LoserName, g.WinProb
+		pre, post, postStd := g.WinnerELO, g.WinnerPostELO, g.WinnerPostStd
+		if !won {
+			opponent, prob = g.WinnerName, 1-g.WinProb
+			pre, post, postStd = g.LoserELO, g.LoserPostELO, g.LoserPostStd
+		}
+
+		// "vs" at home, "@" away, and "n" at a neutral site
+		site := "vs"
+		switch {
+		case g.HomeAdvantage == "N":
+			site = "n"
+		case (g.HomeAdvantage == "H") != won:
+			site = "@"
+		}
+		result := "L"
+		if won {
+			result = "W"
+		}
+
+		// States saved before post-game ratings were logged have none to show
+		postCol, change := fmt.Sprintf("%8s", "-"), fmt.Sprintf("%8s", "-")
+		if postStd > 0 {
+			postCol, change = fmt.Sprintf("%8.1f", post), output.PadLeft(output.FormatTrend(post-pre), 8)
+		}
+		fmt.Printf("  %-10s %-32s %-6s %5.1f%% %8.1f %s %s\n",
+			g.Date, output.Truncate(site+" "+opponent, 32), result, prob*100, pre, postCol, change)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+)
+
+// findTeam resolves a team ID or name. Names match case-insensitively and
+// ignoring punctuation: first an alias from the aliases file, then exactly,
+// then as a unique prefix (e.g. "duke" for "Duke Blue Devils"), word prefixes
+// ("mich st"), initials ("nc" for "North Carolina Tar Heels"), substring, and
+// finally allowing a typo or two ("gonzga"). An ambiguous name lists the
+// candidates in the error.
+func findTeam(elo *model.BayesianELO, query string) (*model.TeamRating, error) {
+	matches := matchTeams(elo.Teams, query)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no team matches %q", query)
+	case 1:
+		return matches[0], nil
+	}
+
+	names := make([]string, len(matches))
+	for i, team := range matches {
+		names[i] = team.TeamName
+	}
+	sort.Strings(names)
+	if len(names) > 5 {
+		names = append(names[:5], fmt.Sprintf("%d more", len(matches)-5))
+	}
+	return nil, fmt.Errorf("%q matches several teams: %s", query, strings.Join(names, ", "))
+}
+
+// matchTeams returns the teams in the best tier of findTeam's matches for a
+// query, in no particular order
+func matchTeams(teams map[string]*model.TeamRating, query string) []*model.TeamRating {
+	query = strings.TrimSpace(query)
+	if team, ok := teams[query]; ok {
+		return []*model.TeamRating{team}
+	}
+
+	q := normalizeName(query)
+	if q == "" {
+		return nil
+	}
+	if team, ok := teams[teamAliases[q]]; ok {
+		return []*model.TeamRating{team}
+	}
+	var prefix, words, initials, contains []*model.TeamRating
+	for _, team := range teams {
+		name := normalizeName(team.TeamName)
+		switch {
+		case name == q:
+			return []*model.TeamRating{team}
+		case strings.HasPrefix(name, q):
+			prefix = append(prefix, team)
+		case matchesWordPrefixes(name, q):
+			words = append(words, team)
+		case matchesInitials(name, q):
+			initials = append(initials, team)
+		case strings.Contains(name, q):
+			contains = append(contains, team)
+		}
+	}
+
+	for _, tier := range [][]*model.TeamRating{prefix, words, initials, contains} {
+		if len(tier) > 0 {
+			return tier
+		}
+	}
+	return closestTeams(teams, q)
+}
+
+// normalizeName lower-cases a team name and drops punctuation, so "St. John's"
+// and "st johns" compare equal. Hyphens and runs of spaces become one space.
+func normalizeName(s string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && sb.Len() > 0 {
+				sb.WriteByte(' ')
+			}
+			space = false
+			sb.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '/':
+			space = true
+		}
+	}
+	return sb.String()
+}
+
+// matchesWordPrefixes reports whether each word of q starts the matching word
+// of name, from the first word on
+func matchesWordPrefixes(name, q string) bool {
+	nameWords, qWords := strings.Fields(name), strings.Fields(q)
+	if len(qWords) < 2 || len(qWords) > len(nameWords) {
+		return false
+	}
+	for i, w := range qWords {
+		if !strings.HasPrefix(nameWords[i], w) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesInitials reports whether q is the initials of name's leading words,
+// with or without the mascot
+func matchesInitials(name, q string) bool {
+	if len(q) < 2 || strings.Contains(q, " ") {
+		return false
+	}
+	var initials strings.Builder
+	for _, w := range strings.Fields(name) {
+		initials.WriteByte(w[0])
+		if initials.String() == q {
+			return true
+		}
+	}
+	return false
+}
+
+// closestTeams returns the teams whose name, or name without the mascot, is
+// fewest edits from q, allowing one edit per four letters
+func closestTeams(teams map[string]*model.TeamRating, q string) []*model.TeamRating {
+	limit := len(q) / 4
+	if limit == 0 {
+		return nil
+	}
+
+	best := limit + 1
+	var closest []*model.TeamRating
+	for _, team := range teams {
+		name := normalizeName(team.TeamName)
+		d := editDistance(name, q)
+		if i := strings.LastIndexByte(name, ' '); i > 0 {
+			d = min(d, editDistance(name[:i], q))
+		}
+		switch {
+		case d > limit:
+		case d < best:
+			best, closest = d, []*model.TeamRating{team}
+		case d == best:
+			closest = append(closest, team)
+		}
+	}
+	return closest
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+)
+
+// lookupTeams is a small league with names that collide across tiers
+var lookupTeams = map[string]*model.TeamRating{
+	"150":  {TeamID: "150", TeamName: "Duke Blue Devils"},
+	"153":  {TeamID: "153", TeamName: "North Carolina Tar Heels"},
+	"152":  {TeamID: "152", TeamName: "NC State Wolfpack"},
+	"127":  {TeamID: "127", TeamName: "Michigan State Spartans"},
+	"130":  {TeamID: "130", TeamName: "Michigan Wolverines"},
+	"2599": {TeamID: "2599", TeamName: "St. John's Red Storm"},
+	"2250": {TeamID: "2250", TeamName: "Gonzaga Bulldogs"},
+	"2306": {TeamID: "2306", TeamName: "Kansas State Wildcats"},
+	"2305": {TeamID: "2305", TeamName: "Kansas Jayhawks"},
+	"2509": {TeamID: "2509", TeamName: "Purdue Boilermakers"},
+}
+
+func TestMatchTeams(t *testing.T) {
+	defer func(saved map[string]string) { teamAliases = saved }(teamAliases)
+	teamAliases = map[string]string{"unc": "153", "zags": "2250"}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"ID", "150", []string{"150"}},
+		{"alias", "UNC", []string{"153"}},
+		{"alias ignores punctuation", "Zags!", []string{"2250"}},
+		{"exact name", "michigan wolverines", []string{"130"}},
+		{"exact beats prefix", "Kansas Jayhawks", []string{"2305"}},
+		{"exact ignores punctuation", "st johns red storm", []string{"2599"}},
+		{"unique prefix", "duke", []string{"150"}},
+		{"ambiguous prefix", "michigan", []string{"127", "130"}},
+		{"prefix with punctuation", "st. john's", []string{"2599"}},
+		{"word prefixes", "mich st", []string{"127"}},
+		{"word prefixes need as many words", "kan s", []string{"2306"}},
+		{"initials", "ncth", []string{"153"}},
+		{"initials without the mascot", "ms", []string{"127"}},
+		{"single letter is a prefix", "d", []string{"150"}},
+		{"substring", "wolfpack", []string{"152"}},
+		{"substring across teams", "state", []string{"127", "152", "2306"}},
+		{"typo", "gonzga", []string{"2250"}},
+		{"typo in the full name", "purdue boilermakrs", []string{"2509"}},
+		{"short queries allow no typos", "dke", nil},
+		{"too many typos", "gnzga", nil},
+		{"blank", "  ", nil},
+		{"punctuation only", "...", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, team := range matchTeams(lookupTeams, tt.query) {
+				got = append(got, team.TeamID)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("matchTeams(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindTeam(t *testing.T) {
+	elo := model.NewBayesianELO()
+	elo.Teams = lookupTeams
+
+	tests := []struct {
+		query   string
+		want    string
+		wantErr string
+	}{
+		{"duke", "150", ""},
+		{"michigan", "", `"michigan" matches several teams: Michigan State Spartans, Michigan Wolverines`},
+		{"xavier", "", `no team matches "xavier"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			team, err := findTeam(elo, tt.query)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("findTeam(%q) error = %v, want %q", tt.query, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findTeam(%q): %v", tt.query, err)
+			}
+			if team.TeamID != tt.want {
+				t.Errorf("findTeam(%q) = %s, want %s", tt.query, team.TeamID, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"St. John's Red Storm", "st johns red storm"},
+		{"Texas A&M-Corpus Christi", "texas am corpus christi"},
+		{"  UL  Monroe/Lafayette ", "ul monroe lafayette"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeName(tt.name); got != tt.want {
+			t.Errorf("normalizeName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// TeamListing is a team's entry in the teams command's list
+type TeamListing struct {
+	TeamID     string  `json:"team_id"`
+	TeamName   string  `json:"team_name"`
+	Conference string  `json:"conference,omitempty"`
+	Rank       int     `json:"rank"`
+	MeanELO    float64 `json:"mean_elo"`
+	StdDev     float64 `json:"std_dev"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+}
+
+// teamsCommand implements the teams command: list the rated teams with their IDs
+// so they can be passed to other commands, optionally searching by name
+func teamsCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	search := fs.String("search", "", "Only list teams matching this ID or name, matched like -team")
+	sortBy := fs.String("sort", "name", "Sort by 'name', 'rank', or 'id'")
+	outputFormat := fs.String("format", "table", "Output format: 'table', 'json', or 'csv'")
+	return func() {
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		teams := elo.GetRankings()
+		if *search != "" {
+			teams = matchTeams(elo.Teams, *search)
+			if len(teams) == 0 {
+				fmt.Fprintf(os.Stderr, "No team matches %q\n", *search)
+				os.Exit(1)
+			}
+		}
+
+		listings, err := teamListings(elo, teams, *sortBy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			fmt.Println(output.JSON(listings))
+		case output.FormatCSV:
+			fmt.Print(formatTeamsCSV(listings))
+		default:
+			fmt.Print(formatTeamsTable(listings))
+		}
+	}
+}
+
+// teamListings describes teams sorted by name, rank, or ID
+func teamListings(elo *model.BayesianELO, teams []*model.TeamRating, sortBy string) ([]TeamListing, error) {
+	ranks := elo.Ranks()
+	records := model.Records(elo.GameLog)
+	listings := make([]TeamListing, len(teams))
+	for i, team := range teams {
+		rec := records[team.TeamID]
+		listings[i] = TeamListing{
+			TeamID:     team.TeamID,
+			TeamName:   team.TeamName,
+			Conference: team.Conference,
+			Rank:       ranks[team.TeamID],
+			MeanELO:    team.Dist.Mean(),
+			StdDev:     team.Dist.Std(),
+			Wins:       rec.Wins,
+			Losses:     rec.Losses,
+		}
+	}
+
+	var less func(a, b TeamListing) bool
+	switch sortBy {
+	case "name":
+		less = func(a, b TeamListing) bool { return strings.ToLower(a.TeamName) < strings.ToLower(b.TeamName) }
+	case "rank":
+		less = func(a, b TeamListing) bool { return a.Rank < b.Rank }
+	case "id":
+		less = func(a, b TeamListing) bool {
+			if len(a.TeamID) != len(b.TeamID) {
+				return len(a.TeamID) < len(b.TeamID) // Numeric IDs in numeric order
+			}
+			return a.TeamID < b.TeamID
+		}
+	default:
+		return nil, fmt.Errorf("invalid -sort %q (expected name, rank, or id)", sortBy)
+	}
+	sort.Slice(listings, func(i, j int) bool { return less(listings[i], listings[j]) })
+	return listings, nil
+}
+
+// formatTeamsTable renders team listings as a text table, with a conference
+// column when conferences are known
+func formatTeamsTable(teams []TeamListing) string {
+	var sb strings.Builder
+	width := 72
+
+	showConference := false
+	for _, t := range teams {
+		showConference = showConference || t.Conference != ""
+	}
+	teamColumn := func(name, conference string) string {
+		column := fmt.Sprintf("%-30s", output.Truncate(name, 30))
+		if showConference {
+			column += fmt.Sprintf(" %-14s", output.Truncate(conference, 14))
+		}
+		return column
+	}
+	if showConference {
+		width += 15
+	}
+
+	sb.WriteString(fmt.Sprintf("%-8s %s %6s %8s %8s %8s\n", "ID", teamColumn("Team", "Conference"), "Rank", "Mean", "StdDev", "Record"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, t := range teams {
+		sb.WriteString(fmt.Sprintf("%-8s %s %6d %8.1f %8.1f %8s\n",
+			t.TeamID,
+			teamColumn(t.TeamName, t.Conference),
+			t.Rank,
+			t.MeanELO,
+			t.StdDev,
+			fmt.Sprintf("%d-%d", t.Wins, t.Losses)))
+	}
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%d teams\n", len(teams)))
+	return sb.String()
+}
+
+// formatTeamsCSV renders team listings as CSV
+func formatTeamsCSV(teams []TeamListing) string {
+	var sb strings.Builder
+	sb.WriteString("team_id,team_name,conference,rank,mean_elo,std_dev,wins,losses\n")
+	for _, t := range teams {
+		sb.WriteString(fmt.Sprintf("%s,\"%s\",\"%s\",%d,%.1f,%.1f,%d,%d\n",
+			t.TeamID, t.TeamName, t.Conference, t.Rank, t.MeanELO, t.StdDev, t.Wins, t.Losses))
+	}
+	return sb.String()
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// tuiHelp is the key reference shown at the bottom of the screen
+const tuiHelp = "[::b]/[::-] search  [::b]v[::-] matchup vs selected  [::b]m[::-] matchup  [::b]Tab[::-] switch pane  [::b]Esc[::-] back  [::b]q[::-] quit"
+
+// tuiCommand implements the tui command: browse the rankings, a team's rating
+// distribution, and matchup predictions in the terminal
+func tuiCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	return func() {
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		if err := newRankingsUI(elo, *engine.season).app.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running terminal UI: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// rankingsUI is the tui command's screen: a searchable rankings table, the
+// selected team's detail, and a matchup predictor
+type rankingsUI struct {
+	app      *tview.Application
+	elo      *model.BayesianELO
+	rankings []*model.TeamRating // Every team, in rating order
+	shown    []*model.TeamRating // The teams matching the search
+	records  map[string]model.Record
+
+	table   *tview.Table
+	search  *tview.InputField
+	detail  *tview.TextView
+	matchup *tview.InputField
+	result  *tview.TextView
+}
+
+// newRankingsUI lays out the screen for a rated season
+func newRankingsUI(elo *model.BayesianELO, season int) *rankingsUI {
+	ui := &rankingsUI{
+		app:      tview.NewApplication(),
+		elo:      elo,
+		rankings: elo.GetRankings(),
+		records:  model.Records(elo.GameLog),
+	}
+
+	ui.table = tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	ui.table.SetBorder(true).SetTitle(fmt.Sprintf(" %d-%d Rankings ", season-1, season))
+	ui.table.SetSelectionChangedFunc(func(row, _ int) { ui.showTeam(row) })
+	ui.table.SetInputCapture(ui.tableKeys)
+
+	ui.search = tview.NewInputField().SetLabel("Search: ")
+	ui.search.SetChangedFunc(ui.filter)
+	ui.search.SetDoneFunc(func(tcell.Key) { ui.app.SetFocus(ui.table) })
+
+	ui.detail = tview.NewTextView().SetDynamicColors(true)
+	ui.detail.SetBorder(true).SetTitle(" Team ")
+
+	ui.matchup = tview.NewInputField().SetLabel("Matchup: ").SetPlaceholder("team vs team")
+	ui.matchup.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			ui.predict()
+		}
+		ui.app.SetFocus(ui.table)
+	})
+	ui.result = tview.NewTextView().SetDynamicColors(true)
+
+	left := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(ui.search, 1, 0, false).
+		AddItem(ui.table, 0, 1, true)
+	panes := tview.NewFlex().
+		AddItem(left, 0, 1, true).
+		AddItem(ui.detail, 52, 0, false)
+	root := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(panes, 0, 1, true).
+		AddItem(ui.matchup, 1, 0, false).
+		AddItem(ui.result, 1, 0, false).
+		AddItem(tview.NewTextView().SetDynamicColors(true).SetText(tuiHelp), 1, 0, false)
+
+	ui.filter("")
+	ui.app.SetRoot(root, true).SetFocus(ui.table)
+	return ui
+}
+
+// tableKeys handles the rankings table's shortcuts
+func (ui *rankingsUI) tableKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyTab:
+		ui.app.SetFocus(ui.detail)
+		ui.detail.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyEscape {
+				ui.app.SetFocus(ui.table)
+				return nil
+			}
+			return event
+		})
+	case event.Key() == tcell.KeyEscape:
+		ui.search.SetText("")
+	case event.Rune() == '/':
+		ui.app.SetFocus(ui.search)
+	case event.Rune() == 'm':
+		ui.app.SetFocus(ui.matchup)
+	case event.Rune() == 'v':
+		if team := ui.selected(); team != nil {
+			ui.matchup.SetText(team.TeamName + " vs ")
+			ui.app.SetFocus(ui.matchup)
+		}
+	case event.Rune() == 'q':
+		ui.app.Stop()
+	default:
+		return event
+	}
+	return nil
+}
+
+// filter shows the teams whose name contains the query or whose ID starts
+// with it
+func (ui *rankingsUI) filter(query string) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	ui.shown = ui.shown[:0]
+	for _, team := range ui.rankings {
+		if strings.Contains(strings.ToLower(team.TeamName), q) || strings.HasPrefix(team.TeamID, q) {
+			ui.shown = append(ui.shown, team)
+		}
+	}
+
+	ui.table.Clear()
+	for col, header := range []string{"Rank", "Team", "Mean", "Std", "Record"} {
+		cell := tview.NewTableCell(header).SetSelectable(false).SetAttributes(tcell.AttrBold)
+		if col != 1 {
+			cell.SetAlign(tview.AlignRight)
+		}
+		ui.table.SetCell(0, col, cell)
+	}
+	ranks := make(map[string]int, len(ui.rankings))
+	for i, team := range ui.rankings {
+		ranks[team.TeamID] = i + 1
+	}
+	for i, team := range ui.shown {
+		rec := ui.records[team.TeamID]
+		row := i + 1
+		ui.table.SetCell(row, 0, tview.NewTableCell(fmt.Sprint(ranks[team.TeamID])).SetAlign(tview.AlignRight))
+		ui.table.SetCell(row, 1, tview.NewTableCell(tview.Escape(team.TeamName)).SetExpansion(1))
+		ui.table.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%.1f", team.Dist.Mean())).SetAlign(tview.AlignRight))
+		ui.table.SetCell(row, 3, tview.NewTableCell(fmt.Sprintf("%.1f", team.Dist.Std())).SetAlign(tview.AlignRight))
+		ui.table.SetCell(row, 4, tview.NewTableCell(fmt.Sprintf("%d-%d", rec.Wins, rec.Losses)).SetAlign(tview.AlignRight))
+	}
+	ui.table.Select(1, 0).ScrollToBeginning()
+	ui.showTeam(1)
+}
+
+// selected returns the team on the table's selected row, if any
+func (ui *rankingsUI) selected() *model.TeamRating {
+	row, _ := ui.table.GetSelection()
+	if row < 1 || row > len(ui.shown) {
+		return nil
+	}
+	return ui.shown[row-1]
+}
+
+// showTeam fills the detail pane with the team on a table row
+func (ui *rankingsUI) showTeam(row int) {
+	ui.detail.Clear()
+	if row < 1 || row > len(ui.shown) {
+		fmt.Fprint(ui.detail, "No teams match")
+		return
+	}
+	team := ui.shown[row-1]
+	d := team.Dist
+	rec := ui.records[team.TeamID]
+
+	fmt.Fprintf(ui.detail, "[::b]%s[::-] (ID: %s)\n", tview.Escape(team.TeamName), team.TeamID)
+	if team.Conference != "" {
+		fmt.Fprintf(ui.detail, "Conference: %s\n", tview.Escape(team.Conference))
+	}
+	fmt.Fprintf(ui.detail, "Record: %d-%d\n\n", rec.Wins, rec.Losses)
+	fmt.Fprintf(ui.detail, "Mean ELO: %7.1f   Std Dev: %5.1f\n", d.Mean(), d.Std())
+	fmt.Fprintf(ui.detail, "5th %%:    %7.1f   95th %%:  %7.1f\n", d.Percentile(5), d.Percentile(95))
+	fmt.Fprintf(ui.detail, "25th %%:   %7.1f   75th %%:  %7.1f\n", d.Percentile(25), d.Percentile(75))
+	fmt.Fprintf(ui.detail, "Median:   %7.1f   MAP:     %7.1f\n", d.Percentile(50), d.Mode())
+	low, high := d.CredibleInterval(0.9)
+	fmt.Fprintf(ui.detail, "90%% CI:   %.0f-%.0f   Skew:  %+.2f\n\n", low, high, d.Skewness())
+	fmt.Fprint(ui.detail, distributionHistogram(d, 16, 28))
+	ui.detail.ScrollToBeginning()
+}
+
+// predict resolves the matchup field and shows both teams' win probabilities
+func (ui *rankingsUI) predict() {
+	ui.result.Clear()
+	a, b, err := splitMatchup(ui.elo, ui.matchup.GetText())
+	if err == nil {
+		var prob float64
+		if prob, err = ui.elo.PredictMatchup(a.TeamID, b.TeamID); err == nil {
+			fmt.Fprintf(ui.result, "[::b]%s[::-] %.1f%% vs [::b]%s[::-] %.1f%% (neutral site)",
+				tview.Escape(a.TeamName), prob*100, tview.Escape(b.TeamName), (1-prob)*100)
+			return
+		}
+	}
+	fmt.Fprintf(ui.result, "[red]%s[-]", tview.Escape(capitalize(err.Error())))
+}
+
+// distributionHistogram draws the central 99% of a distribution as one bar per
+// bin of ELO values, scaled so the tallest bin fills width
+func distributionHistogram(d *model.Distribution, bins, width int) string {
+	grid := d.Step()
+	low := math.Floor(d.Percentile(0.5)/grid) * grid
+	high := math.Ceil(d.Percentile(99.5)/grid) * grid
+	step := math.Max(grid, math.Ceil((high-low+grid)/float64(bins)/grid)*grid)
+
+	mass := make([]float64, bins)
+	for i, v := range d.Values {
+		if v < low || v > high {
+			continue
+		}
+		mass[min(int((v-low)/step), bins-1)] += d.Probs[i]
+	}
+	peak := 0.0
+	for _, m := range mass {
+		peak = math.Max(peak, m)
+	}
+
+	var sb strings.Builder
+	for i, m := range mass {
+		from := low + float64(i)*step
+		if from > high {
+			break
+		}
+		bar := 0
+		if peak > 0 {
+			bar = int(math.Round(m / peak * float64(width)))
+		}
+		sb.WriteString(fmt.Sprintf("%4.0f-%-4.0f %-*s %5.1f%%\n", from, from+step-grid, width, strings.Repeat("█", bar), m*100))
+	}
+	return sb.String()
+}
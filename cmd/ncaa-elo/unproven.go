@@ -0,0 +1,195 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// UnprovenTeam is a team whose rating is still uncertain, with the schedule
+// facts that explain why
+type UnprovenTeam struct {
+	Rank          int     `json:"rank"`
+	TeamID        string  `json:"team_id"`
+	TeamName      string  `json:"team_name"`
+	Conference    string  `json:"conference,omitempty"`
+	Games         int     `json:"games"`
+	Opponents     int     `json:"opponents"`      // Distinct teams played
+	Reach         int     `json:"two_step_reach"` // Distinct teams within two games: opponents and their opponents
+	MainComponent bool    `json:"main_component"` // Connected by games to the largest group of teams
+	MeanELO       float64 `json:"mean_elo"`
+	StdDev        float64 `json:"std_dev"`
+	CI90Low       float64 `json:"ci90_low"`
+	CI90High      float64 `json:"ci90_high"`
+}
+
+// unprovenCommand implements the unproven command: the teams whose ratings
+// should be trusted least, with how few games and connections back them up
+func unprovenCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	minStd := fs.Float64("min-std", 0, "List teams whose standard deviation is above this (0 = 20% above the median team's)")
+	topN := fs.Int("top", 25, "Number of teams to display, most uncertain first")
+	showAll := fs.Bool("all", false, "Show every team above the threshold")
+	outputFormat := fs.String("format", "table", "Output format: 'table', 'json', or 'csv'")
+	return func() {
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+
+		threshold := *minStd
+		if threshold <= 0 {
+			threshold = 1.2 * medianStd(elo)
+		}
+		teams := unprovenTeams(elo, threshold)
+		total := len(teams)
+		if !*showAll {
+			teams = teams[:min(*topN, len(teams))]
+		}
+
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			fmt.Println(output.JSON(teams))
+		case output.FormatCSV:
+			fmt.Print(formatUnprovenCSV(teams))
+		default:
+			fmt.Print(formatUnprovenTable(teams, total, threshold, *engine.season))
+		}
+	}
+}
+
+// medianStd returns the median team's rating standard deviation
+func medianStd(elo *model.BayesianELO) float64 {
+	var stds []float64
+	for _, team := range elo.Teams {
+		stds = append(stds, team.Dist.Std())
+	}
+	if len(stds) == 0 {
+		return 0
+	}
+	sort.Float64s(stds)
+	return stds[len(stds)/2]
+}
+
+// unprovenTeams lists the teams whose standard deviation is above threshold,
+// most uncertain first, with their games, distinct opponents, two-step reach
+// through the schedule, and whether they connect to its main component
+func unprovenTeams(elo *model.BayesianELO, threshold float64) []UnprovenTeam {
+	games := make(map[string]int)
+	opponents := make(map[string]map[string]bool)
+	link := func(a, b string) {
+		if opponents[a] == nil {
+			opponents[a] = make(map[string]bool)
+		}
+		opponents[a][b] = true
+	}
+	for _, g := range elo.GameLog {
+		games[g.WinnerID]++
+		games[g.LoserID]++
+		link(g.WinnerID, g.LoserID)
+		link(g.LoserID, g.WinnerID)
+	}
+
+	// Label each team with its connected component, then find the largest
+	component := make(map[string]int)
+	sizes := []int{0}
+	for id := range opponents {
+		if component[id] != 0 {
+			continue
+		}
+		sizes = append(sizes, 0)
+		label := len(sizes) - 1
+		stack := []string{id}
+		component[id] = label
+		for len(stack) > 0 {
+			next := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			sizes[label]++
+			for opp := range opponents[next] {
+				if component[opp] == 0 {
+					component[opp] = label
+					stack = append(stack, opp)
+				}
+			}
+		}
+	}
+	largest := 0
+	for label, size := range sizes {
+		if size > sizes[largest] {
+			largest = label
+		}
+	}
+
+	var teams []UnprovenTeam
+	for i, team := range elo.GetRankings() {
+		std := team.Dist.Std()
+		if std <= threshold {
+			continue
+		}
+		reach := make(map[string]bool)
+		for opp := range opponents[team.TeamID] {
+			reach[opp] = true
+			for second := range opponents[opp] {
+				reach[second] = true
+			}
+		}
+		delete(reach, team.TeamID)
+
+		low, high := team.Dist.CredibleInterval(0.9)
+		teams = append(teams, UnprovenTeam{
+			Rank:          i + 1,
+			TeamID:        team.TeamID,
+			TeamName:      team.TeamName,
+			Conference:    team.Conference,
+			Games:         games[team.TeamID],
+			Opponents:     len(opponents[team.TeamID]),
+			Reach:         len(reach),
+			MainComponent: largest != 0 && component[team.TeamID] == largest,
+			MeanELO:       team.Dist.Mean(),
+			StdDev:        std,
+			CI90Low:       low,
+			CI90High:      high,
+		})
+	}
+	sort.SliceStable(teams, func(i, j int) bool { return teams[i].StdDev > teams[j].StdDev })
+	return teams
+}
+
+// formatUnprovenTable renders the unproven teams as a text table
+func formatUnprovenTable(teams []UnprovenTeam, total int, threshold float64, season int) string {
+	var sb strings.Builder
+	width := 100
+
+	sb.WriteString(fmt.Sprintf("Unproven Teams (%d-%d Season): %d with a standard deviation above %.1f\n", season-1, season, total, threshold))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-4s %-30s %6s %5s %6s %8s %8s %12s  %s\n", "Rank", "Team", "Games", "Opps", "Reach", "Mean", "StdDev", "90% CI", "Note"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, t := range teams {
+		note := ""
+		if !t.MainComponent {
+			note = "cut off from main schedule"
+		}
+		line := fmt.Sprintf("%-4d %-30s %6d %5d %6d %8.1f %8.1f %12s  %s",
+			t.Rank, output.Truncate(t.TeamName, 30), t.Games, t.Opponents, t.Reach, t.MeanELO, t.StdDev,
+			fmt.Sprintf("%.0f-%.0f", t.CI90Low, t.CI90High), note)
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString("\nOpps counts distinct opponents; Reach counts teams within two games (opponents and their opponents).\n")
+	return sb.String()
+}
+
+// formatUnprovenCSV renders the unproven teams as CSV
+func formatUnprovenCSV(teams []UnprovenTeam) string {
+	var sb strings.Builder
+	sb.WriteString("rank,team_id,team_name,conference,games,opponents,two_step_reach,main_component,mean_elo,std_dev,ci90_low,ci90_high\n")
+	for _, t := range teams {
+		sb.WriteString(fmt.Sprintf("%d,%s,\"%s\",\"%s\",%d,%d,%d,%t,%.1f,%.1f,%.1f,%.1f\n",
+			t.Rank, t.TeamID, t.TeamName, t.Conference, t.Games, t.Opponents, t.Reach, t.MainComponent,
+			t.MeanELO, t.StdDev, t.CI90Low, t.CI90High))
+	}
+	return sb.String()
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/cache"
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// updateCommand implements the update command: apply newly completed games to
+// a saved state, fetching only the days since the state's last game (at least
+// yesterday and today), applying games not already in the game log, and
+// saving the state back
+func updateCommand(fs *flag.FlagSet) func() {
+	statePath := fs.String("state", "", "Saved state file to update (required)")
+	cacheOpts := registerCacheFlags(fs)
+	clientOpts := registerClientFlags(fs)
+	filter := registerFilterFlags(fs)
+	timeout := fs.Duration("timeout", 0, "Abort fetching and processing after this long (e.g. 10m; 0 = no limit)")
+	webhooks := registerWebhookFlags(fs)
+	return func() {
+		if *statePath == "" {
+			fmt.Fprintln(os.Stderr, "Usage: ncaa-bayes-elo update -state <file>")
+			os.Exit(1)
+		}
+		if err := filter.check(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*timeout)
+		defer cancel()
+
+		elo, state, err := model.LoadState(*statePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading state: %v\n", err)
+			os.Exit(1)
+		}
+		if state.Source == "" || state.Season == 0 {
+			fmt.Fprintln(os.Stderr, "Error: state file does not record its source and season")
+			os.Exit(1)
+		}
+
+		today, err := clientOpts.today()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		dates := updateDates(elo, state.Season, today)
+		if len(dates) == 0 {
+			fmt.Printf("Season %d has no dates left to update\n", state.Season)
+			return
+		}
+		fmt.Printf("Updating %s %d-%d state from %s to %s\n", state.Source, state.Season-1, state.Season,
+			dates[0].Format("2006-01-02"), dates[len(dates)-1].Format("2006-01-02"))
+
+		before, ranks := len(elo.GameLog), elo.Ranks()
+		failed, err := applyUpdate(ctx, elo, state.Source, state.Season, dates, cacheOpts, clientOpts, filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := elo.SaveState(*statePath, state.Source, state.Season); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving state: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("State saved to %s\n", *statePath)
+
+		webhooks.send(ctx, webhooks.alerts(elo, before, ranks))
+
+		if len(failed) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %d dates could not be fetched and will be retried next update\n", len(failed))
+			os.Exit(1)
+		}
+	}
+}
+
+// applyUpdate fetches the given dates and processes the completed games the
+// engine hasn't seen that pass the filter flags, returning any dates that
+// could not be fetched
+func applyUpdate(ctx context.Context, elo *model.BayesianELO, source string, season int, dates []time.Time, cacheOpts *cacheFlags, clientOpts *clientFlags, filter *filterFlags) ([]time.Time, error) {
+	clientConfig, err := clientOpts.config(source, cacheOpts.cacheDir())
+	if err != nil {
+		return nil, err
+	}
+	filters, err := filter.fetched(ctx, source, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := openGameStore(ctx, cacheOpts)
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := store.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	newGames, failed, err := fetchNewGames(ctx, elo, store, source, season, dates, cacheOpts, clientConfig, filters)
+	if err != nil {
+		return nil, fmt.Errorf("fetching games: %w", err)
+	}
+
+	before := len(elo.GameLog)
+	start := time.Now()
+	if err := processGames(ctx, elo, newGames, nil); err != nil {
+		return nil, fmt.Errorf("processing games: %w", err)
+	}
+	recordRun(elo, len(elo.GameLog)-before, start)
+	assignConferences(ctx, elo, source, clientConfig)
+	fmt.Printf("Applied %d new games (%d teams rated)\n", len(elo.GameLog)-before, len(elo.Teams))
+	return failed, nil
+}
+
+// fetchNewGames loads the given dates and returns the completed games not yet
+// in the engine's game log, run through the filters, along with any dates
+// that could not be fetched
+func fetchNewGames(ctx context.Context, elo *model.BayesianELO, store cache.GameStore, source string, season int, dates []time.Time, cacheOpts *cacheFlags, clientConfig sources.Config, filters []gameFilter) ([]model.Game, []time.Time, error) {
+	games, failed, err := loadDates(ctx, store, source, season, dates, cacheOpts, clientConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Only apply completed games the state hasn't seen, by ID or by date and
+	// teams
+	processed := elo.ProcessedGames()
+	for _, g := range elo.GameLog {
+		processed[matchupKey(g.Date, g.WinnerID, g.LoserID)] = true
+	}
+	var newGames []model.Game
+	for _, g := range games {
+		if g.Completed && !processed[g.Key()] && !processed[matchupKey(g.Date.Format("2006-01-02"), g.HomeTeamID, g.AwayTeamID)] {
+			newGames = append(newGames, g)
+		}
+	}
+	return ratableGames(filterGames(dedupeGames(newGames), filters)), failed, nil
+}
+
+// updateDates returns the season dates an update must fetch: from the date of
+// the last processed game (or yesterday, if earlier) through today, the
+// current game day
+func updateDates(elo *model.BayesianELO, season int, today time.Time) []time.Time {
+	all := sources.SeasonDates(season)
+	if len(all) == 0 {
+		return nil
+	}
+
+	// The last processed date is refetched since it may have had games
+	// still in progress at the previous update
+	lastDate := elo.LastGameDate()
+
+	start := today.AddDate(0, 0, -1)
+	if d, err := time.Parse("2006-01-02", lastDate); err == nil && d.Before(start) {
+		start = d
+	}
+
+	var dates []time.Time
+	for _, d := range all {
+		if !d.Before(start) {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+)
+
+// WebhookAlert is the JSON payload POSTed to each webhook URL
+type WebhookAlert struct {
+	Event    string         `json:"event"` // "entered_top", "left_top", "moved", or "upset"
+	Time     time.Time      `json:"time"`
+	Message  string         `json:"message"`
+	Team     *output.Team   `json:"team,omitempty"`
+	PrevRank int            `json:"prev_rank,omitempty"` // 0 if the team was unrated
+	Game     *GameLogOutput `json:"game,omitempty"`
+}
+
+// urlList collects repeated URL flags
+type urlList []string
+
+func (u *urlList) String() string {
+	return strings.Join(*u, ",")
+}
+
+func (u *urlList) Set(value string) error {
+	*u = append(*u, value)
+	return nil
+}
+
+// webhookFlags configures alerts POSTed when the rankings change
+type webhookFlags struct {
+	urls      urlList
+	top       *int
+	move      *int
+	upsetProb *float64
+}
+
+// registerWebhookFlags adds webhook alert flags to a flag set
+func registerWebhookFlags(fs *flag.FlagSet) *webhookFlags {
+	f := &webhookFlags{
+		top:       fs.Int("webhook-top", 25, "Alert when a team enters or leaves the top N"),
+		move:      fs.Int("webhook-move", 5, "Alert when a team stays in the top N but moves more than this many spots (0 = off)"),
+		upsetProb: fs.Float64("webhook-upset", 0.10, "Alert when a winner's pre-game win probability was below this (0 = off)"),
+	}
+	fs.Var(&f.urls, "webhook", "URL to POST ranking alerts to as JSON (repeatable)")
+	return f
+}
+
+// enabled reports whether any webhook URLs are configured
+func (f *webhookFlags) enabled() bool {
+	return f != nil && len(f.urls) > 0
+}
+
+// alerts compares the rankings against the ranks before the games since the
+// game log had n entries were applied, and flags any upsets among those games
+func (f *webhookFlags) alerts(elo *model.BayesianELO, n int, before map[string]int) []WebhookAlert {
+	if !f.enabled() || n >= len(elo.GameLog) {
+		return nil
+	}
+	now := time.Now()
+	top := *f.top
+
+	var alerts []WebhookAlert
+	splits := output.Splits(elo)
+	for i, team := range elo.GetRankings() {
+		rank, prev := i+1, before[team.TeamID]
+		wasTop, isTop := prev > 0 && prev <= top, rank <= top
+
+		t := output.NewTeam(rank, team)
+		t.SetRecords(splits[team.TeamID])
+		alert := WebhookAlert{Time: now, Team: &t, PrevRank: prev}
+		switch {
+		case isTop && !wasTop:
+			alert.Event = "entered_top"
+			alert.Message = fmt.Sprintf("%s entered the top %d at #%d", team.TeamName, top, rank)
+		case wasTop && !isTop:
+			alert.Event = "left_top"
+			alert.Message = fmt.Sprintf("%s fell out of the top %d to #%d", team.TeamName, top, rank)
+		case isTop && *f.move > 0 && abs(rank-prev) > *f.move:
+			alert.Event = "moved"
+			alert.Message = fmt.Sprintf("%s moved from #%d to #%d", team.TeamName, prev, rank)
+		default:
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+
+	for _, g := range gameLogOutputs(elo.GameLog[n:]) {
+		if g.WinProb < *f.upsetProb {
+			alerts = append(alerts, WebhookAlert{
+				Event:   "upset",
+				Time:    now,
+				Message: fmt.Sprintf("Upset: %s (%.1f%%) beat %s", g.WinnerName, g.WinProb*100, g.LoserName),
+				Game:    &g,
+			})
+		}
+	}
+	return alerts
+}
+
+// send POSTs each alert to every webhook URL, warning about failed deliveries
+func (f *webhookFlags) send(ctx context.Context, alerts []WebhookAlert) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, alert := range alerts {
+		body, err := json.Marshal(alert)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not encode %s alert: %v\n", alert.Event, err)
+			continue
+		}
+		for _, url := range f.urls {
+			if err := postJSON(ctx, client, url, body); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: webhook %s: %v\n", url, err)
+			}
+		}
+	}
+}
+
+// postJSON POSTs a JSON body, failing on a non-2xx response
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+	return nil
+}
+
+// abs returns the absolute value of an int
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
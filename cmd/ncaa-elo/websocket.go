@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+
+	"github.com/gorilla/websocket"
+)
+
+// Subscribers more than this many events behind are disconnected
+const eventBuffer = 64
+
+// RatingEvent is pushed to WebSocket subscribers: "game_final" when a result
+// is applied, then "ratings" with every team whose rating it changed
+type RatingEvent struct {
+	Type  string         `json:"type"`
+	Time  time.Time      `json:"time"`
+	Game  *GameLogOutput `json:"game,omitempty"`
+	Teams []RatingChange `json:"teams,omitempty"`
+}
+
+// RatingChange is a team's new rating and how much its mean moved
+type RatingChange struct {
+	output.Team
+	Change float64 `json:"change"`
+}
+
+// ratingMeans snapshots every team's mean rating, to diff after processing
+func ratingMeans(elo *model.BayesianELO) map[string]float64 {
+	means := make(map[string]float64, len(elo.Teams))
+	for id, team := range elo.Teams {
+		means[id] = team.Dist.Mean()
+	}
+	return means
+}
+
+// ratingEvents describes the games applied since the game log had n entries
+// and the rating changes relative to the before snapshot
+func ratingEvents(elo *model.BayesianELO, n int, before map[string]float64) []RatingEvent {
+	if n >= len(elo.GameLog) {
+		return nil
+	}
+	now := time.Now()
+
+	var events []RatingEvent
+	for _, g := range gameLogOutputs(elo.GameLog[n:]) {
+		events = append(events, RatingEvent{Type: "game_final", Time: now, Game: &g})
+	}
+
+	ratings := RatingEvent{Type: "ratings", Time: now}
+	splits := output.Splits(elo)
+	for i, team := range elo.GetRankings() {
+		t := output.NewTeam(i+1, team)
+		t.SetRecords(splits[team.TeamID])
+		prev, seen := before[team.TeamID]
+		if !seen {
+			// A team's first game has no previous rating to change from
+			ratings.Teams = append(ratings.Teams, RatingChange{Team: t})
+			continue
+		}
+		if t.MeanELO != prev {
+			ratings.Teams = append(ratings.Teams, RatingChange{Team: t, Change: t.MeanELO - prev})
+		}
+	}
+	return append(events, ratings)
+}
+
+// eventHub fans rating events out to WebSocket subscribers
+type eventHub struct {
+	mu   sync.Mutex
+	subs map[chan []byte]struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subs: make(map[chan []byte]struct{})}
+}
+
+// publish sends events to every subscriber without blocking. A subscriber
+// whose buffer is full is dropped, closing its connection.
+func (h *eventHub) publish(events ...RatingEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not encode %s event: %v\n", event.Type, err)
+			continue
+		}
+		for sub := range h.subs {
+			select {
+			case sub <- data:
+			default:
+				delete(h.subs, sub)
+				close(sub)
+			}
+		}
+	}
+}
+
+// subscribe registers a subscriber, returning its channel and a function to
+// unsubscribe
+func (h *eventHub) subscribe() (chan []byte, func()) {
+	sub := make(chan []byte, eventBuffer)
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[sub]; ok {
+			delete(h.subs, sub)
+			close(sub)
+		}
+	}
+}
+
+var upgrader = websocket.Upgrader{
+	// Dashboards are typically served from another origin
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// ServeHTTP upgrades the request to a WebSocket and streams events to it
+// until the client disconnects
+func (h *eventHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // The upgrader has already replied
+	}
+	defer conn.Close()
+
+	sub, unsubscribe := h.subscribe()
+	defer unsubscribe()
+
+	// Clients only send control frames; reading processes them and notices
+	// when the client goes away
+	done := make(chan struct{})
+	conn.SetReadDeadline(time.Now().Add(time.Minute))
+	conn.SetPongHandler(func(string) error { return conn.SetReadDeadline(time.Now().Add(time.Minute)) })
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(30 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-r.Context().Done():
+			return
+		case data, ok := <-sub:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too far behind"), time.Now().Add(time.Second))
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// startEventServer serves the hub at /ws on addr in the background, if set
+func startEventServer(addr string, hub *eventHub) {
+	if addr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /ws", hub)
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Warning: WebSocket server stopped: %v\n", err)
+		}
+	}()
+	fmt.Printf("Serving rating events on ws://%s/ws\n", addr)
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// hypothetical is a made-up result to rate as if it had been played
+type hypothetical struct {
+	text          string
+	winner, loser string // Team IDs or names
+	site          string // "home" (the winner hosted), "away", or "neutral"
+	date          string // As written; empty for the day after the last game
+}
+
+// hypotheticalPattern reads results like "Duke beats Houston on a neutral
+// court on 3/30" or "Kansas over Baylor at home on 2025-02-01"
+var hypotheticalPattern = regexp.MustCompile(`(?i)^\s*(.+?)\s+(?:beats|beat|defeats|def\.?|over)\s+(.+?)` +
+	`(?:\s+(at home|on the road|away|on a neutral court|at a neutral site|neutral))?` +
+	`(?:\s+on\s+(\d{4}-\d{1,2}-\d{1,2}|\d{1,2}/\d{1,2}(?:/\d{4})?))?\s*$`)
+
+// parseHypothetical reads a result in the form "<winner> beats <loser>
+// [at home|on the road|on a neutral court] [on <date>]". Games are at a
+// neutral site unless said otherwise.
+func parseHypothetical(text string) (hypothetical, error) {
+	m := hypotheticalPattern.FindStringSubmatch(text)
+	if m == nil {
+		return hypothetical{}, fmt.Errorf("can't read %q (expected e.g. \"Duke beats Houston on a neutral court on 3/30\")", text)
+	}
+	h := hypothetical{text: strings.TrimSpace(text), winner: m[1], loser: m[2], site: "neutral", date: m[4]}
+	switch strings.ToLower(m[3]) {
+	case "at home":
+		h.site = "home"
+	case "on the road", "away":
+		h.site = "away"
+	}
+	return h, nil
+}
+
+// gameDate resolves the hypothetical's date. Dates without a year fall in
+// the season: July through December in its first year, the rest in its second.
+func (h hypothetical) gameDate(season int, last time.Time) (time.Time, error) {
+	if h.date == "" {
+		return last.AddDate(0, 0, 1), nil
+	}
+	if date, err := time.Parse("2006-1-2", h.date); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse("1/2/2006", h.date); err == nil {
+		return date, nil
+	}
+	parts := strings.Split(h.date, "/")
+	month, _ := strconv.Atoi(parts[0])
+	day, _ := strconv.Atoi(parts[1])
+	year := season
+	if month >= 7 {
+		year = season - 1
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Month() != time.Month(month) || date.Day() != day {
+		return time.Time{}, fmt.Errorf("invalid date %q", h.date)
+	}
+	return date, nil
+}
+
+// game builds the hypothetical's game, matching its teams among teams
+func (h hypothetical) game(id string, teams map[string]*model.TeamRating, season int, last time.Time) (model.Game, error) {
+	var sides [2]*model.TeamRating
+	for i, name := range []string{h.winner, h.loser} {
+		matches := matchTeams(teams, name)
+		switch len(matches) {
+		case 0:
+			return model.Game{}, fmt.Errorf("no team matches %q", name)
+		case 1:
+			sides[i] = matches[0]
+		default:
+			return model.Game{}, fmt.Errorf("%q matches several teams", name)
+		}
+	}
+	winner, loser := sides[0], sides[1]
+	if winner == loser {
+		return model.Game{}, fmt.Errorf("%s can't play itself", winner.TeamName)
+	}
+	date, err := h.gameDate(season, last)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	g := model.Game{
+		ID:          id,
+		Date:        date,
+		HomeTeamID:  winner.TeamID,
+		HomeTeam:    winner.TeamName,
+		AwayTeamID:  loser.TeamID,
+		AwayTeam:    loser.TeamName,
+		HomeScore:   1,
+		NeutralSite: h.site == "neutral",
+		Completed:   true,
+		WinnerID:    winner.TeamID,
+		State:       "post",
+	}
+	if h.site == "away" {
+		g.HomeTeamID, g.AwayTeamID = g.AwayTeamID, g.HomeTeamID
+		g.HomeTeam, g.AwayTeam = g.AwayTeam, g.HomeTeam
+		g.HomeScore, g.AwayScore = 0, 1
+	}
+	return g, nil
+}
+
+// whatIfList collects repeated -what-if flags, one result each
+type whatIfList []string
+
+func (w *whatIfList) String() string {
+	return strings.Join(*w, "; ")
+}
+
+func (w *whatIfList) Set(value string) error {
+	*w = append(*w, value)
+	return nil
+}
+
+// readHypotheticals parses results from the -what-if flags and a file of
+// one result per line, where blank lines and lines starting with # are skipped
+func readHypotheticals(texts []string, path string) ([]hypothetical, error) {
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+				texts = append(texts, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var hyps []hypothetical
+	for _, text := range texts {
+		h, err := parseHypothetical(text)
+		if err != nil {
+			return nil, err
+		}
+		hyps = append(hyps, h)
+	}
+	return hyps, nil
+}
+
+// matchupKey identifies a game by date and its two teams, in either order
+func matchupKey(date, a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return date + ":" + a + ":" + b
+}
+
+// whatIfFilter adds the hypothetical results to the games, replacing any real
+// game between the same teams on the same day. Teams are matched among those
+// in the games; results that can't be placed are reported and left out.
+func whatIfFilter(hyps []hypothetical, season int) gameFilter {
+	return func(games []model.Game) []model.Game {
+		teams := make(map[string]*model.TeamRating)
+		var last time.Time
+		for _, g := range games {
+			teams[g.HomeTeamID] = &model.TeamRating{TeamID: g.HomeTeamID, TeamName: g.HomeTeam}
+			teams[g.AwayTeamID] = &model.TeamRating{TeamID: g.AwayTeamID, TeamName: g.AwayTeam}
+			if g.Date.After(last) {
+				last = g.Date
+			}
+		}
+
+		var added []model.Game
+		replaced := make(map[string]bool)
+		for i, h := range hyps {
+			g, err := h.game(fmt.Sprintf("what-if-%d", i+1), teams, season, last)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: what-if %q: %v\n", h.text, err)
+				continue
+			}
+			added = append(added, g)
+			replaced[matchupKey(g.Date.Format("2006-01-02"), g.HomeTeamID, g.AwayTeamID)] = true
+		}
+
+		var kept []model.Game
+		for _, g := range games {
+			if !replaced[matchupKey(g.Date.Format("2006-01-02"), g.HomeTeamID, g.AwayTeamID)] {
+				kept = append(kept, g)
+			}
+		}
+		if n := len(games) - len(kept); n > 0 {
+			fmt.Fprintf(os.Stderr, "What-if results replace real games: %d\n", n)
+		}
+		if len(added) > 0 {
+			fmt.Fprintf(os.Stderr, "What-if results added: %d\n", len(added))
+		}
+		return append(kept, added...)
+	}
+}
+
+// WhatIfReport compares the rankings with and without hypothetical results
+type WhatIfReport struct {
+	Results []string `json:"results"`
+	Teams   []Mover  `json:"teams"` // From the actual rankings to the what-if rankings
+}
+
+// whatIfCommand implements the whatif command: rerate the season with made-up
+// results and show how the rankings would change
+func whatIfCommand(fs *flag.FlagSet) func() {
+	engine := registerEngineFlags(fs)
+	topN := fs.Int("top", 25, "Number of teams to display, by what-if rank")
+	showAll := fs.Bool("all", false, "Show all teams, not just top N")
+	outputFormat := fs.String("format", "table", "Output format: 'table' or 'json'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ncaa-bayes-elo whatif [flags] <result>...")
+		fmt.Fprintln(fs.Output(), "  e.g. whatif \"Duke beats Houston on a neutral court on 3/30\" \"UConn over Purdue\"")
+		fs.PrintDefaults()
+	}
+	return func() {
+		if fs.NArg() == 0 {
+			fs.Usage()
+			os.Exit(1)
+		}
+
+		hyps, err := readHypotheticals(fs.Args(), "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(*engine.timeout)
+		defer cancel()
+		elo := engine.mustLoad(ctx)
+		for _, h := range hyps {
+			for _, name := range []string{h.winner, h.loser} {
+				if _, err := findTeam(elo, name); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %q: %v\n", h.text, err)
+					os.Exit(1)
+				}
+			}
+		}
+
+		scenario, err := replay(ctx, elo, sources.DateRange{}, whatIfFilter(hyps, *engine.season))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		report := whatIfReport(elo, scenario, hyps)
+		if !*showAll {
+			report.Teams = report.Teams[:min(*topN, len(report.Teams))]
+		}
+		switch output.Format(*outputFormat) {
+		case output.FormatJSON:
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Print(formatWhatIf(report))
+		}
+	}
+}
+
+// whatIfReport lists every team in the what-if rankings by its new rank,
+// with its actual rank and rating
+func whatIfReport(actual, scenario *model.BayesianELO, hyps []hypothetical) WhatIfReport {
+	report := WhatIfReport{Teams: []Mover{}}
+	for _, h := range hyps {
+		report.Results = append(report.Results, h.text)
+	}
+
+	ranks := actual.Ranks()
+	for i, team := range scenario.GetRankings() {
+		m := Mover{
+			TeamID:   team.TeamID,
+			TeamName: team.TeamName,
+			FromRank: ranks[team.TeamID],
+			ToRank:   i + 1,
+			FromELO:  actual.PriorMean(),
+			ToELO:    team.Dist.Mean(),
+		}
+		if before, ok := actual.Teams[team.TeamID]; ok {
+			m.FromELO = before.Dist.Mean()
+			m.RankChange = m.FromRank - m.ToRank
+		}
+		m.Change = m.ToELO - m.FromELO
+		report.Teams = append(report.Teams, m)
+	}
+	sort.SliceStable(report.Teams, func(i, j int) bool { return report.Teams[i].ToRank < report.Teams[j].ToRank })
+	return report
+}
+
+// formatWhatIf renders a what-if report as a text table
+func formatWhatIf(report WhatIfReport) string {
+	var sb strings.Builder
+	width := 72
+
+	sb.WriteString("What-If Rankings\n")
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	for _, r := range report.Results {
+		sb.WriteString("  " + r + "\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("%-4s %-30s %6s %8s %8s %8s\n", "Rank", "Team", "Was", "Spots", "ELO", "Change"))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for _, m := range report.Teams {
+		was, spots := "-", "-"
+		if m.FromRank > 0 {
+			was, spots = fmt.Sprint(m.FromRank), fmt.Sprintf("%+d", m.RankChange)
+		}
+		sb.WriteString(fmt.Sprintf("%-4d %-30s %6s %8s %8.1f %s\n",
+			m.ToRank, output.Truncate(m.TeamName, 30), was, spots, m.ToELO, output.PadLeft(output.FormatTrend(m.Change), 8)))
+	}
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	return sb.String()
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	model "github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/output"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxSheet is one worksheet: a header row and the data rows beneath it
+type xlsxSheet struct {
+	name    string
+	columns []string
+	kinds   []reflect.Kind
+	rows    [][]any
+}
+
+// sheetFromRows builds a worksheet from a slice of flat structs, with one
+// column per field named by its json tag
+func sheetFromRows[T any](name string, rows []T) xlsxSheet {
+	rowType := reflect.TypeOf((*T)(nil)).Elem()
+	sheet := xlsxSheet{name: name}
+
+	for i := 0; i < rowType.NumField(); i++ {
+		field := rowType.Field(i)
+		column, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if column == "" {
+			column = field.Name
+		}
+		sheet.columns = append(sheet.columns, column)
+		sheet.kinds = append(sheet.kinds, field.Type.Kind())
+	}
+
+	for _, row := range rows {
+		v := reflect.ValueOf(row)
+		values := make([]any, v.NumField())
+		for i := range values {
+			values[i] = v.Field(i).Interface()
+		}
+		sheet.rows = append(sheet.rows, values)
+	}
+
+	return sheet
+}
+
+// writeXLSX writes sheets to an Excel workbook with a bold frozen header row,
+// filters, and number formats (ratings to one decimal, probabilities as percentages)
+func writeXLSX(path string, sheets ...xlsxSheet) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"1F4E78"}},
+		Alignment: &excelize.Alignment{Horizontal: "center"},
+	})
+	if err != nil {
+		return err
+	}
+	decimalFormat := "0.0"
+	decimalStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &decimalFormat})
+	if err != nil {
+		return err
+	}
+	percentFormat := "0.0%"
+	percentStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &percentFormat})
+	if err != nil {
+		return err
+	}
+
+	for i, sheet := range sheets {
+		// New workbooks start with one sheet, which becomes the first
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", sheet.name); err != nil {
+				return err
+			}
+		} else if _, err := f.NewSheet(sheet.name); err != nil {
+			return err
+		}
+
+		header := make([]any, len(sheet.columns))
+		for j, column := range sheet.columns {
+			header[j] = column
+		}
+		if err := f.SetSheetRow(sheet.name, "A1", &header); err != nil {
+			return err
+		}
+		for j, row := range sheet.rows {
+			cell, _ := excelize.CoordinatesToCellName(1, j+2)
+			if err := f.SetSheetRow(sheet.name, cell, &row); err != nil {
+				return err
+			}
+		}
+
+		for j, column := range sheet.columns {
+			name, _ := excelize.ColumnNumberToName(j + 1)
+			width := float64(len(column) + 4)
+			if sheet.kinds[j] == reflect.String && width < 24 {
+				width = 24
+			}
+			if err := f.SetColWidth(sheet.name, name, name, width); err != nil {
+				return err
+			}
+			if sheet.kinds[j] == reflect.Float64 {
+				style := decimalStyle
+				if strings.Contains(column, "prob") {
+					style = percentStyle
+				}
+				if err := f.SetColStyle(sheet.name, name, style); err != nil {
+					return err
+				}
+			}
+		}
+
+		last, _ := excelize.ColumnNumberToName(len(sheet.columns))
+		if err := f.SetCellStyle(sheet.name, "A1", last+"1", headerStyle); err != nil {
+			return err
+		}
+		if err := f.SetPanes(sheet.name, &excelize.Panes{
+			Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft",
+		}); err != nil {
+			return err
+		}
+		if len(sheet.rows) > 0 {
+			if err := f.AutoFilter(sheet.name, fmt.Sprintf("A1:%s%d", last, len(sheet.rows)+1), nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := f.SaveAs(path); err != nil {
+		return fmt.Errorf("failed to write workbook: %w", err)
+	}
+	return nil
+}
+
+// TeamDetailOutput summarizes a team's season for the workbook's detail sheet
+type TeamDetailOutput struct {
+	TeamID     string  `json:"team_id"`
+	TeamName   string  `json:"team_name"`
+	Conference string  `json:"conference"`
+	Games      int     `json:"games"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	MeanELO    float64 `json:"mean_elo"`
+	StdDev     float64 `json:"std_dev"`
+	Pct5       float64 `json:"percentile_5"`
+	Pct95      float64 `json:"percentile_95"`
+	SeasonHigh float64 `json:"season_high"`
+	SeasonLow  float64 `json:"season_low"`
+	LastPlayed string  `json:"last_played"`
+}
+
+// writeRankingsWorkbook writes the rankings, per-team detail, and game log
+// as sheets of one workbook
+func writeRankingsWorkbook(path string, elo *model.BayesianELO, teams []output.Team) error {
+	return writeXLSX(path, rankingsSheets(elo, teams)...)
+}
+
+// rankingsSheets builds the Rankings, Team Detail, and Game Log sheets
+func rankingsSheets(elo *model.BayesianELO, teams []output.Team) []xlsxSheet {
+	wins := make(map[string]int)
+	losses := make(map[string]int)
+	for _, g := range elo.GameLog {
+		wins[g.WinnerID]++
+		losses[g.LoserID]++
+	}
+
+	var details []TeamDetailOutput
+	for _, t := range teams {
+		team := elo.Teams[t.TeamID]
+		detail := TeamDetailOutput{
+			TeamID:     t.TeamID,
+			TeamName:   t.TeamName,
+			Conference: t.Conference,
+			Games:      wins[t.TeamID] + losses[t.TeamID],
+			Wins:       wins[t.TeamID],
+			Losses:     losses[t.TeamID],
+			MeanELO:    t.MeanELO,
+			StdDev:     t.StdDev,
+			Pct5:       t.Pct5,
+			Pct95:      t.Pct95,
+			SeasonHigh: team.Dist.Mean(),
+			SeasonLow:  team.Dist.Mean(),
+		}
+		for _, p := range elo.History[t.TeamID] {
+			if p.Mean > detail.SeasonHigh {
+				detail.SeasonHigh = p.Mean
+			}
+			if p.Mean < detail.SeasonLow {
+				detail.SeasonLow = p.Mean
+			}
+			detail.LastPlayed = p.Date
+		}
+		details = append(details, detail)
+	}
+
+	return []xlsxSheet{
+		sheetFromRows("Rankings", teams),
+		sheetFromRows("Team Detail", details),
+		sheetFromRows("Game Log", gameLogOutputs(elo.GameLog)),
+	}
+}
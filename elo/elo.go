@@ -0,0 +1,949 @@
+// Package elo implements the Bayesian ELO rating engine: each team's rating
+// is a discrete probability distribution updated game by game with Bayes'
+// rule, so rankings carry their own uncertainty.
+package elo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tuned parameters from cross-validation, the defaults for a new engine
+const (
+	OptimalKFactor = 0.90   // Tuned K factor for likelihood function
+	ELOMin         = 0.0    // Minimum ELO value
+	ELOMax         = 3000.0 // Maximum ELO value
+	ELOStep        = 5.0    // Step size for discretization
+	PriorMean      = 1500.0 // Prior distribution mean
+	PriorStdDev    = 300.0  // Prior distribution standard deviation
+)
+
+// maxLikelihoodTables bounds the likelihood tables an engine keeps, one per
+// home edge in use; past it the cache starts over
+const maxLikelihoodTables = 16
+
+// HomeCourtELO is the rating edge given a home team in predictions, about a
+// 63% chance for evenly matched teams. Ratings are fitted without it.
+const HomeCourtELO = 100.0
+
+// Distribution represents a discrete probability distribution over ELO values.
+// Distributions on the same grid share its Values, so never write to them.
+// Every team in an engine is on the engine's grid.
+type Distribution struct {
+	Values []float64 // ELO values (quantiles)
+	Probs  []float64 // Probabilities
+}
+
+// NewNormalPrior creates the default prior: a normal distribution centered at
+// 1500, truncated to the default grid. Its Values are shared.
+func NewNormalPrior() *Distribution {
+	return defaultPrior().withOwnProbs()
+}
+
+// defaultPrior is the default prior, built once and never modified
+var defaultPrior = sync.OnceValue(func() *Distribution {
+	return normalPrior(ELOMin, ELOMax, ELOStep, PriorMean, PriorStdDev)
+})
+
+// normalPrior creates a normal distribution over a grid, truncated at its ends
+func normalPrior(low, high, step, mean, stdDev float64) *Distribution {
+	n := int((high - low) / step)
+	d := &Distribution{
+		Values: make([]float64, n),
+		Probs:  make([]float64, n),
+	}
+
+	// Calculate normal distribution probabilities (truncated at low and high)
+	for i := 0; i < n; i++ {
+		d.Values[i] = low + float64(i)*step
+		// Normal PDF: exp(-0.5 * ((x - mean) / std)^2)
+		z := (d.Values[i] - mean) / stdDev
+		d.Probs[i] = math.Exp(-0.5 * z * z)
+	}
+
+	// Normalize so probabilities sum to 1
+	d.Normalize()
+
+	return d
+}
+
+// Mean returns the expected value of the distribution
+func (d *Distribution) Mean() float64 {
+	var sum float64
+	for i, v := range d.Values {
+		sum += v * d.Probs[i]
+	}
+	return sum
+}
+
+// Std returns the standard deviation of the distribution
+func (d *Distribution) Std() float64 {
+	mean := d.Mean()
+	var variance float64
+	for i, v := range d.Values {
+		diff := v - mean
+		variance += diff * diff * d.Probs[i]
+	}
+	return math.Sqrt(variance)
+}
+
+// Percentile returns the value at the given percentile (0-100), spreading
+// each grid point's probability evenly across its step so the result moves
+// smoothly between grid values rather than snapping to them
+func (d *Distribution) Percentile(p float64) float64 {
+	n := len(d.Values)
+	step := d.Step()
+	target := p / 100.0
+	var cumulative float64
+	for i, prob := range d.Probs {
+		if prob > 0 && cumulative+prob >= target {
+			value := d.Values[i] - step/2 + (target-cumulative)/prob*step
+			return math.Max(d.Values[0], math.Min(value, d.Values[n-1]))
+		}
+		cumulative += prob
+	}
+	return d.Values[n-1]
+}
+
+// Step returns the spacing between the distribution's grid values
+func (d *Distribution) Step() float64 {
+	if len(d.Values) < 2 {
+		return ELOStep
+	}
+	return d.Values[1] - d.Values[0]
+}
+
+// Mode returns the most probable value, the maximum a posteriori (MAP) estimate
+func (d *Distribution) Mode() float64 {
+	best := 0
+	for i, p := range d.Probs {
+		if p > d.Probs[best] {
+			best = i
+		}
+	}
+	return d.Values[best]
+}
+
+// Skewness returns the distribution's skewness: positive when it has a long
+// tail toward higher ratings, negative toward lower
+func (d *Distribution) Skewness() float64 {
+	mean, std := d.Mean(), d.Std()
+	if std == 0 {
+		return 0
+	}
+	var third float64
+	for i, v := range d.Values {
+		z := (v - mean) / std
+		third += z * z * z * d.Probs[i]
+	}
+	return third
+}
+
+// CredibleInterval returns the narrowest range of values holding at least
+// mass (0-1) of the probability, the highest density interval. Unlike the
+// percentiles either side of the median, it follows a skewed distribution's
+// bulk.
+func (d *Distribution) CredibleInterval(mass float64) (low, high float64) {
+	lo, hi := 0, len(d.Values)-1
+	var sum float64
+	start := 0
+	for end, p := range d.Probs {
+		sum += p
+		for start < end && sum-d.Probs[start] >= mass {
+			sum -= d.Probs[start]
+			start++
+		}
+		if sum >= mass && end-start < hi-lo {
+			lo, hi = start, end
+		}
+	}
+	return d.Values[lo], d.Values[hi]
+}
+
+// Normalize ensures probabilities sum to 1
+func (d *Distribution) Normalize() {
+	var sum float64
+	for _, p := range d.Probs {
+		sum += p
+	}
+	if sum > 0 {
+		for i := range d.Probs {
+			d.Probs[i] /= sum
+		}
+	}
+}
+
+// Diffuse widens the distribution as if the rating took a normal random walk
+// with the given standard deviation. Mass spread past the ends of the grid is
+// dropped, as it is for the truncated prior.
+func (d *Distribution) Diffuse(stdDev float64) {
+	step := d.Step()
+	if stdDev <= 0 || step <= 0 {
+		return
+	}
+	reach := int(math.Ceil(4 * stdDev / step))
+	kernel := make([]float64, 2*reach+1)
+	for k := range kernel {
+		z := float64(k-reach) * step / stdDev
+		kernel[k] = math.Exp(-0.5 * z * z)
+	}
+
+	spread := make([]float64, len(d.Probs))
+	for i, p := range d.Probs {
+		if p == 0 {
+			continue
+		}
+		for k, w := range kernel {
+			if j := i + k - reach; j >= 0 && j < len(spread) {
+				spread[j] += p * w
+			}
+		}
+	}
+	d.Probs = spread
+	d.Normalize()
+}
+
+// withOwnProbs copies the distribution's probabilities, sharing its grid
+func (d *Distribution) withOwnProbs() *Distribution {
+	probs := make([]float64, len(d.Probs))
+	copy(probs, d.Probs)
+	return &Distribution{Values: d.Values, Probs: probs}
+}
+
+// Clone creates a deep copy of the distribution
+func (d *Distribution) Clone() *Distribution {
+	clone := &Distribution{
+		Values: make([]float64, len(d.Values)),
+		Probs:  make([]float64, len(d.Probs)),
+	}
+	copy(clone.Values, d.Values)
+	copy(clone.Probs, d.Probs)
+	return clone
+}
+
+// TeamRating holds a team's ELO distribution
+type TeamRating struct {
+	TeamID     string
+	TeamName   string
+	Conference string // Empty when the data source doesn't provide conferences
+	Dist       *Distribution
+}
+
+// BayesianELO implements the Bayesian ELO rating system. Its methods are safe
+// to call while games are being processed: each day's games are applied under
+// a write lock, so readers see the ratings as of the end of a day. The
+// exported fields aren't guarded; read them directly only when no update can
+// run at the same time, or read them from a Snapshot.
+type BayesianELO struct {
+	Teams         map[string]*TeamRating
+	KFactor       float64
+	HomeAdvantage float64 // Rating edge given a home team in predictions
+	Dynamics      float64 // Standard deviation of each rating's drift per day (0 = fixed ratings)
+	GameLog       []GameResult
+	History       map[string][]RatingPoint // Per-team rating after each day it played
+	logMutex      sync.Mutex               // Protects GameLog during parallel processing
+	mu            sync.RWMutex             // Held for writing while games are applied
+
+	// Grid and prior new teams start from. The prior is a template built
+	// once per engine (once per process for the defaults) and never modified.
+	eloMin, eloMax, eloStep float64
+	priorMean, priorStdDev  float64
+	prior                   *Distribution
+
+	likelihoodMu sync.Mutex
+	likelihoods  map[likelihoodKey][]float64 // Win probabilities by grid steps between ratings, built as needed
+}
+
+// RatingPoint is a team's posterior rating at the end of a day
+type RatingPoint struct {
+	Date string  `json:"date"`
+	Mean float64 `json:"mean"`
+	Std  float64 `json:"std"`
+}
+
+// GameResult stores the result of processing a game
+type GameResult struct {
+	GameID        string  `json:"game_id"` // Game.Key of the processed game
+	Date          string  `json:"date"`
+	WinnerName    string  `json:"winner_name"`
+	WinnerID      string  `json:"winner_id"`
+	LoserName     string  `json:"loser_name"`
+	LoserID       string  `json:"loser_id"`
+	WinnerELO     float64 `json:"winner_elo"` // Pre-game mean
+	LoserELO      float64 `json:"loser_elo"`  // Pre-game mean
+	WinnerStd     float64 `json:"winner_std"` // Pre-game standard deviation
+	LoserStd      float64 `json:"loser_std"`  // Pre-game standard deviation
+	WinnerPostELO float64 `json:"winner_post_elo"`
+	LoserPostELO  float64 `json:"loser_post_elo"`
+	WinnerPostStd float64 `json:"winner_post_std"`
+	LoserPostStd  float64 `json:"loser_post_std"`
+	WinProb       float64 `json:"win_prob"`
+	HomeAdvantage string  `json:"home_advantage"` // "H", "A", or "N"
+	WinnerScore   int     `json:"winner_score,omitempty"`
+	LoserScore    int     `json:"loser_score,omitempty"`
+	Periods       int     `json:"periods,omitempty"` // Periods played (2 in regulation), when the source reports them
+}
+
+// NewBayesianELO creates a new Bayesian ELO system, by default with the
+// tuned parameters above
+func NewBayesianELO(opts ...Option) *BayesianELO {
+	b := &BayesianELO{
+		Teams:         make(map[string]*TeamRating),
+		KFactor:       OptimalKFactor,
+		HomeAdvantage: HomeCourtELO,
+		GameLog:       []GameResult{},
+		History:       make(map[string][]RatingPoint),
+		eloMin:        ELOMin,
+		eloMax:        ELOMax,
+		eloStep:       ELOStep,
+		priorMean:     PriorMean,
+		priorStdDev:   PriorStdDev,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.eloMin == ELOMin && b.eloMax == ELOMax && b.eloStep == ELOStep && b.priorMean == PriorMean && b.priorStdDev == PriorStdDev {
+		b.prior = defaultPrior()
+	} else {
+		b.prior = normalPrior(b.eloMin, b.eloMax, b.eloStep, b.priorMean, b.priorStdDev)
+	}
+	return b
+}
+
+// NewPrior returns a copy of the prior every new team starts from, sharing
+// its grid Values
+func (b *BayesianELO) NewPrior() *Distribution {
+	return b.prior.withOwnProbs()
+}
+
+// PriorMean returns the mean of the prior, the rating of a team with no games
+func (b *BayesianELO) PriorMean() float64 {
+	return b.priorMean
+}
+
+// getOrCreateTeam gets an existing team or creates a new one with normal prior
+func (b *BayesianELO) getOrCreateTeam(teamID, teamName string) *TeamRating {
+	if team, exists := b.Teams[teamID]; exists {
+		return team
+	}
+
+	team := &TeamRating{
+		TeamID:   teamID,
+		TeamName: teamName,
+		Dist:     b.NewPrior(),
+	}
+	b.Teams[teamID] = team
+	return team
+}
+
+// WinProbability calculates P(team1 wins) given ELO difference
+func (b *BayesianELO) WinProbability(diff float64) float64 {
+	return 1.0 / (1.0 + math.Pow(10, -diff*b.KFactor/400.0))
+}
+
+// likelihoodKey identifies a likelihood table: the home edge it includes and
+// the K factor it was built with
+type likelihoodKey struct {
+	edge, kFactor float64
+}
+
+// Likelihood returns the chance a team at each rating on the engine's grid
+// beats one at each other, given an edge for the first: table[i-j+n-1] for
+// the ith grid value against the jth, where n is the grid size. The chance
+// depends only on the i-j grid steps between the ratings, so a table is
+// computed once per edge and reused for every game; callers must not modify
+// it.
+func (b *BayesianELO) Likelihood(edge float64) []float64 {
+	key := likelihoodKey{edge: edge, kFactor: b.KFactor}
+
+	b.likelihoodMu.Lock()
+	defer b.likelihoodMu.Unlock()
+	if table, ok := b.likelihoods[key]; ok {
+		return table
+	}
+	n, step := len(b.prior.Values), b.prior.Step()
+	table := make([]float64, 2*n-1)
+	for k := range table {
+		table[k] = b.WinProbability(float64(k-n+1)*step + edge)
+	}
+	if b.likelihoods == nil || len(b.likelihoods) >= maxLikelihoodTables {
+		b.likelihoods = make(map[likelihoodKey][]float64)
+	}
+	b.likelihoods[key] = table
+	return table
+}
+
+// GameOutcome works out whether the home team won a completed game, or why
+// the game can't be rated: a tie, no score, or no winner among its teams. A
+// forfeit goes to its listed winner whatever the score; otherwise the score
+// decides and has to agree with the listed winner.
+func GameOutcome(game Game) (homeWon bool, problem string) {
+	if game.WinnerID == "" || (game.WinnerID != game.HomeTeamID && game.WinnerID != game.AwayTeamID) {
+		switch {
+		case game.WinnerID != "":
+			return false, fmt.Sprintf("winner %s is neither team", game.WinnerID)
+		case game.HomeScore == 0 && game.AwayScore == 0:
+			return false, "no score"
+		case game.HomeScore == game.AwayScore:
+			return false, fmt.Sprintf("tied %d-%d", game.AwayScore, game.HomeScore)
+		}
+		return false, "no winner listed"
+	}
+	homeWon = game.WinnerID == game.HomeTeamID
+	switch {
+	case game.Status == StatusForfeit:
+		return homeWon, ""
+	case game.HomeScore == 0 && game.AwayScore == 0:
+		return false, "no score"
+	case game.HomeScore == game.AwayScore:
+		return false, fmt.Sprintf("tied %d-%d", game.AwayScore, game.HomeScore)
+	case (game.HomeScore > game.AwayScore) != homeWon:
+		return false, fmt.Sprintf("score %d-%d disagrees with the listed winner", game.AwayScore, game.HomeScore)
+	}
+	return homeWon, ""
+}
+
+// ProcessGame updates team distributions based on a game result. Games
+// GameOutcome can't settle are skipped.
+func (b *BayesianELO) ProcessGame(game Game) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.processGameHistory(game)
+}
+
+// processGameHistory applies a game and records both teams' ratings for its
+// date, for a caller holding the write lock
+func (b *BayesianELO) processGameHistory(game Game) {
+	if winner, loser := b.processGame(game); winner != nil {
+		date := game.Date.Format("2006-01-02")
+		b.recordHistory(date, winner)
+		b.recordHistory(date, loser)
+	}
+}
+
+// processGame applies a game, returning its winner and loser, or nils if it
+// was skipped. The caller holds the write lock; games without shared teams
+// may run concurrently once both teams exist, since only the game log is
+// shared between them.
+func (b *BayesianELO) processGame(game Game) (winner, loser *TeamRating) {
+	homeWon, problem := GameOutcome(game)
+	if !game.Completed || problem != "" {
+		return nil, nil
+	}
+
+	var winnerID, winnerName, loserID, loserName string
+	var homeAdv string
+
+	if homeWon {
+		winnerID = game.HomeTeamID
+		winnerName = game.HomeTeam
+		loserID = game.AwayTeamID
+		loserName = game.AwayTeam
+		if game.NeutralSite {
+			homeAdv = "N"
+		} else {
+			homeAdv = "H" // Winner was home
+		}
+	} else {
+		winnerID = game.AwayTeamID
+		winnerName = game.AwayTeam
+		loserID = game.HomeTeamID
+		loserName = game.HomeTeam
+		if game.NeutralSite {
+			homeAdv = "N"
+		} else {
+			homeAdv = "A" // Winner was away
+		}
+	}
+
+	winner = b.getOrCreateTeam(winnerID, winnerName)
+	loser = b.getOrCreateTeam(loserID, loserName)
+	b.drift(winner, game.Date)
+	b.drift(loser, game.Date)
+
+	// Record pre-game state
+	winnerPreMean := winner.Dist.Mean()
+	loserPreMean := loser.Dist.Mean()
+	winnerPreStd := winner.Dist.Std()
+	loserPreStd := loser.Dist.Std()
+	preWinProb := b.WinProbability(winnerPreMean - loserPreMean)
+
+	// Compute joint distribution and likelihood
+	n := len(winner.Dist.Values)
+	jointProbs := make([][]float64, n)
+	for i := range jointProbs {
+		jointProbs[i] = make([]float64, n)
+	}
+
+	// Build joint distribution (outer product of marginals)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			jointProbs[i][j] = winner.Dist.Probs[i] * loser.Dist.Probs[j]
+		}
+	}
+
+	// Apply likelihood (winner won)
+	likelihood := b.Likelihood(0)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			jointProbs[i][j] *= likelihood[i-j+n-1]
+		}
+	}
+
+	// Normalize joint
+	var totalProb float64
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			totalProb += jointProbs[i][j]
+		}
+	}
+	if totalProb > 0 {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				jointProbs[i][j] /= totalProb
+			}
+		}
+	}
+
+	// Marginalize to get updated distributions
+	newWinnerProbs := make([]float64, n)
+	newLoserProbs := make([]float64, n)
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			newWinnerProbs[i] += jointProbs[i][j]
+			newLoserProbs[j] += jointProbs[i][j]
+		}
+	}
+
+	winner.Dist.Probs = newWinnerProbs
+	winner.Dist.Normalize()
+
+	loser.Dist.Probs = newLoserProbs
+	loser.Dist.Normalize()
+
+	// Log the game result (needs mutex since GameLog is shared)
+	b.logMutex.Lock()
+	b.GameLog = append(b.GameLog, GameResult{
+		GameID:        game.Key(),
+		Date:          game.Date.Format("2006-01-02"),
+		WinnerName:    winnerName,
+		WinnerID:      winnerID,
+		LoserName:     loserName,
+		LoserID:       loserID,
+		WinnerELO:     winnerPreMean,
+		LoserELO:      loserPreMean,
+		WinnerStd:     winnerPreStd,
+		LoserStd:      loserPreStd,
+		WinnerPostELO: winner.Dist.Mean(),
+		LoserPostELO:  loser.Dist.Mean(),
+		WinnerPostStd: winner.Dist.Std(),
+		LoserPostStd:  loser.Dist.Std(),
+		WinProb:       preWinProb,
+		HomeAdvantage: homeAdv,
+		WinnerScore:   max(game.HomeScore, game.AwayScore),
+		LoserScore:    min(game.HomeScore, game.AwayScore),
+		Periods:       game.Period,
+	})
+	b.logMutex.Unlock()
+	return winner, loser
+}
+
+// ProcessGames processes multiple games with parallelization where possible.
+// Processing stops between days if the context is cancelled.
+func (b *BayesianELO) ProcessGames(ctx context.Context, games []Game) error {
+	return b.ProcessGamesFunc(ctx, games, nil)
+}
+
+// ProcessGamesFunc is ProcessGames with a callback run after each day's games
+// are applied. An error from afterDay stops processing and is returned.
+func (b *BayesianELO) ProcessGamesFunc(ctx context.Context, games []Game, afterDay func(date string) error) error {
+	// Sort games by date
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].Date.Before(games[j].Date)
+	})
+
+	// Group games by date for batch processing
+	gamesByDate := make(map[string][]Game)
+	var dateOrder []string
+
+	for _, game := range games {
+		dateKey := game.Date.Format("2006-01-02")
+		if _, exists := gamesByDate[dateKey]; !exists {
+			dateOrder = append(dateOrder, dateKey)
+		}
+		gamesByDate[dateKey] = append(gamesByDate[dateKey], game)
+	}
+
+	// Process each day's games with parallelization
+	for _, dateKey := range dateOrder {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b.processDay(dateKey, gamesByDate[dateKey])
+
+		if afterDay != nil {
+			if err := afterDay(dateKey); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ProcessStream rates games as they arrive, so rating can begin while later
+// dates are still being fetched. Games must arrive in date order: each day's
+// games are applied together once a game from a later day arrives or the
+// channel is closed, and a game dated before a day already applied is an
+// error. It returns when the channel is closed or the context is done.
+func (b *BayesianELO) ProcessStream(ctx context.Context, games <-chan Game) error {
+	var day string
+	var dayGames []Game
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case game, ok := <-games:
+			if !ok {
+				b.processDay(day, dayGames)
+				return nil
+			}
+			dateKey := game.Date.Format("2006-01-02")
+			if dateKey < day {
+				return fmt.Errorf("game %s on %s arrived after %s was rated", game.Key(), dateKey, day)
+			}
+			if dateKey > day {
+				b.processDay(day, dayGames)
+				day, dayGames = dateKey, nil
+			}
+			dayGames = append(dayGames, game)
+		}
+	}
+}
+
+// processDay applies one day's games under the write lock, then snapshots
+// every team that played
+func (b *BayesianELO) processDay(dateKey string, dayGames []Game) {
+	if len(dayGames) == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.processGameBatchParallel(dayGames)
+
+	for _, game := range dayGames {
+		if _, problem := GameOutcome(game); !game.Completed || problem != "" {
+			continue
+		}
+		for _, id := range []string{game.HomeTeamID, game.AwayTeamID} {
+			if team, ok := b.Teams[id]; ok {
+				b.recordHistory(dateKey, team)
+			}
+		}
+	}
+}
+
+// processGameBatchParallel processes a batch of games from the same day
+// Games that don't share teams can be processed in parallel
+func (b *BayesianELO) processGameBatchParallel(games []Game) {
+	if len(games) == 0 {
+		return
+	}
+
+	// Pre-create all teams to avoid race conditions during parallel processing
+	for _, game := range games {
+		if _, problem := GameOutcome(game); !game.Completed || problem != "" {
+			continue
+		}
+		b.getOrCreateTeam(game.HomeTeamID, game.HomeTeam)
+		b.getOrCreateTeam(game.AwayTeamID, game.AwayTeam)
+	}
+
+	processed := make([]bool, len(games))
+	remaining := len(games)
+
+	// Mark invalid games as already processed
+	for i, game := range games {
+		if _, problem := GameOutcome(game); !game.Completed || problem != "" {
+			processed[i] = true
+			remaining--
+		}
+	}
+
+	for remaining > 0 {
+		// Find all games that can be processed in parallel (no shared teams)
+		var batch []int
+		teamsInBatch := make(map[string]bool)
+
+		for i, game := range games {
+			if processed[i] {
+				continue
+			}
+
+			// Check if this game shares any teams with games already in batch
+			homeID := game.HomeTeamID
+			awayID := game.AwayTeamID
+
+			if teamsInBatch[homeID] || teamsInBatch[awayID] {
+				// Conflict - can't process in parallel
+				continue
+			}
+
+			// Add to batch
+			batch = append(batch, i)
+			teamsInBatch[homeID] = true
+			teamsInBatch[awayID] = true
+		}
+
+		if len(batch) == 0 {
+			// Should never happen if remaining > 0
+			break
+		}
+
+		// Process batch in parallel
+		if len(batch) == 1 {
+			// Single game, no need for goroutines
+			b.processGameHistory(games[batch[0]])
+		} else {
+			var wg sync.WaitGroup
+			for _, idx := range batch {
+				wg.Add(1)
+				go func(gameIdx int) {
+					defer wg.Done()
+					b.processGame(games[gameIdx])
+				}(idx)
+			}
+			wg.Wait()
+		}
+
+		// Mark as processed
+		for _, idx := range batch {
+			processed[idx] = true
+			remaining--
+		}
+	}
+}
+
+// drift widens a team's distribution for the days since it last played, by
+// the engine's dynamics
+func (b *BayesianELO) drift(team *TeamRating, date time.Time) {
+	points := b.History[team.TeamID]
+	if b.Dynamics <= 0 || len(points) == 0 {
+		return
+	}
+	last, err := time.Parse("2006-01-02", points[len(points)-1].Date)
+	if err != nil {
+		return
+	}
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if days := day.Sub(last).Hours() / 24; days > 0 {
+		team.Dist.Diffuse(b.Dynamics * math.Sqrt(days))
+	}
+}
+
+// recordHistory stores a team's current rating as its point for date,
+// replacing an earlier point for the same day
+func (b *BayesianELO) recordHistory(date string, team *TeamRating) {
+	if b.History == nil {
+		b.History = make(map[string][]RatingPoint)
+	}
+	point := RatingPoint{Date: date, Mean: team.Dist.Mean(), Std: team.Dist.Std()}
+	points := b.History[team.TeamID]
+	if n := len(points); n > 0 && points[n-1].Date == date {
+		points[n-1] = point
+		return
+	}
+	b.History[team.TeamID] = append(points, point)
+}
+
+// RatingAsOf returns a team's mean rating at the end of date (YYYY-MM-DD)
+// from its history, or the prior mean if it had not played by then
+func (b *BayesianELO) RatingAsOf(teamID, date string) float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	rating := b.priorMean
+	for _, p := range b.History[teamID] {
+		if p.Date > date {
+			break
+		}
+		rating = p.Mean
+	}
+	return rating
+}
+
+// LastGameDate returns the date of the most recent processed game, or "" if none
+func (b *BayesianELO) LastGameDate() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var last string
+	for _, result := range b.GameLog {
+		if result.Date > last {
+			last = result.Date
+		}
+	}
+	return last
+}
+
+// ProcessedGames returns the keys of every game in the game log
+func (b *BayesianELO) ProcessedGames() map[string]bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	processed := make(map[string]bool, len(b.GameLog))
+	for _, result := range b.GameLog {
+		processed[result.GameID] = true
+	}
+	return processed
+}
+
+// Games returns a copy of the game log
+func (b *BayesianELO) Games() []GameResult {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]GameResult(nil), b.GameLog...)
+}
+
+// GetRankings returns teams sorted by mean ELO. The teams are copies that
+// share the engine's distribution slices, so they can be read while later
+// games are processed but mustn't be modified.
+func (b *BayesianELO) GetRankings() []*TeamRating {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.rankings()
+}
+
+// rankings is GetRankings for a caller holding the lock
+func (b *BayesianELO) rankings() []*TeamRating {
+	rankings := make([]*TeamRating, 0, len(b.Teams))
+	for _, team := range b.Teams {
+		rankings = append(rankings, team.snapshot())
+	}
+
+	sort.Slice(rankings, func(i, j int) bool {
+		return rankings[i].Dist.Mean() > rankings[j].Dist.Mean()
+	})
+
+	return rankings
+}
+
+// Team returns a copy of a team's current rating, shared like GetRankings
+func (b *BayesianELO) Team(teamID string) (*TeamRating, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	team, ok := b.Teams[teamID]
+	if !ok {
+		return nil, false
+	}
+	return team.snapshot(), true
+}
+
+// snapshot copies a team's rating. Processing always replaces a distribution's
+// probabilities rather than changing them in place, so the copy can share
+// the slices.
+func (t *TeamRating) snapshot() *TeamRating {
+	team := *t
+	team.Dist = &Distribution{Values: t.Dist.Values, Probs: t.Dist.Probs}
+	return &team
+}
+
+// Snapshot returns a copy of the engine as of the end of the last day
+// processed, whose fields can be read freely while this engine goes on
+// processing games
+func (b *BayesianELO) Snapshot() *BayesianELO {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	s := &BayesianELO{
+		Teams:         make(map[string]*TeamRating, len(b.Teams)),
+		KFactor:       b.KFactor,
+		HomeAdvantage: b.HomeAdvantage,
+		Dynamics:      b.Dynamics,
+		GameLog:       append([]GameResult{}, b.GameLog...),
+		History:       make(map[string][]RatingPoint, len(b.History)),
+		eloMin:        b.eloMin,
+		eloMax:        b.eloMax,
+		eloStep:       b.eloStep,
+		priorMean:     b.priorMean,
+		priorStdDev:   b.priorStdDev,
+		prior:         b.prior,
+	}
+	for id, team := range b.Teams {
+		s.Teams[id] = team.snapshot()
+	}
+	for id, points := range b.History {
+		s.History[id] = append([]RatingPoint(nil), points...)
+	}
+	return s
+}
+
+// Ranks maps team IDs to their current rank
+func (b *BayesianELO) Ranks() map[string]int {
+	rankings := b.GetRankings()
+	ranks := make(map[string]int, len(rankings))
+	for i, team := range rankings {
+		ranks[team.TeamID] = i + 1
+	}
+	return ranks
+}
+
+// PredictMatchup predicts the probability of team1 beating team2 at a
+// neutral site
+func (b *BayesianELO) PredictMatchup(team1ID, team2ID string) (float64, error) {
+	return b.PredictMatchupAt(team1ID, team2ID, 0)
+}
+
+// PredictMatchupAt predicts the probability of team1 beating team2 with team1
+// given an edge of homeELO: HomeAdvantage at home, -HomeAdvantage away
+func (b *BayesianELO) PredictMatchupAt(team1ID, team2ID string, homeELO float64) (float64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	team1, exists1 := b.Teams[team1ID]
+	team2, exists2 := b.Teams[team2ID]
+
+	if !exists1 {
+		return 0, fmt.Errorf("team %s not found", team1ID)
+	}
+	if !exists2 {
+		return 0, fmt.Errorf("team %s not found", team2ID)
+	}
+
+	// Compute win probability by integrating over joint distribution
+	likelihood := b.Likelihood(homeELO)
+	offset := len(team2.Dist.Values) - 1
+	var winProb float64
+	for i, p1 := range team1.Dist.Probs {
+		for j, p2 := range team2.Dist.Probs {
+			winProb += p1 * p2 * likelihood[i-j+offset]
+		}
+	}
+
+	return winProb, nil
+}
+
+// PrintTeamDistribution prints a summary of a team's distribution
+func (b *BayesianELO) PrintTeamDistribution(teamID string) {
+	team, exists := b.Team(teamID)
+	if !exists {
+		fmt.Printf("Team %s not found\n", teamID)
+		return
+	}
+
+	fmt.Printf("\n%s (ID: %s)\n", team.TeamName, team.TeamID)
+	if team.Conference != "" {
+		fmt.Printf("  Conference: %s\n", team.Conference)
+	}
+	fmt.Printf("  Mean ELO: %.1f\n", team.Dist.Mean())
+	fmt.Printf("  Std Dev:  %.1f\n", team.Dist.Std())
+	fmt.Printf("  MAP:      %.1f\n", team.Dist.Mode())
+	fmt.Printf("  Skewness: %+.2f\n", team.Dist.Skewness())
+	fmt.Printf("  5th %%:    %.1f\n", team.Dist.Percentile(5))
+	fmt.Printf("  25th %%:   %.1f\n", team.Dist.Percentile(25))
+	fmt.Printf("  Median:   %.1f\n", team.Dist.Percentile(50))
+	fmt.Printf("  75th %%:   %.1f\n", team.Dist.Percentile(75))
+	fmt.Printf("  95th %%:   %.1f\n", team.Dist.Percentile(95))
+	low, high := team.Dist.CredibleInterval(0.9)
+	fmt.Printf("  90%% CI:   %.0f-%.0f\n", low, high)
+}
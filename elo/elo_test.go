@@ -0,0 +1,64 @@
+package elo
+
+import (
+	"math"
+	"testing"
+)
+
+// grid is a small evenly spaced grid for distribution tests
+var grid = []float64{0, 10, 20, 30, 40}
+
+func TestPercentile(t *testing.T) {
+	uniform := []float64{0.2, 0.2, 0.2, 0.2, 0.2}
+	point := []float64{0, 0, 1, 0, 0}
+	tests := []struct {
+		name  string
+		probs []float64
+		p     float64
+		want  float64
+	}{
+		{"uniform median", uniform, 50, 20},
+		{"uniform between grid points", uniform, 25, 7.5},
+		{"uniform at a step boundary", uniform, 30, 10},
+		{"uniform low tail clamped to grid", uniform, 0, 0},
+		{"uniform top", uniform, 100, 40},
+		{"point mass median", point, 50, 20},
+		{"point mass spreads over its step", point, 10, 16},
+		{"point mass low end", point, 0, 15},
+		{"point mass high end", point, 100, 25},
+		{"skips empty grid points", []float64{0.5, 0, 0, 0, 0.5}, 75, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Distribution{Values: grid, Probs: tt.probs}
+			if got := d.Percentile(tt.p); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Percentile(%g) = %g, want %g", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredibleInterval(t *testing.T) {
+	tests := []struct {
+		name      string
+		probs     []float64
+		mass      float64
+		low, high float64
+	}{
+		{"uniform takes the first narrowest window", []float64{0.2, 0.2, 0.2, 0.2, 0.2}, 0.4, 0, 10},
+		{"follows the bulk", []float64{0.05, 0.1, 0.5, 0.3, 0.05}, 0.75, 20, 30},
+		{"widens toward the heavier side", []float64{0.05, 0.1, 0.5, 0.3, 0.05}, 0.88, 10, 30},
+		{"skewed", []float64{0.6, 0.25, 0.05, 0.05, 0.05}, 0.8, 0, 10},
+		{"point mass", []float64{0, 0, 1, 0, 0}, 0.9, 20, 20},
+		{"all the mass", []float64{0.2, 0.2, 0.2, 0.2, 0.2}, 1, 0, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Distribution{Values: grid, Probs: tt.probs}
+			low, high := d.CredibleInterval(tt.mass)
+			if low != tt.low || high != tt.high {
+				t.Errorf("CredibleInterval(%g) = %g-%g, want %g-%g", tt.mass, low, high, tt.low, tt.high)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package elo
+
+import (
+	"time"
+)
+
+// Game represents a normalized game record for our ELO system
+type Game struct {
+	ID          string
+	Date        time.Time
+	Start       time.Time // Scheduled tip-off, when the source reports one; zero otherwise
+	HomeTeamID  string
+	HomeTeam    string
+	AwayTeamID  string
+	AwayTeam    string
+	HomeScore   int
+	AwayScore   int
+	NeutralSite bool
+	Completed   bool
+	WinnerID    string
+	State       string // "pre", "in", or "post"
+	Period      int    // Current period (halves, then overtimes)
+	Clock       string // Display clock for the current period, e.g. "12:34"
+	Status      string // Empty for a game played as scheduled, else one of the Status values
+}
+
+// Game statuses for games not played out as scheduled. Cancelled, postponed,
+// and no-contest games are never completed; forfeits are, with the winner
+// the source awards.
+const (
+	StatusForfeit   = "forfeit"
+	StatusCancelled = "cancelled"
+	StatusPostponed = "postponed"
+	StatusNoContest = "no_contest"
+)
+
+// Key identifies a game across fetches: the source's game ID when present,
+// otherwise its date and matchup
+func (g Game) Key() string {
+	if g.ID != "" {
+		return g.ID
+	}
+	return g.Date.Format("2006-01-02") + ":" + g.AwayTeamID + "@" + g.HomeTeamID
+}
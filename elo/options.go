@@ -0,0 +1,67 @@
+package elo
+
+// Option configures a BayesianELO. Options given values they can't use
+// (a non-positive K factor, standard deviation, or grid step) are ignored,
+// keeping the default.
+type Option func(*BayesianELO)
+
+// WithKFactor sets the K factor scaling rating differences in the
+// likelihood. Lower values give flatter, less confident win probabilities.
+func WithKFactor(k float64) Option {
+	return func(b *BayesianELO) {
+		if k > 0 {
+			b.KFactor = k
+		}
+	}
+}
+
+// WithPrior sets the mean and standard deviation of the normal prior every
+// team starts from
+func WithPrior(mean, stdDev float64) Option {
+	return func(b *BayesianELO) {
+		if stdDev > 0 {
+			b.priorMean, b.priorStdDev = mean, stdDev
+		}
+	}
+}
+
+// WithGrid sets the range and spacing of the ratings distributions are kept
+// over. A finer grid is more precise, but each game costs the square of the
+// number of grid points.
+func WithGrid(low, high, step float64) Option {
+	return func(b *BayesianELO) {
+		if step > 0 && high-low >= step {
+			b.eloMin, b.eloMax, b.eloStep = low, high, step
+		}
+	}
+}
+
+// WithHomeAdvantage sets the rating edge given a home team in predictions
+func WithHomeAdvantage(elo float64) Option {
+	return func(b *BayesianELO) {
+		b.HomeAdvantage = elo
+	}
+}
+
+// WithDynamics lets ratings drift over the season: before each game, a team's
+// distribution widens as if its rating took a normal random walk with this
+// standard deviation per day since its last game, so older results count
+// for less. Zero, the default, holds ratings fixed.
+func WithDynamics(dailyStdDev float64) Option {
+	return func(b *BayesianELO) {
+		if dailyStdDev >= 0 {
+			b.Dynamics = dailyStdDev
+		}
+	}
+}
+
+// Options returns the options that configure a new engine like this one
+func (b *BayesianELO) Options() []Option {
+	return []Option{
+		WithKFactor(b.KFactor),
+		WithPrior(b.priorMean, b.priorStdDev),
+		WithGrid(b.eloMin, b.eloMax, b.eloStep),
+		WithHomeAdvantage(b.HomeAdvantage),
+		WithDynamics(b.Dynamics),
+	}
+}
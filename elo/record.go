@@ -0,0 +1,18 @@
+package elo
+
+// Record is a team's wins and losses
+type Record struct {
+	Wins, Losses int
+}
+
+// Records tallies each team's record from a game log
+func Records(log []GameResult) map[string]Record {
+	records := make(map[string]Record)
+	for _, g := range log {
+		w, l := records[g.WinnerID], records[g.LoserID]
+		w.Wins++
+		l.Losses++
+		records[g.WinnerID], records[g.LoserID] = w, l
+	}
+	return records
+}
@@ -0,0 +1,198 @@
+package elo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/internal/gzfile"
+	"github.com/corykiser/NCAA-Bayes-ELO/internal/migrate"
+)
+
+// StateVersion is the format version of saved engine state. When a stored
+// field is added, renamed, or changes meaning, bump the version and append a
+// migration that upgrades the previous version's documents.
+const StateVersion = 3
+
+// stateMigrations[v] upgrades a state document from version v to v+1
+var stateMigrations = []migrate.Step{
+	migrateStateV0,
+	migrateStateV1,
+	migrateStateV2,
+}
+
+// migrateStateV0 upgrades state saved before versioning. Version 0 fields all
+// keep their meaning; game log entries from before game IDs were recorded
+// simply have none, so update may reapply those days' games.
+func migrateStateV0(doc map[string]any) error {
+	return nil
+}
+
+// migrateStateV1 upgrades state saved before the game log kept standard
+// deviations and post-game ratings. Those can't be recovered without
+// reprocessing, so the new fields stay zero for older games.
+func migrateStateV1(doc map[string]any) error {
+	return nil
+}
+
+// migrateStateV2 upgrades state saved before the home advantage was recorded,
+// when it was always HomeCourtELO
+func migrateStateV2(doc map[string]any) error {
+	doc["home_advantage"] = HomeCourtELO
+	return nil
+}
+
+// TeamState is the serialized form of a team's rating distribution
+type TeamState struct {
+	TeamID     string    `json:"team_id"`
+	TeamName   string    `json:"team_name"`
+	Conference string    `json:"conference,omitempty"`
+	Values     []float64 `json:"values"`
+	Probs      []float64 `json:"probs"`
+}
+
+// EngineState is the complete serialized state of a BayesianELO, so processed
+// seasons can be saved once and served later without refetching or reprocessing
+type EngineState struct {
+	Version int       `json:"version"`
+	SavedAt time.Time `json:"saved_at"`
+	Source  string    `json:"source,omitempty"`
+	Season  int       `json:"season,omitempty"`
+
+	// Parameters the distributions were computed with
+	KFactor       float64 `json:"k_factor"`
+	ELOMin        float64 `json:"elo_min"`
+	ELOMax        float64 `json:"elo_max"`
+	ELOStep       float64 `json:"elo_step"`
+	PriorMean     float64 `json:"prior_mean"`
+	PriorStdDev   float64 `json:"prior_std_dev"`
+	HomeAdvantage float64 `json:"home_advantage"`
+	Dynamics      float64 `json:"dynamics,omitempty"`
+
+	Teams   []TeamState              `json:"teams"`
+	GameLog []GameResult             `json:"game_log"`
+	History map[string][]RatingPoint `json:"history,omitempty"`
+}
+
+// Export writes the engine's full state as JSON. Source and season are
+// recorded as metadata for whoever loads it.
+func (b *BayesianELO) Export(w io.Writer, source string, season int) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	state := EngineState{
+		Version:       StateVersion,
+		SavedAt:       time.Now(),
+		Source:        source,
+		Season:        season,
+		KFactor:       b.KFactor,
+		ELOMin:        b.eloMin,
+		ELOMax:        b.eloMax,
+		ELOStep:       b.eloStep,
+		PriorMean:     b.priorMean,
+		PriorStdDev:   b.priorStdDev,
+		HomeAdvantage: b.HomeAdvantage,
+		Dynamics:      b.Dynamics,
+		GameLog:       b.GameLog,
+		History:       b.History,
+	}
+
+	// Teams in ranking order keep the file stable between saves
+	for _, team := range b.rankings() {
+		state.Teams = append(state.Teams, TeamState{
+			TeamID:     team.TeamID,
+			TeamName:   team.TeamName,
+			Conference: team.Conference,
+			Values:     team.Dist.Values,
+			Probs:      team.Dist.Probs,
+		})
+	}
+
+	return json.NewEncoder(w).Encode(state)
+}
+
+// ImportBayesianELO restores an engine from state written by Export,
+// migrating state saved by earlier versions
+func ImportBayesianELO(r io.Reader) (*BayesianELO, *EngineState, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	data, err = migrate.Upgrade(data, StateVersion, stateMigrations)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+
+	var state EngineState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+
+	b := NewBayesianELO(
+		WithKFactor(state.KFactor),
+		WithPrior(state.PriorMean, state.PriorStdDev),
+		WithGrid(state.ELOMin, state.ELOMax, state.ELOStep),
+		WithHomeAdvantage(state.HomeAdvantage),
+		WithDynamics(state.Dynamics),
+	)
+	if state.GameLog != nil {
+		b.GameLog = state.GameLog
+	}
+	if state.History != nil {
+		b.History = state.History
+	}
+
+	for _, t := range state.Teams {
+		if len(t.Values) != len(t.Probs) || len(t.Values) == 0 {
+			return nil, nil, fmt.Errorf("team %s has a malformed distribution", t.TeamID)
+		}
+		if !b.onGrid(t.Values) {
+			return nil, nil, fmt.Errorf("team %s's distribution is not on the %g-%g grid in steps of %g", t.TeamID, b.eloMin, b.eloMax, b.eloStep)
+		}
+		b.Teams[t.TeamID] = &TeamRating{
+			TeamID:     t.TeamID,
+			TeamName:   t.TeamName,
+			Conference: t.Conference,
+			Dist:       &Distribution{Values: b.prior.Values, Probs: t.Probs},
+		}
+	}
+
+	return b, &state, nil
+}
+
+// onGrid reports whether values are the engine's grid, to within rounding
+func (b *BayesianELO) onGrid(values []float64) bool {
+	if len(values) != len(b.prior.Values) {
+		return false
+	}
+	for i, v := range values {
+		if math.Abs(v-b.prior.Values[i]) > b.eloStep*1e-6 {
+			return false
+		}
+	}
+	return true
+}
+
+// SaveState writes the engine state to a gzip-compressed file
+func (b *BayesianELO) SaveState(path, source string, season int) error {
+	var buf bytes.Buffer
+	if err := b.Export(&buf, source, season); err != nil {
+		return fmt.Errorf("failed to export state: %w", err)
+	}
+	if err := gzfile.WriteFile(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// LoadState reads an engine state file written by SaveState (compressed or not)
+func LoadState(path string) (*BayesianELO, *EngineState, error) {
+	data, err := gzfile.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	return ImportBayesianELO(bytes.NewReader(data))
+}
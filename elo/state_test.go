@@ -0,0 +1,117 @@
+package elo
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestImportStateV0(t *testing.T) {
+	f, err := os.Open("testdata/state_v0.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	b, state, err := ImportBayesianELO(f)
+	if err != nil {
+		t.Fatalf("ImportBayesianELO: %v", err)
+	}
+	if state.Version != StateVersion {
+		t.Errorf("version = %d, want %d", state.Version, StateVersion)
+	}
+	if state.Source != "espn" || state.Season != 2025 {
+		t.Errorf("source and season = %s %d, want espn 2025", state.Source, state.Season)
+	}
+	if b.HomeAdvantage != HomeCourtELO {
+		t.Errorf("home advantage = %g, want %g from before it was recorded", b.HomeAdvantage, HomeCourtELO)
+	}
+	if b.KFactor != 0.9 {
+		t.Errorf("K factor = %g, want 0.9", b.KFactor)
+	}
+
+	duke, ok := b.Team("150")
+	if !ok {
+		t.Fatal("team 150 not loaded")
+	}
+	if want := []float64{0.1, 0.2, 0.4, 0.3}; !slices.Equal(duke.Dist.Probs, want) {
+		t.Errorf("team 150 probs = %v, want %v", duke.Dist.Probs, want)
+	}
+	if want := []float64{1400, 1450, 1500, 1550}; !slices.Equal(duke.Dist.Values, want) {
+		t.Errorf("team 150 values = %v, want %v", duke.Dist.Values, want)
+	}
+
+	games := b.Games()
+	if len(games) != 1 {
+		t.Fatalf("game log has %d games, want 1", len(games))
+	}
+	if g := games[0]; g.WinnerID != "150" || g.LoserID != "153" || g.GameID != "" || g.WinnerStd != 0 {
+		t.Errorf("game log entry = %+v, want 150 over 153 with no ID or deviations", g)
+	}
+	if got := b.RatingAsOf("153", "2025-01-14"); got != 1470 {
+		t.Errorf("153's rating after 2025-01-14 = %g, want 1470", got)
+	}
+}
+
+func TestImportStateVersions(t *testing.T) {
+	const teams = `"elo_min": 1400, "elo_max": 1600, "elo_step": 50, "prior_mean": 1500, "prior_std_dev": 100, "k_factor": 0.9,
+		"teams": [{"team_id": "1", "values": [1400, 1450, 1500, 1550], "probs": [0.25, 0.25, 0.25, 0.25]}]`
+	tests := []struct {
+		name     string
+		state    string
+		wantHome float64
+		wantErr  string
+	}{
+		{"version 1", `{"version": 1, ` + teams + `}`, HomeCourtELO, ""},
+		{"version 2", `{"version": 2, ` + teams + `}`, HomeCourtELO, ""},
+		{"current keeps its home advantage", `{"version": 3, "home_advantage": 60, ` + teams + `}`, 60, ""},
+		{"newer version", `{"version": 4, ` + teams + `}`, 0, "newer than supported"},
+		{"negative version", `{"version": -2, ` + teams + `}`, 0, "invalid format version -2"},
+		{"off the grid", `{"version": 3, ` + strings.Replace(teams, `"elo_step": 50`, `"elo_step": 25`, 1) + `}`, 0, "not on the 1400-1600 grid"},
+		{"malformed distribution", `{"version": 3, ` + strings.Replace(teams, `0.25, 0.25, 0.25, 0.25`, `0.5, 0.5`, 1) + `}`, 0, "malformed distribution"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _, err := ImportBayesianELO(strings.NewReader(tt.state))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ImportBayesianELO error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ImportBayesianELO: %v", err)
+			}
+			if b.HomeAdvantage != tt.wantHome {
+				t.Errorf("home advantage = %g, want %g", b.HomeAdvantage, tt.wantHome)
+			}
+		})
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	b := NewBayesianELO(WithGrid(1000, 2000, 10), WithHomeAdvantage(75), WithDynamics(2))
+	b.ProcessGame(Game{ID: "1", HomeTeamID: "a", HomeTeam: "A", AwayTeamID: "b", AwayTeam: "B", HomeScore: 70, AwayScore: 60, Completed: true, WinnerID: "a"})
+
+	var buf bytes.Buffer
+	if err := b.Export(&buf, "espn", 2025); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	loaded, state, err := ImportBayesianELO(&buf)
+	if err != nil {
+		t.Fatalf("ImportBayesianELO: %v", err)
+	}
+	if state.Version != StateVersion || loaded.HomeAdvantage != 75 || loaded.Dynamics != 2 {
+		t.Errorf("loaded version %d, home advantage %g, dynamics %g; want %d, 75, 2",
+			state.Version, loaded.HomeAdvantage, loaded.Dynamics, StateVersion)
+	}
+	for _, id := range []string{"a", "b"} {
+		want, _ := b.Team(id)
+		got, ok := loaded.Team(id)
+		if !ok || !slices.Equal(got.Dist.Probs, want.Dist.Probs) {
+			t.Errorf("team %s did not round-trip", id)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package elo
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// In-game win probability parameters for men's college basketball
+const (
+	HalfSeconds       = 1200.0 // Length of each regulation half
+	OvertimeSeconds   = 300.0  // Length of each overtime period
+	RegulationSeconds = 2 * HalfSeconds
+	FinalMarginStdDev = 11.0 // Standard deviation of final scoring margin around expectation
+)
+
+// LiveWinProbability estimates the probability that a team wins given its
+// pre-game win probability, its current lead (negative when trailing), and the
+// seconds left to play.
+//
+// The pre-game probability is converted to an expected full-game margin under a
+// normal margin model. The remaining share of that edge plus the current lead
+// determines the expected final margin, with variance shrinking as the clock runs.
+func LiveWinProbability(pregameProb float64, scoreDiff int, secondsRemaining float64) float64 {
+	// Clamp to keep the inverse normal finite
+	p := math.Min(math.Max(pregameProb, 1e-6), 1-1e-6)
+	expectedMargin := FinalMarginStdDev * normalQuantile(p)
+
+	if secondsRemaining <= 0 {
+		switch {
+		case scoreDiff > 0:
+			return 1
+		case scoreDiff < 0:
+			return 0
+		}
+		// Tied at the horn means overtime
+		secondsRemaining = OvertimeSeconds
+	}
+
+	fraction := secondsRemaining / RegulationSeconds
+	mean := float64(scoreDiff) + expectedMargin*fraction
+	std := FinalMarginStdDev * math.Sqrt(fraction)
+
+	return normalCDF(mean / std)
+}
+
+// GameSecondsRemaining converts a period number and display clock ("12:34" or
+// "45.2") into the seconds left to play, including the rest of regulation
+func GameSecondsRemaining(period int, clock string) float64 {
+	seconds := parseClock(clock)
+	switch {
+	case period <= 0:
+		return RegulationSeconds
+	case period == 1:
+		return HalfSeconds + seconds
+	default:
+		// Second half or overtime: only the current period remains
+		return seconds
+	}
+}
+
+// parseClock parses a display clock in "MM:SS" or "SS.s" form into seconds
+func parseClock(clock string) float64 {
+	clock = strings.TrimSpace(clock)
+	if clock == "" {
+		return 0
+	}
+
+	minutes, secs := "0", clock
+	if i := strings.Index(clock, ":"); i >= 0 {
+		minutes, secs = clock[:i], clock[i+1:]
+	}
+
+	m, err := strconv.ParseFloat(minutes, 64)
+	if err != nil {
+		return 0
+	}
+	s, err := strconv.ParseFloat(secs, 64)
+	if err != nil {
+		return 0
+	}
+	return m*60 + s
+}
+
+// LiveHomeWinProbability estimates the home team's chance of winning an
+// in-progress game from its pre-game home win probability and the game state
+func LiveHomeWinProbability(pregameHomeProb float64, g Game) float64 {
+	if g.Completed || g.State == "post" {
+		switch {
+		case g.HomeScore > g.AwayScore:
+			return 1
+		case g.HomeScore < g.AwayScore:
+			return 0
+		}
+	}
+	if g.State != "in" {
+		return pregameHomeProb
+	}
+	return LiveWinProbability(pregameHomeProb, g.HomeScore-g.AwayScore, GameSecondsRemaining(g.Period, g.Clock))
+}
+
+// normalCDF is the standard normal cumulative distribution function
+func normalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
+
+// normalQuantile is the inverse of the standard normal CDF
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
@@ -0,0 +1,56 @@
+// Package gzfile reads and writes gzip-compressed files, reading
+// uncompressed ones unchanged.
+package gzfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+)
+
+// gzipMagic is the two-byte header that starts every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// WriteFile gzips data and writes it to path
+func WriteFile(path string, data []byte) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// ReadFile reads path, transparently decompressing gzip content so
+// files written before compression was added still load
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// ReadFileOr reads a compressed cache file, falling back to the
+// uncompressed legacy path when no compressed copy exists
+func ReadFileOr(path, legacyPath string) ([]byte, error) {
+	data, err := ReadFile(path)
+	if os.IsNotExist(err) {
+		return ReadFile(legacyPath)
+	}
+	return data, err
+}
@@ -0,0 +1,49 @@
+// Package migrate upgrades versioned JSON documents one version at a time.
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Step upgrades a decoded JSON document by one version in place
+type Step func(doc map[string]any) error
+
+// Upgrade upgrades a stored JSON document to the current version. Documents
+// without a version field are version 0; documents from a newer version, or
+// with a negative one, are rejected rather than risk misreading them.
+func Upgrade(data []byte, current int, migrations []Step) ([]byte, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	if header.Version == current {
+		return data, nil
+	}
+	if header.Version < 0 {
+		return nil, fmt.Errorf("invalid format version %d", header.Version)
+	}
+	if header.Version > current {
+		return nil, fmt.Errorf("format version %d is newer than supported version %d", header.Version, current)
+	}
+
+	// Numbers stay as written so distributions round-trip exactly
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	for v := header.Version; v < current; v++ {
+		if err := migrations[v](doc); err != nil {
+			return nil, fmt.Errorf("migrating from version %d: %w", v, err)
+		}
+		doc["version"] = v + 1
+	}
+
+	return json.Marshal(doc)
+}
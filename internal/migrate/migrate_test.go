@@ -0,0 +1,99 @@
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// steps records which migrations ran by appending to the document's "steps"
+var steps = []Step{
+	func(doc map[string]any) error { return mark(doc, "v0") },
+	func(doc map[string]any) error { return mark(doc, "v1") },
+	func(doc map[string]any) error { return mark(doc, "v2") },
+}
+
+// mark appends a step's name to the document's "steps"
+func mark(doc map[string]any, name string) error {
+	ran, _ := doc["steps"].(string)
+	doc["steps"] = ran + name + ";"
+	return nil
+}
+
+func TestUpgrade(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantSteps string
+		wantErr   string
+	}{
+		{"unversioned is version 0", `{"value": 1}`, "v0;v1;v2;", ""},
+		{"version 0", `{"version": 0}`, "v0;v1;v2;", ""},
+		{"version 2 runs the last step", `{"version": 2}`, "v2;", ""},
+		{"newer version", `{"version": 4}`, "", "format version 4 is newer than supported version 3"},
+		{"negative version", `{"version": -1}`, "", "invalid format version -1"},
+		{"not an object", `[1, 2]`, "", "cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Upgrade([]byte(tt.input), 3, steps)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Upgrade error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Upgrade: %v", err)
+			}
+
+			var doc struct {
+				Version int    `json:"version"`
+				Steps   string `json:"steps"`
+			}
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("upgraded document: %v", err)
+			}
+			if doc.Version != 3 {
+				t.Errorf("version = %d, want 3", doc.Version)
+			}
+			if doc.Steps != tt.wantSteps {
+				t.Errorf("steps run = %q, want %q", doc.Steps, tt.wantSteps)
+			}
+		})
+	}
+}
+
+func TestUpgradeCurrentIsUnchanged(t *testing.T) {
+	input := `{"version": 3, "probs": [0.1000000000000000055511151231257827]}`
+	data, err := Upgrade([]byte(input), 3, steps)
+	if err != nil {
+		t.Fatalf("Upgrade: %v", err)
+	}
+	if string(data) != input {
+		t.Errorf("Upgrade = %s, want the input unchanged", data)
+	}
+}
+
+func TestUpgradeKeepsNumbers(t *testing.T) {
+	data, err := Upgrade([]byte(`{"probs": [0.30000000000000004, 1e-300]}`), 1, steps[:1])
+	if err != nil {
+		t.Fatalf("Upgrade: %v", err)
+	}
+	if !strings.Contains(string(data), `[0.30000000000000004,1e-300]`) {
+		t.Errorf("Upgrade = %s, want numbers as written", data)
+	}
+}
+
+func TestUpgradeStepError(t *testing.T) {
+	failing := errors.New("bad document")
+	broken := []Step{
+		func(doc map[string]any) error { return nil },
+		func(doc map[string]any) error { return failing },
+	}
+	_, err := Upgrade([]byte(`{}`), 2, broken)
+	if !errors.Is(err, failing) || !strings.Contains(err.Error(), "migrating from version 1") {
+		t.Errorf("Upgrade error = %v, want the failing step's error from version 1", err)
+	}
+}
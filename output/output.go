@@ -0,0 +1,164 @@
+// Package output renders rankings as text tables, JSON, and CSV.
+package output
+
+import (
+	"github.com/corykiser/NCAA-Bayes-ELO/elo"
+)
+
+// Format specifies the output format type
+type Format string
+
+const (
+	FormatTable    Format = "table"
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+	FormatJSONL    Format = "jsonl" // One JSON object per line
+	FormatHTML     Format = "html"  // Self-contained report page
+	FormatAtom     Format = "atom"  // Feed with an entry per game day
+	FormatRSS      Format = "rss"   // Feed with an item per game day
+	FormatMarkdown Format = "markdown"
+
+	// Binary formats, written only to -output files
+	FormatParquet     Format = "parquet"
+	FormatArrow       Format = "arrow"        // Arrow IPC file
+	FormatArrowStream Format = "arrow-stream" // Arrow IPC stream
+	FormatXLSX        Format = "xlsx"         // Excel workbook
+)
+
+// Binary reports whether a format is binary and so must be written to a file
+func (f Format) Binary() bool {
+	switch f {
+	case FormatParquet, FormatArrow, FormatArrowStream, FormatXLSX:
+		return true
+	}
+	return false
+}
+
+// Team represents a team's rating for JSON/CSV/Parquet/Arrow output
+type Team struct {
+	Rank        int     `json:"rank" parquet:"rank"`
+	TeamID      string  `json:"team_id" parquet:"team_id"`
+	TeamName    string  `json:"team_name" parquet:"team_name"`
+	Conference  string  `json:"conference,omitempty" parquet:"conference"`
+	MeanELO     float64 `json:"mean_elo" parquet:"mean_elo"`
+	StdDev      float64 `json:"std_dev" parquet:"std_dev"`
+	Pct5        float64 `json:"percentile_5" parquet:"percentile_5"`
+	Pct25       float64 `json:"percentile_25" parquet:"percentile_25"`
+	Median      float64 `json:"median" parquet:"median"`
+	Pct75       float64 `json:"percentile_75" parquet:"percentile_75"`
+	Pct95       float64 `json:"percentile_95" parquet:"percentile_95"`
+	MAP         float64 `json:"map_elo" parquet:"map_elo"` // Most probable rating
+	Skewness    float64 `json:"skewness" parquet:"skewness"`
+	CI90Low     float64 `json:"ci90_low" parquet:"ci90_low"` // Narrowest range holding 90% of the probability
+	CI90High    float64 `json:"ci90_high" parquet:"ci90_high"`
+	Wins        int     `json:"wins" parquet:"wins"`
+	Losses      int     `json:"losses" parquet:"losses"`
+	HomeWins    int     `json:"home_wins" parquet:"home_wins"`
+	HomeLosses  int     `json:"home_losses" parquet:"home_losses"`
+	RoadWins    int     `json:"road_wins" parquet:"road_wins"`
+	RoadLosses  int     `json:"road_losses" parquet:"road_losses"`
+	Top50Wins   int     `json:"top50_wins" parquet:"top50_wins"`
+	Top50Losses int     `json:"top50_losses" parquet:"top50_losses"`
+	FormELO     float64 `json:"form_elo,omitempty" parquet:"form_elo"` // Rating from recent games only, when asked for
+}
+
+// Ranked summarizes teams already in ranking order for output
+func Ranked(engine *elo.BayesianELO, rankings []*elo.TeamRating) []Team {
+	splits := Splits(engine)
+	var teamOutputs []Team
+	for i, team := range rankings {
+		t := NewTeam(i+1, team)
+		t.SetRecords(splits[team.TeamID])
+		teamOutputs = append(teamOutputs, t)
+	}
+	return teamOutputs
+}
+
+// SplitRecord is a team's record overall, at home, on the road, and against
+// the current top 50. Neutral-site games count only toward overall.
+type SplitRecord struct {
+	overall, home, road, top50 elo.Record
+}
+
+// Splits tallies each team's split records from the game log
+func Splits(engine *elo.BayesianELO) map[string]SplitRecord {
+	ranks := engine.Ranks()
+	splits := make(map[string]SplitRecord)
+	for _, g := range engine.Games() {
+		w, l := splits[g.WinnerID], splits[g.LoserID]
+		w.overall.Wins++
+		l.overall.Losses++
+		switch g.HomeAdvantage {
+		case "H":
+			w.home.Wins++
+			l.road.Losses++
+		case "A":
+			w.road.Wins++
+			l.home.Losses++
+		}
+		if rank := ranks[g.LoserID]; rank > 0 && rank <= 50 {
+			w.top50.Wins++
+		}
+		if rank := ranks[g.WinnerID]; rank > 0 && rank <= 50 {
+			l.top50.Losses++
+		}
+		splits[g.WinnerID], splits[g.LoserID] = w, l
+	}
+	return splits
+}
+
+// SetRecords fills in a team's split records
+func (t *Team) SetRecords(s SplitRecord) {
+	t.Wins, t.Losses = s.overall.Wins, s.overall.Losses
+	t.HomeWins, t.HomeLosses = s.home.Wins, s.home.Losses
+	t.RoadWins, t.RoadLosses = s.road.Wins, s.road.Losses
+	t.Top50Wins, t.Top50Losses = s.top50.Wins, s.top50.Losses
+}
+
+// NewTeam summarizes one team's distribution at a given rank
+func NewTeam(rank int, team *elo.TeamRating) Team {
+	low, high := team.Dist.CredibleInterval(0.9)
+	return Team{
+		Rank:       rank,
+		TeamID:     team.TeamID,
+		TeamName:   team.TeamName,
+		Conference: team.Conference,
+		MeanELO:    team.Dist.Mean(),
+		StdDev:     team.Dist.Std(),
+		Pct5:       team.Dist.Percentile(5),
+		Pct25:      team.Dist.Percentile(25),
+		Median:     team.Dist.Percentile(50),
+		Pct75:      team.Dist.Percentile(75),
+		Pct95:      team.Dist.Percentile(95),
+		MAP:        team.Dist.Mode(),
+		Skewness:   team.Dist.Skewness(),
+		CI90Low:    low,
+		CI90High:   high,
+	}
+}
+
+// Posterior is a team's rating summary together with its full posterior
+// distribution: the probability of each value on the ELO grid
+type Posterior struct {
+	Team
+	Values []float64 `json:"values"`
+	Probs  []float64 `json:"probs"`
+}
+
+// Posteriors attaches each team's distribution to its rating summary,
+// leaving out teams the engine doesn't have
+func Posteriors(engine *elo.BayesianELO, teams []Team) []Posterior {
+	outputs := make([]Posterior, 0, len(teams))
+	for _, t := range teams {
+		team, ok := engine.Team(t.TeamID)
+		if !ok {
+			continue
+		}
+		outputs = append(outputs, Posterior{
+			Team:   t,
+			Values: team.Dist.Values,
+			Probs:  team.Dist.Probs,
+		})
+	}
+	return outputs
+}
@@ -0,0 +1,59 @@
+package output
+
+import (
+	"strings"
+)
+
+// ANSI escape sequences used to style tables
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiReverse = "\x1b[7m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+)
+
+// TableStyle controls how text tables are styled for a terminal. The zero
+// value renders plain text, which is what files and pipes get.
+type TableStyle struct {
+	Color     bool
+	Highlight string // ID of a team whose row stands out
+	Groups    bool   // Rule off each conference's rows, for tables grouped by conference
+}
+
+// paint wraps text in ANSI codes when coloring
+func (s TableStyle) paint(text string, codes ...string) string {
+	if !s.Color || len(codes) == 0 {
+		return text
+	}
+	return strings.Join(codes, "") + text + ansiReset
+}
+
+// rowCodes returns the codes for a ranked team's row: bold for the top 16
+// (the top four seed lines), dim outside the 68-team field, and reversed for
+// the highlighted team
+func (s TableStyle) rowCodes(rank int, teamID string) []string {
+	var codes []string
+	switch {
+	case rank <= 16:
+		codes = append(codes, ansiBold)
+	case rank > 68:
+		codes = append(codes, ansiDim)
+	}
+	if teamID == s.Highlight {
+		codes = append(codes, ansiReverse)
+	}
+	return codes
+}
+
+// trendCodes colors a rating change green when it rose and red when it fell
+func trendCodes(delta float64) []string {
+	switch {
+	case delta >= 0.05:
+		return []string{ansiGreen}
+	case delta <= -0.05:
+		return []string{ansiRed}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/elo"
+)
+
+// HasConferences reports whether any of the teams has a known conference
+func HasConferences(teams []Team) bool {
+	for _, t := range teams {
+		if t.Conference != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Table renders the rankings table. With trends, a column shows each
+// team's rating change over the last trendDays days. A conference column is
+// shown when conferences are known.
+func Table(teams []Team, season int, trends map[string]float64, trendDays int, style TableStyle) string {
+	var sb strings.Builder
+
+	width := 150
+	trendHeader := ""
+	if trends != nil {
+		width += 10
+		trendHeader = PadLeft(fmt.Sprintf("%dd", trendDays), 10)
+	}
+	teamHeader := fmt.Sprintf("%-30s", "Team")
+	showConference := HasConferences(teams)
+	if showConference {
+		width += 15
+		teamHeader += fmt.Sprintf(" %-14s", "Conference")
+	}
+	meanHeader := fmt.Sprintf("%8s", "Mean")
+	showForm := hasForm(teams)
+	if showForm {
+		width += 9
+		meanHeader += fmt.Sprintf(" %8s", "Form")
+	}
+	meanHeader += fmt.Sprintf(" %8s", "MAP")
+
+	sb.WriteString(fmt.Sprintf("\nNCAA Men's Basketball Bayesian ELO Rankings (%d-%d Season)\n", season-1, season))
+	sb.WriteString(fmt.Sprintf("Generated: %s\n", time.Now().Format("2006-01-02 15:04:05")))
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("%-4s %s %6s %6s %6s %6s %s %8s %8s %8s %8s %8s %8s %12s%s\n",
+		"Rank", teamHeader, "W-L", "Home", "Road", "Top50", meanHeader, "StdDev", "5th%", "25th%", "Median", "75th%", "95th%", "90% CI", trendHeader))
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+
+	for i, team := range teams {
+		if style.Groups && i > 0 && team.Conference != teams[i-1].Conference {
+			sb.WriteString(strings.Repeat("-", width) + "\n")
+		}
+		// Styles wrap padded cells so escape codes don't throw off alignment
+		codes := style.rowCodes(team.Rank, team.TeamID)
+		trend := ""
+		if trends != nil {
+			delta := trends[team.TeamID]
+			trend = style.paint(PadLeft(FormatTrend(delta), 10), append(codes, trendCodes(delta)...)...)
+		}
+		name := fmt.Sprintf("%-30s", Truncate(team.TeamName, 30))
+		if showConference {
+			name += fmt.Sprintf(" %-14s", Truncate(team.Conference, 14))
+		}
+		// Form is colored by how far it runs above or below the season rating
+		form := ""
+		if showForm {
+			form = style.paint(fmt.Sprintf(" %8.1f", team.FormELO), append(codes, trendCodes(team.FormELO-team.MeanELO)...)...)
+		}
+		sb.WriteString(style.paint(fmt.Sprintf("%-4d %s %6s %6s %6s %6s %8.1f",
+			team.Rank,
+			name,
+			fmt.Sprintf("%d-%d", team.Wins, team.Losses),
+			fmt.Sprintf("%d-%d", team.HomeWins, team.HomeLosses),
+			fmt.Sprintf("%d-%d", team.RoadWins, team.RoadLosses),
+			fmt.Sprintf("%d-%d", team.Top50Wins, team.Top50Losses),
+			team.MeanELO), codes...) + form + style.paint(fmt.Sprintf(" %8.1f %8.1f %8.1f %8.1f %8.1f %8.1f %8.1f %12s",
+			team.MAP,
+			team.StdDev,
+			team.Pct5,
+			team.Pct25,
+			team.Median,
+			team.Pct75,
+			team.Pct95,
+			fmt.Sprintf("%.0f-%.0f", team.CI90Low, team.CI90High)), codes...) + trend + "\n")
+	}
+
+	sb.WriteString(strings.Repeat("=", width) + "\n")
+	sb.WriteString(fmt.Sprintf("\nNote: ELO distributions show uncertainty in team strength.\n"))
+	sb.WriteString(fmt.Sprintf("      Higher StdDev = more uncertainty about true strength.\n"))
+
+	return sb.String()
+}
+
+func JSON[T any](teams []T) string {
+	data, _ := json.MarshalIndent(teams, "", "  ")
+	return string(data)
+}
+
+func CSV(teams []Team) string {
+	var sb strings.Builder
+
+	sb.WriteString("rank,team_id,team_name,conference,mean_elo,std_dev,pct_5,pct_25,median,pct_75,pct_95,wins,losses,home_wins,home_losses,road_wins,road_losses,top50_wins,top50_losses,map_elo,skewness,ci90_low,ci90_high")
+	showForm := hasForm(teams)
+	if showForm {
+		sb.WriteString(",form_elo")
+	}
+	sb.WriteString("\n")
+
+	for _, team := range teams {
+		sb.WriteString(fmt.Sprintf("%d,%s,\"%s\",\"%s\",%.1f,%.1f,%.1f,%.1f,%.1f,%.1f,%.1f,%d,%d,%d,%d,%d,%d,%d,%d,%.1f,%.3f,%.1f,%.1f",
+			team.Rank,
+			team.TeamID,
+			team.TeamName,
+			team.Conference,
+			team.MeanELO,
+			team.StdDev,
+			team.Pct5,
+			team.Pct25,
+			team.Median,
+			team.Pct75,
+			team.Pct95,
+			team.Wins,
+			team.Losses,
+			team.HomeWins,
+			team.HomeLosses,
+			team.RoadWins,
+			team.RoadLosses,
+			team.Top50Wins,
+			team.Top50Losses,
+			team.MAP,
+			team.Skewness,
+			team.CI90Low,
+			team.CI90High))
+		if showForm {
+			sb.WriteString(fmt.Sprintf(",%.1f", team.FormELO))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// hasForm reports whether any team has a recent-form rating to show
+func hasForm(teams []Team) bool {
+	for _, t := range teams {
+		if t.FormELO != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Trends returns each team's rating change over the days before the
+// most recent game, which keeps finished seasons' trends meaningful
+func Trends(engine *elo.BayesianELO, days int) map[string]float64 {
+	trends := make(map[string]float64)
+	last, err := time.Parse("2006-01-02", engine.LastGameDate())
+	if err != nil {
+		return trends
+	}
+	since := last.AddDate(0, 0, -days).Format("2006-01-02")
+	for _, team := range engine.GetRankings() {
+		trends[team.TeamID] = team.Dist.Mean() - engine.RatingAsOf(team.TeamID, since)
+	}
+	return trends
+}
+
+// FormatTrend shows a rating change as an arrow and delta
+func FormatTrend(delta float64) string {
+	switch {
+	case delta >= 0.05:
+		return fmt.Sprintf("▲ %.1f", delta)
+	case delta <= -0.05:
+		return fmt.Sprintf("▼ %.1f", -delta)
+	}
+	return "–"
+}
+
+// PadLeft right-aligns s in width columns, counting runes rather than bytes
+func PadLeft(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
+
+// Truncate shortens s to maxLen runes, ending in "..." when cut
+func Truncate(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
@@ -0,0 +1,5 @@
+// Package ratingspb holds the gRPC service definition for ratings and
+// predictions and the code generated from it
+package ratingspb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative ratings.proto
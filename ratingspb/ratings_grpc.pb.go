@@ -0,0 +1,295 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: ratings.proto
+
+// Ratings and predictions from the NCAA Bayesian ELO model.
+
+package ratingspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Ratings_GetRankings_FullMethodName  = "/ncaaelo.v1.Ratings/GetRankings"
+	Ratings_GetTeam_FullMethodName      = "/ncaaelo.v1.Ratings/GetTeam"
+	Ratings_Predict_FullMethodName      = "/ncaaelo.v1.Ratings/Predict"
+	Ratings_GetHistory_FullMethodName   = "/ncaaelo.v1.Ratings/GetHistory"
+	Ratings_WatchRatings_FullMethodName = "/ncaaelo.v1.Ratings/WatchRatings"
+)
+
+// RatingsClient is the client API for Ratings service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Ratings serves a rated season to programmatic consumers.
+type RatingsClient interface {
+	// GetRankings returns teams in ranking order.
+	GetRankings(ctx context.Context, in *GetRankingsRequest, opts ...grpc.CallOption) (*GetRankingsResponse, error)
+	// GetTeam returns one team with its full posterior distribution.
+	GetTeam(ctx context.Context, in *GetTeamRequest, opts ...grpc.CallOption) (*Team, error)
+	// Predict returns win probabilities for a matchup.
+	Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (*Prediction, error)
+	// GetHistory returns a team's rating after each day it played.
+	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
+	// WatchRatings sends the current ratings, then an update after every
+	// refresh that applies new games.
+	WatchRatings(ctx context.Context, in *WatchRatingsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RatingUpdate], error)
+}
+
+type ratingsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRatingsClient(cc grpc.ClientConnInterface) RatingsClient {
+	return &ratingsClient{cc}
+}
+
+func (c *ratingsClient) GetRankings(ctx context.Context, in *GetRankingsRequest, opts ...grpc.CallOption) (*GetRankingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRankingsResponse)
+	err := c.cc.Invoke(ctx, Ratings_GetRankings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingsClient) GetTeam(ctx context.Context, in *GetTeamRequest, opts ...grpc.CallOption) (*Team, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Team)
+	err := c.cc.Invoke(ctx, Ratings_GetTeam_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingsClient) Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (*Prediction, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Prediction)
+	err := c.cc.Invoke(ctx, Ratings_Predict_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingsClient) GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHistoryResponse)
+	err := c.cc.Invoke(ctx, Ratings_GetHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingsClient) WatchRatings(ctx context.Context, in *WatchRatingsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RatingUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Ratings_ServiceDesc.Streams[0], Ratings_WatchRatings_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRatingsRequest, RatingUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Ratings_WatchRatingsClient = grpc.ServerStreamingClient[RatingUpdate]
+
+// RatingsServer is the server API for Ratings service.
+// All implementations must embed UnimplementedRatingsServer
+// for forward compatibility.
+//
+// Ratings serves a rated season to programmatic consumers.
+type RatingsServer interface {
+	// GetRankings returns teams in ranking order.
+	GetRankings(context.Context, *GetRankingsRequest) (*GetRankingsResponse, error)
+	// GetTeam returns one team with its full posterior distribution.
+	GetTeam(context.Context, *GetTeamRequest) (*Team, error)
+	// Predict returns win probabilities for a matchup.
+	Predict(context.Context, *PredictRequest) (*Prediction, error)
+	// GetHistory returns a team's rating after each day it played.
+	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
+	// WatchRatings sends the current ratings, then an update after every
+	// refresh that applies new games.
+	WatchRatings(*WatchRatingsRequest, grpc.ServerStreamingServer[RatingUpdate]) error
+	mustEmbedUnimplementedRatingsServer()
+}
+
+// UnimplementedRatingsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRatingsServer struct{}
+
+func (UnimplementedRatingsServer) GetRankings(context.Context, *GetRankingsRequest) (*GetRankingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRankings not implemented")
+}
+func (UnimplementedRatingsServer) GetTeam(context.Context, *GetTeamRequest) (*Team, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTeam not implemented")
+}
+func (UnimplementedRatingsServer) Predict(context.Context, *PredictRequest) (*Prediction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Predict not implemented")
+}
+func (UnimplementedRatingsServer) GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedRatingsServer) WatchRatings(*WatchRatingsRequest, grpc.ServerStreamingServer[RatingUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRatings not implemented")
+}
+func (UnimplementedRatingsServer) mustEmbedUnimplementedRatingsServer() {}
+func (UnimplementedRatingsServer) testEmbeddedByValue()                 {}
+
+// UnsafeRatingsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RatingsServer will
+// result in compilation errors.
+type UnsafeRatingsServer interface {
+	mustEmbedUnimplementedRatingsServer()
+}
+
+func RegisterRatingsServer(s grpc.ServiceRegistrar, srv RatingsServer) {
+	// If the following call pancis, it indicates UnimplementedRatingsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Ratings_ServiceDesc, srv)
+}
+
+func _Ratings_GetRankings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRankingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingsServer).GetRankings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ratings_GetRankings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingsServer).GetRankings(ctx, req.(*GetRankingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ratings_GetTeam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTeamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingsServer).GetTeam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ratings_GetTeam_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingsServer).GetTeam(ctx, req.(*GetTeamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ratings_Predict_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PredictRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingsServer).Predict(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ratings_Predict_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingsServer).Predict(ctx, req.(*PredictRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ratings_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingsServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ratings_GetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingsServer).GetHistory(ctx, req.(*GetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ratings_WatchRatings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRatingsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RatingsServer).WatchRatings(m, &grpc.GenericServerStream[WatchRatingsRequest, RatingUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Ratings_WatchRatingsServer = grpc.ServerStreamingServer[RatingUpdate]
+
+// Ratings_ServiceDesc is the grpc.ServiceDesc for Ratings service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Ratings_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ncaaelo.v1.Ratings",
+	HandlerType: (*RatingsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRankings",
+			Handler:    _Ratings_GetRankings_Handler,
+		},
+		{
+			MethodName: "GetTeam",
+			Handler:    _Ratings_GetTeam_Handler,
+		},
+		{
+			MethodName: "Predict",
+			Handler:    _Ratings_Predict_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _Ratings_GetHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchRatings",
+			Handler:       _Ratings_WatchRatings_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ratings.proto",
+}
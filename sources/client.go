@@ -0,0 +1,110 @@
+package sources
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	_ "time/tzdata" // US Eastern on systems without a zoneinfo database
+)
+
+// Config controls how an API client issues requests
+type Config struct {
+	Concurrency       int     // Parallel workers used for date range fetches
+	RequestsPerSecond float64 // Sustained request rate shared by all workers (0 = unlimited)
+	Burst             int     // Requests allowed back-to-back before the rate applies
+
+	// HTTPCache enables conditional requests against previously fetched
+	// responses; nil disables it
+	HTTPCache *HTTPCache
+
+	Proxy     *url.URL    // Explicit proxy; nil uses HTTP_PROXY/HTTPS_PROXY from the environment
+	UserAgent string      // User-Agent sent with every request (empty = Go default)
+	Headers   http.Header // Extra headers sent with every request
+
+	// Instrument, if set, wraps the transport, e.g. to record request metrics
+	Instrument func(http.RoundTripper) http.RoundTripper
+
+	// Location is the time zone whose calendar day a game is dated by; nil
+	// uses US Eastern, as both sources' scoreboards do
+	Location *time.Location
+}
+
+// easternTime is the default zone for dating games
+var easternTime, _ = time.LoadLocation("America/New_York")
+
+// GameDay returns the calendar date of a start time in loc (US Eastern if
+// nil), as midnight UTC like every other game date
+func GameDay(start time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = easternTime
+	}
+	local := start.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// NewHTTPClient builds an HTTP client honoring the proxy, user agent, and
+// extra headers in the config
+func NewHTTPClient(config Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.Proxy != nil {
+		transport.Proxy = http.ProxyURL(config.Proxy)
+	}
+
+	var rt http.RoundTripper = transport
+	if config.Instrument != nil {
+		rt = config.Instrument(rt)
+	}
+	if config.UserAgent != "" || len(config.Headers) > 0 {
+		rt = &headerTransport{
+			base:      rt,
+			userAgent: config.UserAgent,
+			headers:   config.Headers,
+		}
+	}
+
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: rt,
+	}
+}
+
+// headerTransport adds configured headers to every outgoing request
+type headerTransport struct {
+	base      http.RoundTripper
+	userAgent string
+	headers   http.Header
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+	if t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// ParseHeaders parses "Name: Value" pairs into a header set
+func ParseHeaders(pairs []string) (http.Header, error) {
+	headers := make(http.Header)
+	for _, pair := range pairs {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q (expected 'Name: Value')", pair)
+		}
+		headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return headers, nil
+}
@@ -0,0 +1,64 @@
+package espn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// GroupsResponse is the conference listing from ESPN's groups endpoint
+type GroupsResponse struct {
+	Groups []Group `json:"groups"`
+}
+
+// Group is a division or conference, listing its member teams or its
+// own subgroups
+type Group struct {
+	Name         string  `json:"name"`
+	Abbreviation string  `json:"abbreviation"`
+	ShortName    string  `json:"shortName"`
+	Children     []Group `json:"children"`
+	Teams        []Team  `json:"teams"`
+}
+
+// GetConferences fetches the Division I conferences and their members
+func (c *Client) GetConferences(ctx context.Context) (map[string]string, error) {
+	url := baseURL + "/groups"
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	body, status, err := sources.ConditionalGet(ctx, c.httpClient, c.config.HTTPCache, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch conferences: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("ESPN API returned status %d", status)
+	}
+
+	var groupsResp GroupsResponse
+	if err := json.Unmarshal(body, &groupsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse conferences response: %w", err)
+	}
+
+	conferences := make(map[string]string)
+	var walk func(groups []Group)
+	walk = func(groups []Group) {
+		for _, g := range groups {
+			name := g.ShortName
+			if name == "" {
+				name = g.Name
+			}
+			for _, team := range g.Teams {
+				conferences[team.ID] = name
+			}
+			walk(g.Children)
+		}
+	}
+	walk(groupsResp.Groups)
+	return conferences, nil
+}
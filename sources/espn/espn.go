@@ -0,0 +1,280 @@
+// Package espn fetches men's college basketball scoreboards from ESPN's
+// public site API.
+package espn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// init registers the client as the "espn" source
+func init() {
+	sources.Register("espn", DefaultConfig(), func(config sources.Config) sources.Source {
+		return NewClient(config)
+	})
+}
+
+// DefaultConfig returns the default request settings for the ESPN API
+func DefaultConfig() sources.Config {
+	return sources.Config{
+		Concurrency:       10,
+		RequestsPerSecond: 20,
+		Burst:             10,
+	}
+}
+
+const (
+	baseURL = "https://site.api.espn.com/apis/site/v2/sports/basketball/mens-college-basketball"
+)
+
+// Client handles requests to ESPN's undocumented API
+type Client struct {
+	httpClient *http.Client
+	config     sources.Config
+	limiter    *sources.RateLimiter
+}
+
+// NewClient creates a new ESPN API client
+func NewClient(config sources.Config) *Client {
+	if config.Concurrency < 1 {
+		config.Concurrency = 1
+	}
+	return &Client{
+		httpClient: sources.NewHTTPClient(config),
+		config:     config,
+		limiter:    sources.NewRateLimiter(config.RequestsPerSecond, config.Burst),
+	}
+}
+
+// ScoreboardResponse represents the top-level scoreboard response
+type ScoreboardResponse struct {
+	Events []Event `json:"events"`
+}
+
+// Event represents a single game event
+type Event struct {
+	ID           string        `json:"id"`
+	Date         string        `json:"date"`
+	Name         string        `json:"name"`
+	ShortName    string        `json:"shortName"`
+	Competitions []Competition `json:"competitions"`
+	Status       Status        `json:"status"`
+}
+
+// Competition represents the competition details
+type Competition struct {
+	ID          string       `json:"id"`
+	Date        string       `json:"date"`
+	Attendance  int          `json:"attendance"`
+	NeutralSite bool         `json:"neutralSite"`
+	Competitors []Competitor `json:"competitors"`
+	Status      Status       `json:"status"`
+}
+
+// Competitor represents a team in the competition
+type Competitor struct {
+	ID       string `json:"id"`
+	HomeAway string `json:"homeAway"`
+	Winner   bool   `json:"winner"`
+	Team     Team   `json:"team"`
+	Score    string `json:"score"`
+}
+
+// Team represents team details
+type Team struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Abbreviation     string `json:"abbreviation"`
+	DisplayName      string `json:"displayName"`
+	ShortDisplayName string `json:"shortDisplayName"`
+	Location         string `json:"location"`
+}
+
+// Status represents the game status
+type Status struct {
+	Clock        float64    `json:"clock"`
+	DisplayClock string     `json:"displayClock"`
+	Period       int        `json:"period"`
+	Type         StatusType `json:"type"`
+}
+
+// StatusType represents the status type details
+type StatusType struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	Completed   bool   `json:"completed"`
+	Description string `json:"description"`
+}
+
+// gameStatus maps ESPN's status type onto a Game status
+func gameStatus(t StatusType) string {
+	switch strings.ToUpper(t.Name) {
+	case "STATUS_FORFEIT":
+		return elo.StatusForfeit
+	case "STATUS_CANCELED", "STATUS_CANCELLED":
+		return elo.StatusCancelled
+	case "STATUS_POSTPONED", "STATUS_SUSPENDED", "STATUS_DELAYED":
+		return elo.StatusPostponed
+	case "STATUS_NO_CONTEST", "STATUS_ABANDONED":
+		return elo.StatusNoContest
+	}
+	return sources.DetailStatus(t.Description)
+}
+
+// GetScoreboard fetches games for a specific date (format: YYYYMMDD)
+func (c *Client) GetScoreboard(ctx context.Context, date string) ([]elo.Game, error) {
+	url := fmt.Sprintf("%s/scoreboard?dates=%s&limit=500", baseURL, date)
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	body, status, err := sources.ConditionalGet(ctx, c.httpClient, c.config.HTTPCache, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch scoreboard: %w", err)
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("ESPN API returned status %d", status)
+	}
+
+	var scoreboardResp ScoreboardResponse
+	if err := json.Unmarshal(body, &scoreboardResp); err != nil {
+		return nil, fmt.Errorf("failed to parse scoreboard response: %w", err)
+	}
+
+	day, _ := time.Parse("20060102", date)
+	return c.parseEvents(scoreboardResp.Events, day), nil
+}
+
+// GetDate fetches games for a calendar date
+func (c *Client) GetDate(ctx context.Context, date time.Time) ([]elo.Game, error) {
+	return c.GetScoreboard(ctx, date.Format("20060102"))
+}
+
+// Workers returns the number of parallel requests used for range fetches
+func (c *Client) Workers() int {
+	return c.config.Concurrency
+}
+
+// GetScoreboardRange fetches games for a date range using parallel requests.
+// Dates that still fail after a retry pass are returned alongside the games.
+func (c *Client) GetScoreboardRange(ctx context.Context, startDate, endDate time.Time) ([]elo.Game, []time.Time, error) {
+	dates := sources.DatesBetween(startDate, endDate)
+	gamesByDate, failed, err := sources.FetchDates(ctx, dates, c.config.Concurrency, c.GetDate, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sources.CombineDates(dates, gamesByDate), failed, nil
+}
+
+// GetSeason fetches all games for a season (November to April), along with
+// any dates that could not be fetched. A season that hasn't started has no
+// dates and returns no games.
+func (c *Client) GetSeason(ctx context.Context, year int) ([]elo.Game, []time.Time, error) {
+	dates := sources.SeasonDates(year)
+	if len(dates) == 0 {
+		return nil, nil, nil
+	}
+	gamesByDate, failed, err := sources.FetchDates(ctx, dates, c.config.Concurrency, c.GetDate, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sources.CombineDates(dates, gamesByDate), failed, nil
+}
+
+// dateLayouts are the forms ESPN writes start times in: UTC, usually
+// without seconds
+var dateLayouts = []string{"2006-01-02T15:04Z07:00", time.RFC3339}
+
+// parseEvents converts ESPN events to our Game format. Games are dated by
+// their start time's calendar day in the configured zone, or the scoreboard
+// day when the start time can't be read.
+func (c *Client) parseEvents(events []Event, day time.Time) []elo.Game {
+	var games []elo.Game
+
+	for _, event := range events {
+		if len(event.Competitions) == 0 {
+			continue
+		}
+
+		comp := event.Competitions[0]
+		if len(comp.Competitors) != 2 {
+			continue
+		}
+
+		var homeTeam, awayTeam *Competitor
+		for i := range comp.Competitors {
+			if comp.Competitors[i].HomeAway == "home" {
+				homeTeam = &comp.Competitors[i]
+			} else {
+				awayTeam = &comp.Competitors[i]
+			}
+		}
+
+		if homeTeam == nil || awayTeam == nil {
+			continue
+		}
+
+		gameDate, startTime := day, time.Time{}
+		for _, layout := range dateLayouts {
+			if start, err := time.Parse(layout, event.Date); err == nil {
+				gameDate, startTime = sources.GameDay(start, c.config.Location), start.UTC()
+				break
+			}
+		}
+		homeScore, _ := strconv.Atoi(homeTeam.Score)
+		awayScore, _ := strconv.Atoi(awayTeam.Score)
+
+		game := elo.Game{
+			ID:          event.ID,
+			Date:        gameDate,
+			Start:       startTime,
+			HomeTeamID:  homeTeam.Team.ID,
+			HomeTeam:    homeTeam.Team.DisplayName,
+			AwayTeamID:  awayTeam.Team.ID,
+			AwayTeam:    awayTeam.Team.DisplayName,
+			HomeScore:   homeScore,
+			AwayScore:   awayScore,
+			NeutralSite: comp.NeutralSite,
+			Completed:   comp.Status.Type.Completed,
+			State:       comp.Status.Type.State,
+			Period:      comp.Status.Period,
+			Clock:       comp.Status.DisplayClock,
+			Status:      gameStatus(comp.Status.Type),
+		}
+		if game.Status != "" && game.Status != elo.StatusForfeit {
+			game.Completed = false
+		}
+
+		// Determine winner; a forfeit goes to the team ESPN awards it, whatever
+		// the score
+		if game.Completed {
+			switch {
+			case game.Status == elo.StatusForfeit && homeTeam.Winner != awayTeam.Winner:
+				if homeTeam.Winner {
+					game.WinnerID = homeTeam.Team.ID
+				} else {
+					game.WinnerID = awayTeam.Team.ID
+				}
+			case homeScore > awayScore:
+				game.WinnerID = homeTeam.Team.ID
+			case awayScore > homeScore:
+				game.WinnerID = awayTeam.Team.ID
+			}
+		}
+
+		games = append(games, game)
+	}
+
+	return games
+}
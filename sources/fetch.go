@@ -0,0 +1,141 @@
+package sources
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/elo"
+)
+
+// retryDelay is how long to wait before retrying dates that failed to fetch
+const retryDelay = 2 * time.Second
+
+// dateResult holds the result of fetching a single date
+type dateResult struct {
+	date  time.Time
+	games []elo.Game
+	err   error
+}
+
+// DatesBetween lists each day from start to end inclusive
+func DatesBetween(startDate, endDate time.Time) []time.Time {
+	var dates []time.Time
+	current := startDate
+	for !current.After(endDate) {
+		dates = append(dates, current)
+		current = current.AddDate(0, 0, 1)
+	}
+	return dates
+}
+
+// Tracker follows one pass of FetchDates as its dates finish
+type Tracker interface {
+	// Add records n more dates done, errs of which failed
+	Add(n, errs int)
+	// Finish marks the pass complete
+	Finish()
+}
+
+// FetchDates fetches each date using a pool of workers, then retries any
+// failures once. Games are returned keyed by date along with the dates that
+// still failed after the retry pass. track, if not nil, starts a Tracker for
+// each pass, labeled "Fetching" or "Retrying".
+func FetchDates(ctx context.Context, dates []time.Time, workers int, fetch func(context.Context, time.Time) ([]elo.Game, error), track func(label string, total int) Tracker) (map[time.Time][]elo.Game, []time.Time, error) {
+	pass := func(label string, dates []time.Time, gamesByDate map[time.Time][]elo.Game) []time.Time {
+		var bar Tracker
+		if track != nil {
+			bar = track(label, len(dates))
+		}
+		return fetchDatesPass(ctx, dates, workers, fetch, gamesByDate, bar)
+	}
+	gamesByDate := make(map[time.Time][]elo.Game)
+	failed := pass("Fetching", dates, gamesByDate)
+
+	// Retry pass for transient errors
+	if len(failed) > 0 && ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-time.After(retryDelay):
+			failed = pass("Retrying", failed, gamesByDate)
+		}
+	}
+
+	// A cancelled fetch is incomplete, so don't return partial results
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	return gamesByDate, failed, nil
+}
+
+// CombineDates joins per-date games in chronological order
+func CombineDates(dates []time.Time, gamesByDate map[time.Time][]elo.Game) []elo.Game {
+	var allGames []elo.Game
+	for _, date := range dates {
+		if games, ok := gamesByDate[date]; ok {
+			allGames = append(allGames, games...)
+		}
+	}
+	return allGames
+}
+
+// fetchDatesPass runs one parallel pass over dates, storing successes in
+// gamesByDate and returning the dates that failed in chronological order
+func fetchDatesPass(ctx context.Context, dates []time.Time, workers int, fetch func(context.Context, time.Time) ([]elo.Game, error), gamesByDate map[time.Time][]elo.Game, bar Tracker) []time.Time {
+	// Channel for dates to process
+	dateChan := make(chan time.Time, len(dates))
+	for _, d := range dates {
+		dateChan <- d
+	}
+	close(dateChan)
+
+	// Channel for results
+	resultChan := make(chan dateResult, len(dates))
+
+	// Start worker goroutines
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for date := range dateChan {
+				if ctx.Err() != nil {
+					return
+				}
+				games, err := fetch(ctx, date)
+				resultChan <- dateResult{date: date, games: games, err: err}
+			}
+		}()
+	}
+
+	// Close result channel when all workers done
+	go func() {
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	failedSet := make(map[time.Time]bool)
+	for result := range resultChan {
+		errs := 0
+		if result.err != nil {
+			failedSet[result.date] = true
+			errs = 1
+		} else {
+			gamesByDate[result.date] = result.games
+		}
+		if bar != nil {
+			bar.Add(1, errs)
+		}
+	}
+	if bar != nil {
+		bar.Finish()
+	}
+
+	var failed []time.Time
+	for _, date := range dates {
+		if failedSet[date] {
+			failed = append(failed, date)
+		}
+	}
+	return failed
+}
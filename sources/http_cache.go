@@ -0,0 +1,136 @@
+package sources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/internal/gzfile"
+)
+
+// HTTPCacheEntry stores a response body with the validators needed to revalidate it
+type HTTPCacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+	Body         []byte    `json:"body"`
+}
+
+// HTTPCache keeps the last response for each URL so refetches can be made
+// conditional and unchanged days cost a 304 instead of a full download
+type HTTPCache struct {
+	dir string
+}
+
+// NewHTTPCache creates an HTTP cache stored in dir
+func NewHTTPCache(dir string) (*HTTPCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create HTTP cache directory: %w", err)
+	}
+	return &HTTPCache{dir: dir}, nil
+}
+
+// entryFile returns the path to the gzip-compressed cache file for a URL
+func (c *HTTPCache) entryFile(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json.gz")
+}
+
+// Get returns the cached response for a URL, if any
+func (c *HTTPCache) Get(url string) (*HTTPCacheEntry, bool) {
+	path := c.entryFile(url)
+	data, err := gzfile.ReadFileOr(path, strings.TrimSuffix(path, ".gz"))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry HTTPCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Put stores a response for a URL. Responses without validators are skipped
+// since they could never be revalidated.
+func (c *HTTPCache) Put(entry HTTPCacheEntry) error {
+	if entry.ETag == "" && entry.LastModified == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal HTTP cache entry: %w", err)
+	}
+	path := c.entryFile(entry.URL)
+	if err := gzfile.WriteFile(path, data); err != nil {
+		return fmt.Errorf("failed to write HTTP cache file: %w", err)
+	}
+	os.Remove(strings.TrimSuffix(path, ".gz"))
+	return nil
+}
+
+// ConditionalGet fetches a URL, revalidating any cached copy with
+// If-None-Match/If-Modified-Since. A 304 response is served from the cache and
+// reported as 200. The cache may be nil.
+func ConditionalGet(ctx context.Context, client *http.Client, cache *HTTPCache, url string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	var cached *HTTPCacheEntry
+	if cache != nil {
+		if entry, ok := cache.Get(url); ok {
+			cached = entry
+			if entry.ETag != "" {
+				req.Header.Set("If-None-Match", entry.ETag)
+			}
+			if entry.LastModified != "" {
+				req.Header.Set("If-Modified-Since", entry.LastModified)
+			}
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.Body, http.StatusOK, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if cache != nil {
+		entry := HTTPCacheEntry{
+			URL:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			FetchedAt:    time.Now(),
+			Body:         body,
+		}
+		if err := cache.Put(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not cache response: %v\n", err)
+		}
+	}
+
+	return body, resp.StatusCode, nil
+}
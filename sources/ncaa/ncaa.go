@@ -0,0 +1,245 @@
+// Package ncaa fetches men's college basketball scoreboards from ncaa.com
+// through the ncaa-api.henrygd.me proxy.
+package ncaa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/elo"
+	"github.com/corykiser/NCAA-Bayes-ELO/sources"
+)
+
+// init registers the client as the "ncaa" source
+func init() {
+	sources.Register("ncaa", DefaultConfig(), func(config sources.Config) sources.Source {
+		return NewClient(config)
+	})
+}
+
+// DefaultConfig returns the default request settings for the NCAA API,
+// which limits clients to 5 requests per second
+func DefaultConfig() sources.Config {
+	return sources.Config{
+		Concurrency:       5,
+		RequestsPerSecond: 5,
+		Burst:             1,
+	}
+}
+
+const (
+	// Public instance of henrygd's NCAA API wrapper
+	baseURL = "https://ncaa-api.henrygd.me"
+)
+
+// Client handles requests to the NCAA API wrapper
+type Client struct {
+	httpClient *http.Client
+	config     sources.Config
+	limiter    *sources.RateLimiter
+}
+
+// NewClient creates a new NCAA API client
+func NewClient(config sources.Config) *Client {
+	if config.Concurrency < 1 {
+		config.Concurrency = 1
+	}
+	return &Client{
+		httpClient: sources.NewHTTPClient(config),
+		config:     config,
+		limiter:    sources.NewRateLimiter(config.RequestsPerSecond, config.Burst),
+	}
+}
+
+// ScoreboardResponse represents the scoreboard response
+type ScoreboardResponse struct {
+	Games []ScoreboardGame `json:"games"`
+}
+
+// ScoreboardGame represents a game from the NCAA API
+type ScoreboardGame struct {
+	Game GameDetails `json:"game"`
+}
+
+// GameDetails contains the game details
+type GameDetails struct {
+	GameID         string   `json:"gameID"`
+	StartDate      string   `json:"startDate"`
+	StartTime      string   `json:"startTime"`
+	StartTimeEpoch int64    `json:"startTimeEpoch"`
+	GameState      string   `json:"gameState"`
+	Home           TeamInfo `json:"home"`
+	Away           TeamInfo `json:"away"`
+	FinalMessage   string   `json:"finalMessage"`
+	CurrentPeriod  string   `json:"currentPeriod"`
+	ContestClock   string   `json:"contestClock"`
+}
+
+// TeamInfo represents team info in a game
+type TeamInfo struct {
+	Names  TeamNames `json:"names"`
+	Score  string    `json:"score"`
+	Winner bool      `json:"winner"`
+	TeamID string    `json:"teamId"`
+}
+
+// TeamNames contains various team name formats
+type TeamNames struct {
+	Char6 string `json:"char6"`
+	Short string `json:"short"`
+	Seo   string `json:"seo"`
+	Full  string `json:"full"`
+}
+
+// GetScoreboard fetches games for a specific date
+// Date format: YYYY/MM/DD
+func (c *Client) GetScoreboard(ctx context.Context, year, month, day int) ([]elo.Game, error) {
+	url := fmt.Sprintf("%s/scoreboard/basketball-men/d1/%d/%02d/%02d", baseURL, year, month, day)
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	body, status, err := sources.ConditionalGet(ctx, c.httpClient, c.config.HTTPCache, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch NCAA scoreboard: %w", err)
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("NCAA API returned status %d", status)
+	}
+
+	var scoreboardResp ScoreboardResponse
+	if err := json.Unmarshal(body, &scoreboardResp); err != nil {
+		return nil, fmt.Errorf("failed to parse NCAA scoreboard response: %w", err)
+	}
+
+	return c.parseGames(scoreboardResp.Games, year, month, day), nil
+}
+
+// GetDate fetches games for a calendar date
+func (c *Client) GetDate(ctx context.Context, date time.Time) ([]elo.Game, error) {
+	return c.GetScoreboard(ctx, date.Year(), int(date.Month()), date.Day())
+}
+
+// Workers returns the number of parallel requests used for range fetches
+func (c *Client) Workers() int {
+	return c.config.Concurrency
+}
+
+// GetScoreboardRange fetches games for a date range using parallel requests.
+// Dates that still fail after a retry pass are returned alongside the games.
+func (c *Client) GetScoreboardRange(ctx context.Context, startDate, endDate time.Time) ([]elo.Game, []time.Time, error) {
+	dates := sources.DatesBetween(startDate, endDate)
+	gamesByDate, failed, err := sources.FetchDates(ctx, dates, c.config.Concurrency, c.GetDate, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sources.CombineDates(dates, gamesByDate), failed, nil
+}
+
+// GetSeason fetches all games for a season (November to April), along with
+// any dates that could not be fetched. A season that hasn't started has no
+// dates and returns no games.
+func (c *Client) GetSeason(ctx context.Context, year int) ([]elo.Game, []time.Time, error) {
+	dates := sources.SeasonDates(year)
+	if len(dates) == 0 {
+		return nil, nil, nil
+	}
+	gamesByDate, failed, err := sources.FetchDates(ctx, dates, c.config.Concurrency, c.GetDate, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sources.CombineDates(dates, gamesByDate), failed, nil
+}
+
+// parseGames converts NCAA games to our Game format. Games with a start time
+// are dated by its calendar day in the configured zone, the rest by the
+// scoreboard day.
+func (c *Client) parseGames(ncaaGames []ScoreboardGame, year, month, day int) []elo.Game {
+	var games []elo.Game
+
+	for _, ng := range ncaaGames {
+		g := ng.Game
+
+		homeScore, _ := strconv.Atoi(g.Home.Score)
+		awayScore, _ := strconv.Atoi(g.Away.Score)
+		gameDate, startTime := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC), time.Time{}
+		if g.StartTimeEpoch > 0 {
+			startTime = time.Unix(g.StartTimeEpoch, 0).UTC()
+			gameDate = sources.GameDay(startTime, c.config.Location)
+		}
+
+		game := elo.Game{
+			ID:          g.GameID,
+			Date:        gameDate,
+			Start:       startTime,
+			HomeTeamID:  g.Home.TeamID,
+			HomeTeam:    g.Home.Names.Full,
+			AwayTeamID:  g.Away.TeamID,
+			AwayTeam:    g.Away.Names.Full,
+			HomeScore:   homeScore,
+			AwayScore:   awayScore,
+			NeutralSite: false, // NCAA API doesn't clearly indicate this
+			Completed:   g.GameState == "final",
+			State:       gameState(g.GameState),
+			Period:      parsePeriod(g.CurrentPeriod),
+			Clock:       g.ContestClock,
+			Status:      sources.DetailStatus(g.GameState + " " + g.FinalMessage),
+		}
+		if game.Status != "" && game.Status != elo.StatusForfeit {
+			game.Completed = false
+		}
+
+		if game.Completed {
+			if g.Home.Winner {
+				game.WinnerID = g.Home.TeamID
+			} else if g.Away.Winner {
+				game.WinnerID = g.Away.TeamID
+			}
+		}
+
+		games = append(games, game)
+	}
+
+	return games
+}
+
+// gameState maps the NCAA API's game state onto ESPN's pre/in/post states
+func gameState(state string) string {
+	switch strings.ToLower(state) {
+	case "live":
+		return "in"
+	case "final":
+		return "post"
+	default:
+		return "pre"
+	}
+}
+
+// parsePeriod converts the NCAA API's period label ("1ST", "2ND", "OT", "2OT")
+// into a period number, with overtimes following the two halves
+func parsePeriod(label string) int {
+	label = strings.ToUpper(strings.TrimSpace(label))
+	switch label {
+	case "", "FINAL":
+		return 0
+	case "1ST", "HALF":
+		return 1
+	case "2ND":
+		return 2
+	case "OT":
+		return 3
+	}
+	if strings.HasSuffix(label, "OT") {
+		if n, err := strconv.Atoi(strings.TrimSuffix(label, "OT")); err == nil {
+			return 2 + n
+		}
+	}
+	return 0
+}
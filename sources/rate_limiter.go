@@ -0,0 +1,76 @@
+package sources
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket shared by all of a client's workers, so the
+// request rate is enforced independently of how many requests run in parallel
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // Time to earn one token
+	burst    int           // Maximum tokens that can accumulate
+	tokens   float64
+	last     time.Time
+}
+
+// NewRateLimiter creates a limiter allowing perSecond requests on average with
+// bursts of up to burst requests. A non-positive rate disables limiting.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	l := &RateLimiter{
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+	if perSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return l
+}
+
+// Wait blocks until a request is allowed or the context is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.interval == 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+
+	// Refill tokens earned since the last call, up to the burst size
+	l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+	l.last = now
+
+	// Take a token; a negative balance is a reservation to wait out
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens * float64(l.interval))
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give back the unused token
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,71 @@
+package sources
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory creates a source with the given request settings
+type Factory func(config Config) Source
+
+// registration is a named source's constructor and default settings
+type registration struct {
+	newSource Factory
+	defaults  Config
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]registration)
+)
+
+// Register makes a source available by name, with the request settings it
+// uses unless overridden. Source packages call it from init, so importing a
+// package (even as _) is enough to select its source by name. Register
+// panics if the name is already taken or the factory is nil.
+func Register(name string, defaults Config, newSource Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if newSource == nil {
+		panic("sources: Register factory is nil for " + name)
+	}
+	if _, dup := registry[name]; dup {
+		panic("sources: Register called twice for " + name)
+	}
+	registry[name] = registration{newSource: newSource, defaults: defaults}
+}
+
+// DefaultConfig returns a registered source's default request settings
+func DefaultConfig(name string) (Config, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	reg, ok := registry[name]
+	if !ok {
+		return Config{}, fmt.Errorf("unknown data source: %s", name)
+	}
+	return reg.defaults, nil
+}
+
+// New creates a registered source by name
+func New(name string, config Config) (Source, error) {
+	registryMu.RLock()
+	reg, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown data source: %s", name)
+	}
+	return reg.newSource(config), nil
+}
+
+// Names lists the registered sources in alphabetical order
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
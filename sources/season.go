@@ -0,0 +1,58 @@
+package sources
+
+import (
+	"fmt"
+	"time"
+)
+
+// SeasonDates lists the dates of a season (November 1 to April 15), ending
+// today for a season still in progress. The "year" is the spring year
+// (e.g., 2025 season = Nov 2024 - Apr 2025).
+func SeasonDates(year int) []time.Time {
+	return DateRange{}.SeasonDates(year)
+}
+
+// DateRange overrides the start and end of a season, either of which may
+// fall outside the usual November to April window. Zero values keep the
+// season's own bounds.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// SeasonDates lists the dates of a season within the range, ending today
+// if the range runs past it
+func (r DateRange) SeasonDates(year int) []time.Time {
+	startDate := time.Date(year-1, time.November, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(year, time.April, 15, 0, 0, 0, 0, time.UTC)
+	if !r.Start.IsZero() {
+		startDate = r.Start
+	}
+	if !r.End.IsZero() {
+		endDate = r.End
+	}
+
+	// If we're asking for current/future season, end at today
+	if endDate.After(time.Now()) {
+		endDate = time.Now()
+	}
+
+	return DatesBetween(startDate, endDate)
+}
+
+// Contains reports whether a YYYY-MM-DD date falls within the range
+func (r DateRange) Contains(date string) bool {
+	return (r.Start.IsZero() || date >= r.Start.Format("2006-01-02")) &&
+		(r.End.IsZero() || date <= r.End.Format("2006-01-02"))
+}
+
+// String describes the range for messages
+func (r DateRange) String() string {
+	switch {
+	case r.Start.IsZero():
+		return "through " + r.End.Format("2006-01-02")
+	case r.End.IsZero():
+		return "from " + r.Start.Format("2006-01-02")
+	}
+	return fmt.Sprintf("from %s to %s", r.Start.Format("2006-01-02"), r.End.Format("2006-01-02"))
+}
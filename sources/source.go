@@ -0,0 +1,44 @@
+// Package sources defines the interface game data providers implement and
+// the request plumbing they share: HTTP clients, rate limiting, conditional
+// requests, and parallel date range fetches. Providers live in subpackages.
+package sources
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/elo"
+)
+
+// ConferenceSource is a Source that can also say which conference each
+// team plays in
+type ConferenceSource interface {
+	// GetConferences maps team IDs to conference names
+	GetConferences(ctx context.Context) (map[string]string, error)
+}
+
+// DetailStatus reads a status from a free-text description like "Forfeit"
+// or "Canceled", as both sources use for unusual results
+func DetailStatus(detail string) string {
+	detail = strings.ToLower(detail)
+	switch {
+	case strings.Contains(detail, "forfeit"):
+		return elo.StatusForfeit
+	case strings.Contains(detail, "cancel"):
+		return elo.StatusCancelled
+	case strings.Contains(detail, "postpone"), strings.Contains(detail, "suspend"):
+		return elo.StatusPostponed
+	case strings.Contains(detail, "no contest"):
+		return elo.StatusNoContest
+	}
+	return ""
+}
+
+// Source is a provider of daily scoreboards
+type Source interface {
+	// GetDate fetches all games scheduled on a calendar date
+	GetDate(ctx context.Context, date time.Time) ([]elo.Game, error)
+	// Workers is the number of dates that may be fetched in parallel
+	Workers() int
+}
@@ -0,0 +1,114 @@
+package sources
+
+import (
+	"context"
+	"time"
+
+	"github.com/corykiser/NCAA-Bayes-ELO/elo"
+)
+
+// Stream fetches a source's dates and sends their games in date order; see
+// StreamDates
+func Stream(ctx context.Context, source Source, dates []time.Time) (<-chan elo.Game, func() ([]time.Time, error)) {
+	return StreamDates(ctx, dates, source.Workers(), source.GetDate)
+}
+
+// StreamDates fetches dates using a pool of workers and sends their games on
+// the returned channel in date order, each date as soon as it and every
+// earlier date are in, so a consumer like BayesianELO.ProcessStream can rate
+// while later dates are still being fetched. Workers run only a few dates
+// ahead of the consumer, so the whole range is never held in memory. A date
+// that fails is retried once and skipped if it fails again.
+//
+// The channel is closed when every date is done or the context is done;
+// wait then returns the dates that were skipped, or the context's error. A
+// consumer that stops reading early must cancel the context.
+func StreamDates(ctx context.Context, dates []time.Time, workers int, fetch func(context.Context, time.Time) ([]elo.Game, error)) (<-chan elo.Game, func() ([]time.Time, error)) {
+	if workers < 1 {
+		workers = 1
+	}
+	out := make(chan elo.Game)
+	done := make(chan struct{})
+	var failed []time.Time
+	var err error
+
+	go func() {
+		defer close(done)
+		defer close(out)
+		failed, err = streamDates(ctx, dates, workers, fetch, out)
+	}()
+
+	wait := func() ([]time.Time, error) {
+		<-done
+		return failed, err
+	}
+	return out, wait
+}
+
+// streamDates runs StreamDates, sending games on out and returning the dates
+// that failed
+func streamDates(ctx context.Context, dates []time.Time, workers int, fetch func(context.Context, time.Time) ([]elo.Game, error), out chan<- elo.Game) ([]time.Time, error) {
+	results := make([]chan dateResult, len(dates))
+	for i := range results {
+		results[i] = make(chan dateResult, 1)
+	}
+
+	// Dates are handed out in order, each taking a slot in the window until
+	// its games are sent, so the oldest unsent date is always being fetched
+	window := make(chan struct{}, 2*workers)
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := range dates {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			jobs <- i
+		}
+	}()
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range jobs {
+				results[i] <- fetchWithRetry(ctx, dates[i], fetch)
+			}
+		}()
+	}
+
+	var failed []time.Time
+	for i, date := range dates {
+		var result dateResult
+		select {
+		case result = <-results[i]:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if result.err != nil {
+			failed = append(failed, date)
+		}
+		for _, game := range result.games {
+			select {
+			case out <- game:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		<-window
+	}
+	return failed, nil
+}
+
+// fetchWithRetry fetches a date, retrying once after a pause if it fails
+func fetchWithRetry(ctx context.Context, date time.Time, fetch func(context.Context, time.Time) ([]elo.Game, error)) dateResult {
+	games, err := fetch(ctx, date)
+	if err != nil && ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-time.After(retryDelay):
+			games, err = fetch(ctx, date)
+		}
+	}
+	return dateResult{date: date, games: games, err: err}
+}